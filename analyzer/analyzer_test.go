@@ -185,3 +185,20 @@ func contains(classes []string, class string) bool {
 	}
 	return false
 }
+
+// writeProject writes files, their paths relative to the project and their
+// contents, to a new temporary directory and returns it
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
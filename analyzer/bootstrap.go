@@ -0,0 +1,543 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BootstrapKind says how a Bootstrap class carries over to Tailwind
+type BootstrapKind string
+
+const (
+	// BootstrapExact classes have Tailwind classes doing the same, the
+	// codemod rewrites them
+	BootstrapExact BootstrapKind = "exact"
+	// BootstrapApproximate classes have Tailwind classes that come close,
+	// a person has to decide
+	BootstrapApproximate BootstrapKind = "approximate"
+	// BootstrapComponent classes belong to a Bootstrap component like btn
+	// or card, which needs a Tailwind component of its own
+	BootstrapComponent BootstrapKind = "component"
+)
+
+// BootstrapMapping is what a Bootstrap class becomes in Tailwind
+type BootstrapMapping struct {
+	Kind BootstrapKind `json:"kind"`
+	To   []string      `json:"to,omitempty"`
+}
+
+// bootstrapBreakpoints map the Bootstrap breakpoint infixes to the
+// Tailwind variants of the same name, which start at other widths
+var bootstrapBreakpoints = map[string]string{"sm": "sm", "md": "md", "lg": "lg", "xl": "xl", "xxl": "2xl"}
+
+// bootstrapSpacers map the Bootstrap spacing scale to Tailwind's,
+// Bootstrap's 3 is 1rem like Tailwind's 4
+var bootstrapSpacers = map[string]string{"0": "0", "1": "1", "2": "2", "3": "4", "4": "6", "5": "12", "auto": "auto"}
+
+// bootstrapExact are the classes with Tailwind classes doing the same
+var bootstrapExact = map[string]string{
+	"d-none":                       "hidden",
+	"d-inline":                     "inline",
+	"d-inline-block":               "inline-block",
+	"d-block":                      "block",
+	"d-grid":                       "grid",
+	"d-inline-grid":                "inline-grid",
+	"d-table":                      "table",
+	"d-table-row":                  "table-row",
+	"d-table-cell":                 "table-cell",
+	"d-flex":                       "flex",
+	"d-inline-flex":                "inline-flex",
+	"flex-row":                     "flex-row",
+	"flex-row-reverse":             "flex-row-reverse",
+	"flex-column":                  "flex-col",
+	"flex-column-reverse":          "flex-col-reverse",
+	"flex-wrap":                    "flex-wrap",
+	"flex-nowrap":                  "flex-nowrap",
+	"flex-wrap-reverse":            "flex-wrap-reverse",
+	"flex-fill":                    "flex-auto",
+	"flex-grow-0":                  "grow-0",
+	"flex-grow-1":                  "grow",
+	"flex-shrink-0":                "shrink-0",
+	"flex-shrink-1":                "shrink",
+	"justify-content-start":        "justify-start",
+	"justify-content-end":          "justify-end",
+	"justify-content-center":       "justify-center",
+	"justify-content-between":      "justify-between",
+	"justify-content-around":       "justify-around",
+	"justify-content-evenly":       "justify-evenly",
+	"align-items-start":            "items-start",
+	"align-items-end":              "items-end",
+	"align-items-center":           "items-center",
+	"align-items-baseline":         "items-baseline",
+	"align-items-stretch":          "items-stretch",
+	"align-self-auto":              "self-auto",
+	"align-self-start":             "self-start",
+	"align-self-end":               "self-end",
+	"align-self-center":            "self-center",
+	"align-self-baseline":          "self-baseline",
+	"align-self-stretch":           "self-stretch",
+	"align-content-start":          "content-start",
+	"align-content-end":            "content-end",
+	"align-content-center":         "content-center",
+	"align-content-between":        "content-between",
+	"align-content-around":         "content-around",
+	"align-content-stretch":        "content-stretch",
+	"order-first":                  "order-first",
+	"order-last":                   "order-last",
+	"float-start":                  "float-left",
+	"float-end":                    "float-right",
+	"float-none":                   "float-none",
+	"text-start":                   "text-left",
+	"text-end":                     "text-right",
+	"text-center":                  "text-center",
+	"text-lowercase":               "lowercase",
+	"text-uppercase":               "uppercase",
+	"text-capitalize":              "capitalize",
+	"text-wrap":                    "text-wrap",
+	"text-nowrap":                  "text-nowrap",
+	"text-truncate":                "truncate",
+	"text-break":                   "break-words",
+	"fw-light":                     "font-light",
+	"fw-normal":                    "font-normal",
+	"fw-medium":                    "font-medium",
+	"fw-semibold":                  "font-semibold",
+	"fw-bold":                      "font-bold",
+	"fst-italic":                   "italic",
+	"fst-normal":                   "not-italic",
+	"text-decoration-none":         "no-underline",
+	"text-decoration-underline":    "underline",
+	"text-decoration-line-through": "line-through",
+	"lh-1":                         "leading-none",
+	"lh-sm":                        "leading-tight",
+	"lh-base":                      "leading-normal",
+	"lh-lg":                        "leading-loose",
+	"text-white":                   "text-white",
+	"text-black":                   "text-black",
+	"bg-white":                     "bg-white",
+	"bg-black":                     "bg-black",
+	"bg-transparent":               "bg-transparent",
+	"w-25":                         "w-1/4",
+	"w-50":                         "w-1/2",
+	"w-75":                         "w-3/4",
+	"w-100":                        "w-full",
+	"w-auto":                       "w-auto",
+	"h-25":                         "h-1/4",
+	"h-50":                         "h-1/2",
+	"h-75":                         "h-3/4",
+	"h-100":                        "h-full",
+	"h-auto":                       "h-auto",
+	"mw-100":                       "max-w-full",
+	"mh-100":                       "max-h-full",
+	"vw-100":                       "w-screen",
+	"vh-100":                       "h-screen",
+	"min-vw-100":                   "min-w-[100vw]",
+	"min-vh-100":                   "min-h-screen",
+	"position-static":              "static",
+	"position-relative":            "relative",
+	"position-absolute":            "absolute",
+	"position-fixed":               "fixed",
+	"position-sticky":              "sticky",
+	"top-50":                       "top-1/2",
+	"top-100":                      "top-full",
+	"bottom-50":                    "bottom-1/2",
+	"bottom-100":                   "bottom-full",
+	"start-50":                     "left-1/2",
+	"start-100":                    "left-full",
+	"start-0":                      "left-0",
+	"end-50":                       "right-1/2",
+	"end-100":                      "right-full",
+	"end-0":                        "right-0",
+	"translate-middle":             "-translate-x-1/2 -translate-y-1/2",
+	"translate-middle-x":           "-translate-x-1/2",
+	"translate-middle-y":           "-translate-y-1/2",
+	"fixed-top":                    "fixed top-0 right-0 left-0 z-[1030]",
+	"fixed-bottom":                 "fixed right-0 bottom-0 left-0 z-[1030]",
+	"sticky-top":                   "sticky top-0 z-[1020]",
+	"border-top":                   "border-t",
+	"border-end":                   "border-r",
+	"border-bottom":                "border-b",
+	"border-start":                 "border-l",
+	"border-top-0":                 "border-t-0",
+	"border-end-0":                 "border-r-0",
+	"border-bottom-0":              "border-b-0",
+	"border-start-0":               "border-l-0",
+	"rounded":                      "rounded-md",
+	"rounded-0":                    "rounded-none",
+	"rounded-1":                    "rounded",
+	"rounded-2":                    "rounded-md",
+	"rounded-3":                    "rounded-lg",
+	"rounded-4":                    "rounded-2xl",
+	"rounded-5":                    "rounded-[2rem]",
+	"rounded-circle":               "rounded-full",
+	"rounded-pill":                 "rounded-full",
+	"rounded-top":                  "rounded-t-md",
+	"rounded-end":                  "rounded-r-md",
+	"rounded-bottom":               "rounded-b-md",
+	"rounded-start":                "rounded-l-md",
+	"user-select-all":              "select-all",
+	"user-select-auto":             "select-auto",
+	"user-select-none":             "select-none",
+	"pe-none":                      "pointer-events-none",
+	"pe-auto":                      "pointer-events-auto",
+	"visually-hidden":              "sr-only",
+	"img-fluid":                    "max-w-full h-auto",
+	"col-auto":                     "w-auto flex-none",
+	"object-fit-contain":           "object-contain",
+	"object-fit-cover":             "object-cover",
+	"object-fit-fill":              "object-fill",
+	"object-fit-scale":             "object-scale-down",
+	"object-fit-none":              "object-none",
+	"overflow-x-auto":              "overflow-x-auto",
+	"overflow-y-auto":              "overflow-y-auto",
+}
+
+// bootstrapApproximate are the classes with no exact counterpart and the
+// Tailwind classes closest to them
+var bootstrapApproximate = map[string]string{
+	"container":                 "container mx-auto px-3",
+	"container-fluid":           "w-full px-3",
+	"row":                       "flex flex-wrap -mx-3",
+	"col":                       "flex-1",
+	"shadow-sm":                 "shadow-sm",
+	"shadow":                    "shadow-md",
+	"shadow-lg":                 "shadow-xl",
+	"shadow-none":               "shadow-none",
+	"fs-1":                      "text-4xl",
+	"fs-2":                      "text-3xl",
+	"fs-3":                      "text-2xl",
+	"fs-4":                      "text-xl",
+	"fs-5":                      "text-lg",
+	"fs-6":                      "text-base",
+	"display-1":                 "text-8xl font-light",
+	"display-2":                 "text-7xl font-light",
+	"display-3":                 "text-6xl font-light",
+	"display-4":                 "text-5xl font-light",
+	"display-5":                 "text-4xl font-light",
+	"display-6":                 "text-3xl font-light",
+	"lead":                      "text-xl font-light",
+	"small":                     "text-sm",
+	"fw-bolder":                 "font-extrabold",
+	"fw-lighter":                "font-extralight",
+	"text-muted":                "text-gray-500",
+	"text-body":                 "text-gray-900",
+	"text-body-secondary":       "text-gray-500",
+	"border":                    "border border-gray-300",
+	"visually-hidden-focusable": "sr-only focus:not-sr-only",
+	"clearfix":                  "after:content-[''] after:table after:clear-both",
+	"stretched-link":            "after:absolute after:inset-0 after:z-[1] after:content-['']",
+	"vstack":                    "flex flex-col",
+	"hstack":                    "flex flex-row items-center",
+	"vr":                        "inline-block self-stretch w-px bg-current opacity-25",
+}
+
+// bootstrapThemeColors need a color of the project's Tailwind theme
+var bootstrapThemeColors = []string{"primary", "secondary", "success", "danger", "warning", "info", "light", "dark"}
+
+// bootstrapComponents are Bootstrap's components and the classes they're
+// made of, {color} stands for a theme color and {bp} for a breakpoint infix.
+// Only these exact classes count, Tailwind has placeholder-gray-400 and
+// table-auto too
+var bootstrapComponents = []struct{ root, pattern string }{
+	{"accordion", `accordion(-(item|header|button|collapse|body|flush))?`},
+	{"alert", `alert(-({color}|dismissible|heading|link))?`},
+	{"badge", `badge`},
+	{"breadcrumb", `breadcrumb(-item)?`},
+	{"btn-group", `btn-group(-(lg|sm|vertical))?|btn-toolbar`},
+	{"btn", `btn(-((outline-)?{color}|link|lg|sm|close|close-white|check))?`},
+	{"card", `card(-(body|title|subtitle|text|link|header|footer|img|img-top|img-bottom|img-overlay|group|header-tabs|header-pills))?`},
+	{"carousel", `carousel(-(inner|item|item-next|item-prev|item-start|item-end|control-prev|control-next|control-prev-icon|control-next-icon|indicators|caption|fade|dark))?`},
+	{"dropdown", `dropdown(-(toggle|toggle-split|menu|menu-end|menu-start|menu-dark|menu-{bp}-end|menu-{bp}-start|item|item-text|divider|header|center))?|dropup(-center)?|dropstart|dropend`},
+	{"figure", `figure(-(img|caption))`},
+	{"form", `form-(control(-(lg|sm|plaintext|color))?|label|text|select(-(lg|sm))?|check(-(input|label|inline|reverse))?|switch|range|floating)|col-form-label(-(lg|sm))?`},
+	{"input-group", `input-group(-(text|lg|sm))?`},
+	{"validation", `(in)?valid-(feedback|tooltip)`},
+	{"list-group", `list-group(-(item|item-action|item-{color}|flush|numbered|horizontal|horizontal-{bp}))?`},
+	{"modal", `modal(-(dialog|dialog-centered|dialog-scrollable|content|header|title|body|footer|sm|lg|xl|fullscreen|fullscreen-{bp}-down|backdrop))?`},
+	{"nav", `nav(-(item|link|tabs|pills|underline|fill|justified))?`},
+	{"navbar", `navbar(-(brand|nav|nav-scroll|toggler|toggler-icon|collapse|text|expand|expand-{bp}|dark|light))?`},
+	{"offcanvas", `offcanvas(-(start|end|top|bottom|header|title|body|backdrop|{bp}))?`},
+	{"pagination", `pagination(-(lg|sm))?|page-(item|link)`},
+	{"placeholder", `placeholder-(glow|wave|xs|sm|lg)`},
+	{"popover", `popover(-(header|body|arrow))?`},
+	{"progress", `progress(-(bar|bar-striped|bar-animated|stacked))?`},
+	{"spinner", `spinner-(border|grow)(-sm)?`},
+	{"tab", `tab-(content|pane)`},
+	{"table", `table-({color}|striped|striped-columns|hover|active|bordered|borderless|sm|responsive|responsive-{bp}|group-divider)|caption-top`},
+	{"toast", `toast(-(header|body|container))?`},
+	{"tooltip", `tooltip(-(inner|arrow))?`},
+	{"typography", `blockquote(-footer)?|initialism|list-inline(-item)?|list-unstyled|h[1-6]|lead`},
+	{"ratio", `ratio(-(1x1|4x3|16x9|21x9))?`},
+	{"icon-link", `icon-link(-hover)?`},
+	{"focus-ring", `focus-ring(-{color})?`},
+}
+
+// bootstrapStates are the classes Bootstrap's components and scripts use
+// that are too generic to be Bootstrap's on their own, they only count on
+// an element with a component class
+var bootstrapStates = toSet("active", "disabled", "show", "fade", "collapse", "collapsing", "collapse-horizontal",
+	"is-valid", "is-invalid", "was-validated", "placeholder", "table", "mark", "hiding", "showing")
+
+var bootstrapComponentPatterns = func() []*regexp.Regexp {
+	colors := strings.Join(bootstrapThemeColors, "|")
+	breakpoints := "sm|md|lg|xl|xxl"
+	var patterns []*regexp.Regexp
+	for _, component := range bootstrapComponents {
+		pattern := strings.NewReplacer("{color}", "("+colors+")", "{bp}", "("+breakpoints+")").Replace(component.pattern)
+		patterns = append(patterns, regexp.MustCompile("^(?:"+pattern+")$"))
+	}
+	return patterns
+}()
+
+var (
+	bootstrapSpacing = regexp.MustCompile(`^(m|p)([tbsexy]?)-(0|1|2|3|4|5|auto)$`)
+	bootstrapGap     = regexp.MustCompile(`^(gap|row-gap|column-gap)-(0|1|2|3|4|5)$`)
+	bootstrapColumn  = regexp.MustCompile(`^(col|offset|order)-(\d+)$`)
+	bootstrapColor   = regexp.MustCompile(`^(text|bg|border|link|text-bg)-(` + strings.Join(bootstrapThemeColors, "|") + `)(-subtle|-emphasis)?$`)
+	bootstrapGutter  = regexp.MustCompile(`^(g|gx|gy)-(0|1|2|3|4|5)$`)
+	bootstrapRowCols = regexp.MustCompile(`^row-cols-(\d+|auto)$`)
+	// bootstrapResponsive are the families Bootstrap has responsive classes
+	// of, the breakpoint goes right after the root as in mt-md-3
+	bootstrapResponsive = regexp.MustCompile(`^(d|flex|justify-content|align-items|align-self|align-content|float|text|[mp][tbsexy]?|gap|row-gap|column-gap|g[xy]?|order|col|offset|row-cols)-(sm|md|lg|xl|xxl)(-.+)?$`)
+)
+
+// bootstrapTextAlign are the text classes with responsive versions, the
+// colors and sizes have none
+var bootstrapTextAlign = toSet("text-start", "text-center", "text-end")
+
+// TranslateBootstrap returns what a Bootstrap class becomes in Tailwind,
+// false for classes that aren't Bootstrap's. Responsive classes like
+// col-md-6 become the Tailwind variant of the same name, md:w-1/2. Only
+// the responsive families of Bootstrap take a breakpoint, so Tailwind's
+// rounded-lg or shadow-md aren't taken for one
+func TranslateBootstrap(class string) (BootstrapMapping, bool) {
+	if m, ok := translateBootstrap(class); ok {
+		return m, true
+	}
+	r := bootstrapResponsive.FindStringSubmatch(class)
+	if r == nil {
+		return BootstrapMapping{}, false
+	}
+	base := r[1] + r[3]
+	if _, ok := bootstrapTextAlign[base]; r[1] == "text" && !ok {
+		return BootstrapMapping{}, false
+	}
+	m, ok := translateBootstrap(base)
+	if !ok || m.Kind == BootstrapComponent {
+		return BootstrapMapping{}, false
+	}
+	for j, to := range m.To {
+		m.To[j] = bootstrapBreakpoints[r[2]] + ":" + to
+	}
+	return m, true
+}
+
+func translateBootstrap(class string) (BootstrapMapping, bool) {
+	exact := func(to string) (BootstrapMapping, bool) {
+		return BootstrapMapping{Kind: BootstrapExact, To: strings.Fields(to)}, true
+	}
+	if to, ok := bootstrapExact[class]; ok {
+		return exact(to)
+	}
+	if to, ok := bootstrapApproximate[class]; ok {
+		return BootstrapMapping{Kind: BootstrapApproximate, To: strings.Fields(to)}, true
+	}
+	if m := bootstrapSpacing.FindStringSubmatch(class); m != nil {
+		if m[1] == "p" && m[3] == "auto" {
+			return BootstrapMapping{}, false
+		}
+		return exact(m[1] + m[2] + "-" + bootstrapSpacers[m[3]])
+	}
+	if m := bootstrapGap.FindStringSubmatch(class); m != nil {
+		root := map[string]string{"gap": "gap", "row-gap": "gap-y", "column-gap": "gap-x"}[m[1]]
+		return exact(root + "-" + bootstrapSpacers[m[2]])
+	}
+	if m := bootstrapGutter.FindStringSubmatch(class); m != nil {
+		root := map[string]string{"g": "gap", "gx": "gap-x", "gy": "gap-y"}[m[1]]
+		return BootstrapMapping{Kind: BootstrapApproximate, To: []string{root + "-" + bootstrapSpacers[m[2]]}}, true
+	}
+	if m := bootstrapColumn.FindStringSubmatch(class); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		switch {
+		case m[1] == "order" && n <= 5:
+			return exact("order-" + m[2])
+		case m[1] == "col" && n >= 1 && n <= 12:
+			return exact("w-" + twelfths(n))
+		case m[1] == "offset" && n <= 11:
+			if n == 0 {
+				return exact("ml-0")
+			}
+			return exact(fmt.Sprintf("ml-[%.8g%%]", float64(n)*100/12))
+		}
+		return BootstrapMapping{}, false
+	}
+	if m := bootstrapRowCols.FindStringSubmatch(class); m != nil {
+		if m[1] == "auto" {
+			return BootstrapMapping{Kind: BootstrapApproximate, To: []string{"flex", "flex-wrap"}}, true
+		}
+		return BootstrapMapping{Kind: BootstrapApproximate, To: []string{"grid", "grid-cols-" + m[1]}}, true
+	}
+	if m := bootstrapColor.FindStringSubmatch(class); m != nil {
+		// the theme decides what primary is, suggest the slot to fill in
+		root := map[string]string{"text": "text", "bg": "bg", "border": "border", "link": "text", "text-bg": "bg"}[m[1]]
+		return BootstrapMapping{Kind: BootstrapApproximate, To: []string{root + "-" + m[2] + m[3]}}, true
+	}
+	if bootstrapComponentRoot(class) != "" {
+		return BootstrapMapping{Kind: BootstrapComponent}, true
+	}
+	return BootstrapMapping{}, false
+}
+
+// twelfths writes n twelfths as the smallest Tailwind fraction
+func twelfths(n int) string {
+	if n == 12 {
+		return "full"
+	}
+	d := 12
+	for _, f := range []int{6, 4, 3, 2} {
+		if n%f == 0 && d%f == 0 {
+			n, d = n/f, d/f
+			break
+		}
+	}
+	return fmt.Sprintf("%d/%d", n, d)
+}
+
+// BootstrapPage is the migration effort of one file
+type BootstrapPage struct {
+	Path string `json:"path"`
+	// Classes counts the uses of Bootstrap classes, Exact the ones the
+	// codemod rewrites, Approximate and Components the ones a person has to
+	Classes     int      `json:"classes"`
+	Exact       int      `json:"exact"`
+	Approximate int      `json:"approximate"`
+	Components  []string `json:"components,omitempty"`
+	// Effort is a point per approximate class plus five per component the
+	// page uses
+	Effort int `json:"effort"`
+}
+
+// BootstrapMigration is the translation of a project from Bootstrap
+type BootstrapMigration struct {
+	Pages []BootstrapPage `json:"pages"`
+	// Todo has a finding for every class the codemod can't rewrite
+	Todo []Finding `json:"todo"`
+}
+
+// TranslateBootstrapProject works out what moving the project from
+// Bootstrap to Tailwind takes, page by page, the pages with the most
+// effort first
+func TranslateBootstrapProject(p *Project) *BootstrapMigration {
+	migration := &BootstrapMigration{}
+	for _, file := range p.Files {
+		page := BootstrapPage{Path: file.Path}
+		components := make(map[string]bool)
+		for _, element := range file.Elements {
+			component := false
+			for _, token := range element.Classes {
+				if bootstrapComponentRoot(token.Name) != "" {
+					component = true
+				}
+			}
+			var componentClasses []ClassToken
+			for _, token := range element.Classes {
+				m, ok := TranslateBootstrap(token.Name)
+				if _, state := bootstrapStates[token.Name]; state && component {
+					m, ok = BootstrapMapping{Kind: BootstrapComponent}, true
+				}
+				if !ok {
+					continue
+				}
+				page.Classes++
+				script := element.Provenance == ProvenanceJS
+				switch {
+				case m.Kind == BootstrapComponent:
+					componentClasses = append(componentClasses, token)
+					if root := bootstrapComponentRoot(token.Name); root != "" {
+						components[root] = true
+					}
+				case m.Kind == BootstrapExact && !script:
+					page.Exact++
+				default:
+					page.Approximate++
+					message := fmt.Sprintf("no exact Tailwind class for %s, try %s", token.Name, strings.Join(m.To, " "))
+					if m.Kind == BootstrapExact {
+						message = fmt.Sprintf("%s is looked up by a script, rewrite it by hand as %s", token.Name, strings.Join(m.To, " "))
+					}
+					migration.Todo = append(migration.Todo, Finding{Rule: "bootstrap-todo", Pos: token.Pos, Class: token.Name, Message: message})
+				}
+			}
+			if len(componentClasses) > 0 {
+				names := make([]string, len(componentClasses))
+				for i, token := range componentClasses {
+					names[i] = token.Name
+				}
+				migration.Todo = append(migration.Todo, Finding{
+					Rule:    "bootstrap-component",
+					Pos:     componentClasses[0].Pos,
+					Class:   componentClasses[0].Name,
+					Message: fmt.Sprintf("%s come from a Bootstrap component, it needs a Tailwind component of its own", strings.Join(names, " ")),
+				})
+			}
+		}
+		if page.Classes == 0 {
+			continue
+		}
+		for component := range components {
+			page.Components = append(page.Components, component)
+		}
+		sort.Strings(page.Components)
+		page.Effort = page.Approximate + 5*len(page.Components)
+		migration.Pages = append(migration.Pages, page)
+	}
+	sort.SliceStable(migration.Pages, func(i, j int) bool { return migration.Pages[i].Effort > migration.Pages[j].Effort })
+	return migration
+}
+
+// bootstrapComponentRoot returns the component a class belongs to, btn for
+// btn-primary, or an empty string
+func bootstrapComponentRoot(class string) string {
+	for i, pattern := range bootstrapComponentPatterns {
+		if pattern.MatchString(class) {
+			return bootstrapComponents[i].root
+		}
+	}
+	return ""
+}
+
+// BootstrapEdits returns the edits rewriting the Bootstrap classes of
+// file's class attributes that have exact Tailwind counterparts
+func BootstrapEdits(file *SourceFile, src []byte) ([]Edit, []Renamed) {
+	return replaceClasses(file, src, func(class string) ([]string, bool) {
+		m, ok := TranslateBootstrap(class)
+		if !ok || m.Kind != BootstrapExact || (len(m.To) == 1 && m.To[0] == class) {
+			return nil, false
+		}
+		return m.To, true
+	})
+}
+
+// WriteReport writes the effort per page, the pages with the most effort
+// first
+func (m *BootstrapMigration) WriteReport(w io.Writer) error {
+	var b strings.Builder
+	classes, exact := 0, 0
+	for _, page := range m.Pages {
+		classes += page.Classes
+		exact += page.Exact
+	}
+	fmt.Fprintf(&b, "%d uses of Bootstrap classes in %d files, %d with an exact Tailwind counterpart, %d findings to do by hand\n",
+		classes, len(m.Pages), exact, len(m.Todo))
+	if len(m.Pages) > 0 {
+		fmt.Fprintf(&b, "\n  %-40s %6s %7s %5s %6s  %s\n", "page", "effort", "classes", "exact", "approx", "components")
+	}
+	for _, page := range m.Pages {
+		fmt.Fprintf(&b, "  %-40s %6d %7d %5d %6d  %s\n", page.Path, page.Effort, page.Classes, page.Exact,
+			page.Approximate, strings.Join(page.Components, " "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslateBootstrap(t *testing.T) {
+	tests := map[string]string{
+		"d-flex":                       "exact flex",
+		"d-md-none":                    "exact md:hidden",
+		"mt-3":                         "exact mt-4",
+		"px-lg-5":                      "exact lg:px-12",
+		"mx-auto":                      "exact mx-auto",
+		"col-md-6":                     "exact md:w-1/2",
+		"col-4":                        "exact w-1/3",
+		"col-xxl-12":                   "exact 2xl:w-full",
+		"col-5":                        "exact w-5/12",
+		"offset-md-3":                  "exact md:ml-[25%]",
+		"offset-1":                     "exact ml-[8.3333333%]",
+		"justify-content-md-between":   "exact md:justify-between",
+		"translate-middle":             "exact -translate-x-1/2 -translate-y-1/2",
+		"column-gap-3":                 "exact gap-x-4",
+		"row":                          "approximate flex flex-wrap -mx-3",
+		"g-3":                          "approximate gap-4",
+		"text-primary":                 "approximate text-primary",
+		"bg-danger-subtle":             "approximate bg-danger-subtle",
+		"btn":                          "component",
+		"btn-primary":                  "component",
+		"btn-lg":                       "component",
+		"navbar-expand-lg":             "component",
+		"nav-link":                     "component",
+		"table-striped":                "component",
+		"placeholder-glow":             "component",
+		"placeholder-gray-400":         "",
+		"table":                        "",
+		"table-auto":                   "",
+		"active":                       "",
+		"show":                         "",
+		"btn-primary-ish":              "",
+		"p-auto":                       "",
+		"hover:underline":              "",
+		"my-own-class":                 "",
+		"text-decoration-line-through": "exact line-through",
+		"text-md-center":               "exact md:text-center",
+		"float-lg-end":                 "exact lg:float-right",
+		"row-cols-md-3":                "approximate md:grid md:grid-cols-3",
+		"rounded-lg":                   "",
+		"rounded-sm":                   "",
+		"shadow-md":                    "",
+		"text-white-lg":                "",
+		"text-lg":                      "",
+		"text-md-primary":              "",
+		"mt-3-lg":                      "",
+	}
+	for class, expected := range tests {
+		got := ""
+		if m, ok := TranslateBootstrap(class); ok {
+			got = strings.TrimSpace(string(m.Kind) + " " + strings.Join(m.To, " "))
+		}
+		if got != expected {
+			t.Errorf("%s: expected %q, got %q", class, expected, got)
+		}
+	}
+}
+
+func TestTranslateBootstrapProject(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<div class="container">
+  <div class="row d-flex mt-3 my-extra">
+    <div class="col-md-6 text-primary">
+      <a class="btn btn-primary mt-3 active">Go</a>
+    </div>
+  </div>
+</div>`,
+		"about.html": `<p class="d-none d-md-block mt-3 fw-bold"></p>`,
+		"plain.html": `<p class="my-extra"></p><li class="active show"></li><input class="placeholder-gray-400 table">`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migration := TranslateBootstrapProject(project)
+	if len(migration.Pages) != 2 {
+		t.Fatalf("Expected 2 pages, got %+v", migration.Pages)
+	}
+	index, about := migration.Pages[0], migration.Pages[1]
+	if index.Path != "index.html" || index.Classes != 10 || index.Exact != 4 || index.Approximate != 3 || strings.Join(index.Components, " ") != "btn" || index.Effort != 8 {
+		t.Errorf("Unexpected effort for index.html: %+v", index)
+	}
+	if about.Path != "about.html" || about.Classes != 4 || about.Exact != 4 || about.Effort != 0 {
+		t.Errorf("Unexpected effort for about.html: %+v", about)
+	}
+
+	var todo []string
+	for _, f := range migration.Todo {
+		todo = append(todo, f.Pos.String()+" "+f.Rule+" "+f.Message)
+	}
+	expected := []string{
+		"index.html:1:13 bootstrap-todo no exact Tailwind class for container, try container mx-auto px-3",
+		"index.html:2:15 bootstrap-todo no exact Tailwind class for row, try flex flex-wrap -mx-3",
+		"index.html:3:26 bootstrap-todo no exact Tailwind class for text-primary, try text-primary",
+		"index.html:4:17 bootstrap-component btn btn-primary active come from a Bootstrap component, it needs a Tailwind component of its own",
+	}
+	if strings.Join(todo, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected the findings\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(todo, "\n"))
+	}
+
+	var page *SourceFile
+	for _, file := range project.Files {
+		if file.Path == "index.html" {
+			page = file
+		}
+	}
+	src := []byte(files["index.html"])
+	edits, renamed := BootstrapEdits(page, src)
+	if len(renamed) != 4 {
+		t.Errorf("Expected 4 classes rewritten, got %+v", renamed)
+	}
+	fixed, err := ApplyEdits(src, edits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<div class="container">
+  <div class="row flex mt-4 my-extra">
+    <div class="md:w-1/2 text-primary">
+      <a class="btn btn-primary mt-4 active">Go</a>
+    </div>
+  </div>
+</div>`
+	if string(fixed) != want {
+		t.Errorf("Expected the rewritten page to be\n%s\ngot\n%s", want, fixed)
+	}
+}
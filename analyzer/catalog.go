@@ -0,0 +1,658 @@
+package analyzer
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// valueKind is a family of values a utility accepts, kinds are combined
+type valueKind uint8
+
+const (
+	spacingValues  valueKind = 1 << iota // the spacing scale, any multiple of 0.25 in v4
+	fractionValues                       // 1/2, 3/4, ...
+	colorValues                          // the color palette, black, white, ...
+	integerValues                        // any whole number
+	percentValues                        // 10%, 15%, ... as used by gradient stops
+)
+
+// utilitySpec describes the values a utility root accepts
+type utilitySpec struct {
+	kinds valueKind
+	// values are the named values, like "auto" or "full"
+	values []string
+	// bare utilities are valid without a value, like "flex" or "rounded"
+	bare     bool
+	negative bool
+	// theme lists the tailwind.config.js theme keys whose entries extend the
+	// values, the v4 @theme namespaces are mapped onto the same keys
+	theme []string
+}
+
+var (
+	staticUtility = utilitySpec{bare: true}
+
+	palette = []string{
+		"slate", "gray", "zinc", "neutral", "stone", "red", "orange", "amber", "yellow", "lime",
+		"green", "emerald", "teal", "cyan", "sky", "blue", "indigo", "violet", "purple",
+		"fuchsia", "pink", "rose",
+	}
+	shades          = []string{"50", "100", "200", "300", "400", "500", "600", "700", "800", "900", "950"}
+	specialColors   = []string{"inherit", "current", "transparent", "black", "white"}
+	spacingScale    = []string{"0", "px", "0.5", "1", "1.5", "2", "2.5", "3", "3.5", "4", "5", "6", "7", "8", "9", "10", "11", "12", "14", "16", "20", "24", "28", "32", "36", "40", "44", "48", "52", "56", "60", "64", "72", "80", "96"}
+	containerSizes  = []string{"3xs", "2xs", "xs", "sm", "md", "lg", "xl", "2xl", "3xl", "4xl", "5xl", "6xl", "7xl"}
+	fontSizes       = []string{"xs", "sm", "base", "lg", "xl", "2xl", "3xl", "4xl", "5xl", "6xl", "7xl", "8xl", "9xl"}
+	radii           = []string{"none", "xs", "sm", "md", "lg", "xl", "2xl", "3xl", "4xl", "full"}
+	shadows         = []string{"2xs", "xs", "sm", "md", "lg", "xl", "2xl", "inner", "none"}
+	blurs           = []string{"none", "xs", "sm", "md", "lg", "xl", "2xl", "3xl"}
+	lineStyles      = []string{"solid", "dashed", "dotted", "double", "hidden", "none"}
+	positions       = []string{"bottom", "center", "left", "left-bottom", "left-top", "right", "right-bottom", "right-top", "top"}
+	blendModes      = []string{"normal", "multiply", "screen", "overlay", "darken", "lighten", "color-dodge", "color-burn", "hard-light", "soft-light", "difference", "exclusion", "hue", "saturation", "color", "luminosity", "plus-darker", "plus-lighter"}
+	overflows       = []string{"auto", "hidden", "clip", "visible", "scroll"}
+	contentAlign    = []string{"normal", "center", "start", "end", "between", "around", "evenly", "baseline", "stretch"}
+	itemsAlign      = []string{"start", "end", "center", "baseline", "stretch", "baseline-last", "end-safe", "center-safe"}
+	selfAlign       = []string{"auto", "start", "end", "center", "stretch", "baseline", "baseline-last", "end-safe", "center-safe"}
+	insetValues     = []string{"auto", "full", "px"}
+	widthValues     = append([]string{"auto", "full", "screen", "svw", "lvw", "dvw", "min", "max", "fit", "px"}, containerSizes...)
+	heightValues    = []string{"auto", "full", "screen", "svh", "lvh", "dvh", "min", "max", "fit", "px", "lh"}
+	breakValues     = []string{"auto", "avoid", "all", "avoid-page", "page", "left", "right", "column"}
+	snapValues      = []string{"start", "end", "center", "align-none", "normal", "always", "none", "x", "y", "both", "mandatory", "proximity"}
+	gradientSides   = []string{"t", "tr", "r", "br", "b", "bl", "l", "tl"}
+	backgroundNamed = append([]string{"fixed", "local", "scroll", "repeat", "no-repeat", "repeat-x", "repeat-y", "repeat-round", "repeat-space", "auto", "cover", "contain", "none"}, positions...)
+)
+
+func colorSpec(extra ...string) utilitySpec {
+	return utilitySpec{kinds: colorValues, values: extra, theme: []string{"colors"}}
+}
+
+func spacingSpec(negative bool, theme string, extra ...string) utilitySpec {
+	return utilitySpec{kinds: spacingValues, values: extra, negative: negative, theme: []string{"spacing", theme}}
+}
+
+func namedSpec(values ...string) utilitySpec {
+	return utilitySpec{values: values}
+}
+
+// utilities is the catalog of Tailwind v3 and v4 utility roots, the parser
+// uses its keys to find where a root ends and its value begins
+var utilities = map[string]utilitySpec{
+	// layout
+	"aspect":             {kinds: fractionValues, values: []string{"auto", "square", "video"}, theme: []string{"aspectRatio"}},
+	"container":          staticUtility,
+	"columns":            {kinds: integerValues, values: append([]string{"auto"}, containerSizes...), theme: []string{"columns"}},
+	"break-after":        namedSpec(breakValues...),
+	"break-before":       namedSpec(breakValues...),
+	"break-inside":       namedSpec("auto", "avoid", "avoid-page", "avoid-column"),
+	"box-decoration":     namedSpec("clone", "slice"),
+	"box":                namedSpec("border", "content"),
+	"block":              staticUtility,
+	"inline-block":       staticUtility,
+	"inline":             staticUtility,
+	"flex":               {kinds: integerValues | fractionValues, bare: true, values: []string{"1", "auto", "initial", "none", "row", "row-reverse", "col", "col-reverse", "wrap", "wrap-reverse", "nowrap"}, theme: []string{"flex"}},
+	"inline-flex":        staticUtility,
+	"table":              {bare: true, values: []string{"auto", "fixed"}},
+	"inline-table":       staticUtility,
+	"table-caption":      staticUtility,
+	"table-cell":         staticUtility,
+	"table-column":       staticUtility,
+	"table-column-group": staticUtility,
+	"table-footer-group": staticUtility,
+	"table-header-group": staticUtility,
+	"table-row-group":    staticUtility,
+	"table-row":          staticUtility,
+	"flow-root":          staticUtility,
+	"grid":               staticUtility,
+	"inline-grid":        staticUtility,
+	"contents":           staticUtility,
+	"list-item":          staticUtility,
+	"hidden":             staticUtility,
+	"float":              namedSpec("start", "end", "right", "left", "none"),
+	"clear":              namedSpec("start", "end", "left", "right", "both", "none"),
+	"isolate":            staticUtility,
+	"isolation-auto":     staticUtility,
+	"object":             namedSpec(append([]string{"contain", "cover", "fill", "none", "scale-down"}, positions...)...),
+	"overflow":           namedSpec(overflows...),
+	"overflow-x":         namedSpec(overflows...),
+	"overflow-y":         namedSpec(overflows...),
+	"overscroll":         namedSpec("auto", "contain", "none"),
+	"overscroll-x":       namedSpec("auto", "contain", "none"),
+	"overscroll-y":       namedSpec("auto", "contain", "none"),
+	"static":             staticUtility,
+	"fixed":              staticUtility,
+	"absolute":           staticUtility,
+	"relative":           staticUtility,
+	"sticky":             staticUtility,
+	"inset":              {kinds: spacingValues | fractionValues, values: insetValues, negative: true, theme: []string{"spacing", "inset"}},
+	"inset-x":            {kinds: spacingValues | fractionValues, values: insetValues, negative: true, theme: []string{"spacing", "inset"}},
+	"inset-y":            {kinds: spacingValues | fractionValues, values: insetValues, negative: true, theme: []string{"spacing", "inset"}},
+	"start":              {kinds: spacingValues | fractionValues, values: insetValues, negative: true, theme: []string{"spacing", "inset"}},
+	"end":                {kinds: spacingValues | fractionValues, values: insetValues, negative: true, theme: []string{"spacing", "inset"}},
+	"top":                {kinds: spacingValues | fractionValues, values: insetValues, negative: true, theme: []string{"spacing", "inset"}},
+	"right":              {kinds: spacingValues | fractionValues, values: insetValues, negative: true, theme: []string{"spacing", "inset"}},
+	"bottom":             {kinds: spacingValues | fractionValues, values: insetValues, negative: true, theme: []string{"spacing", "inset"}},
+	"left":               {kinds: spacingValues | fractionValues, values: insetValues, negative: true, theme: []string{"spacing", "inset"}},
+	"visible":            staticUtility,
+	"invisible":          staticUtility,
+	"collapse":           staticUtility,
+	"z":                  {kinds: integerValues, values: []string{"auto"}, negative: true, theme: []string{"zIndex"}},
+	// flexbox and grid
+	"basis":           {kinds: spacingValues | fractionValues, values: append([]string{"auto", "full"}, containerSizes...), theme: []string{"spacing", "flexBasis"}},
+	"grow":            {kinds: integerValues, bare: true, theme: []string{"flexGrow"}},
+	"shrink":          {kinds: integerValues, bare: true, theme: []string{"flexShrink"}},
+	"order":           {kinds: integerValues, values: []string{"first", "last", "none"}, negative: true, theme: []string{"order"}},
+	"grid-cols":       {kinds: integerValues, values: []string{"none", "subgrid"}, theme: []string{"gridTemplateColumns"}},
+	"col":             {kinds: integerValues, values: []string{"auto"}, negative: true, theme: []string{"gridColumn"}},
+	"col-span":        {kinds: integerValues, values: []string{"full"}},
+	"col-start":       {kinds: integerValues, values: []string{"auto"}, negative: true, theme: []string{"gridColumnStart"}},
+	"col-end":         {kinds: integerValues, values: []string{"auto"}, negative: true, theme: []string{"gridColumnEnd"}},
+	"grid-rows":       {kinds: integerValues, values: []string{"none", "subgrid"}, theme: []string{"gridTemplateRows"}},
+	"row":             {kinds: integerValues, values: []string{"auto"}, negative: true, theme: []string{"gridRow"}},
+	"row-span":        {kinds: integerValues, values: []string{"full"}},
+	"row-start":       {kinds: integerValues, values: []string{"auto"}, negative: true, theme: []string{"gridRowStart"}},
+	"row-end":         {kinds: integerValues, values: []string{"auto"}, negative: true, theme: []string{"gridRowEnd"}},
+	"grid-flow":       namedSpec("row", "col", "dense", "row-dense", "col-dense"),
+	"auto-cols":       namedSpec("auto", "min", "max", "fr"),
+	"auto-rows":       namedSpec("auto", "min", "max", "fr"),
+	"gap":             spacingSpec(false, "gap"),
+	"gap-x":           spacingSpec(false, "gap"),
+	"gap-y":           spacingSpec(false, "gap"),
+	"justify":         namedSpec(append([]string{"end-safe", "center-safe"}, contentAlign...)...),
+	"justify-items":   namedSpec("start", "end", "center", "stretch", "normal", "end-safe", "center-safe"),
+	"justify-self":    namedSpec("auto", "start", "end", "center", "stretch", "end-safe", "center-safe"),
+	"content":         namedSpec(append([]string{"none", "end-safe", "center-safe"}, contentAlign...)...),
+	"items":           namedSpec(itemsAlign...),
+	"self":            namedSpec(selfAlign...),
+	"place-content":   namedSpec(contentAlign...),
+	"place-items":     namedSpec(itemsAlign...),
+	"place-self":      namedSpec(selfAlign...),
+	"space-x":         spacingSpec(true, "space"),
+	"space-y":         spacingSpec(true, "space"),
+	"space-x-reverse": staticUtility,
+	"space-y-reverse": staticUtility,
+	// spacing
+	"p":  spacingSpec(false, "padding"),
+	"px": spacingSpec(false, "padding"),
+	"py": spacingSpec(false, "padding"),
+	"ps": spacingSpec(false, "padding"),
+	"pe": spacingSpec(false, "padding"),
+	"pt": spacingSpec(false, "padding"),
+	"pr": spacingSpec(false, "padding"),
+	"pb": spacingSpec(false, "padding"),
+	"pl": spacingSpec(false, "padding"),
+	"m":  spacingSpec(true, "margin", "auto"),
+	"mx": spacingSpec(true, "margin", "auto"),
+	"my": spacingSpec(true, "margin", "auto"),
+	"ms": spacingSpec(true, "margin", "auto"),
+	"me": spacingSpec(true, "margin", "auto"),
+	"mt": spacingSpec(true, "margin", "auto"),
+	"mr": spacingSpec(true, "margin", "auto"),
+	"mb": spacingSpec(true, "margin", "auto"),
+	"ml": spacingSpec(true, "margin", "auto"),
+	// sizing
+	"w":     {kinds: spacingValues | fractionValues, values: widthValues, theme: []string{"spacing", "width"}},
+	"min-w": {kinds: spacingValues | fractionValues, values: widthValues, theme: []string{"spacing", "minWidth"}},
+	"max-w": {kinds: spacingValues | fractionValues, values: append([]string{"none", "prose", "screen-sm", "screen-md", "screen-lg", "screen-xl", "screen-2xl"}, widthValues...), theme: []string{"spacing", "maxWidth"}},
+	"h":     {kinds: spacingValues | fractionValues, values: heightValues, theme: []string{"spacing", "height"}},
+	"min-h": {kinds: spacingValues | fractionValues, values: heightValues, theme: []string{"spacing", "minHeight"}},
+	"max-h": {kinds: spacingValues | fractionValues, values: append([]string{"none"}, heightValues...), theme: []string{"spacing", "maxHeight"}},
+	"size":  {kinds: spacingValues | fractionValues, values: []string{"auto", "full", "min", "max", "fit", "px"}, theme: []string{"spacing", "size"}},
+	// typography
+	"font":                 {values: []string{"sans", "serif", "mono", "thin", "extralight", "light", "normal", "medium", "semibold", "bold", "extrabold", "black"}, theme: []string{"fontFamily", "fontWeight"}},
+	"text":                 {kinds: colorValues, values: append([]string{"left", "center", "right", "justify", "start", "end", "wrap", "nowrap", "balance", "pretty", "ellipsis", "clip"}, fontSizes...), theme: []string{"colors", "textColor", "fontSize"}},
+	"antialiased":          staticUtility,
+	"subpixel-antialiased": staticUtility,
+	"italic":               staticUtility,
+	"not-italic":           staticUtility,
+	"normal-nums":          staticUtility,
+	"ordinal":              staticUtility,
+	"slashed-zero":         staticUtility,
+	"lining-nums":          staticUtility,
+	"oldstyle-nums":        staticUtility,
+	"proportional-nums":    staticUtility,
+	"tabular-nums":         staticUtility,
+	"diagonal-fractions":   staticUtility,
+	"stacked-fractions":    staticUtility,
+	"tracking":             {values: []string{"tighter", "tight", "normal", "wide", "wider", "widest"}, negative: true, theme: []string{"letterSpacing"}},
+	"line-clamp":           {kinds: integerValues, values: []string{"none"}, theme: []string{"lineClamp"}},
+	"leading":              {kinds: spacingValues, values: []string{"none", "tight", "snug", "normal", "relaxed", "loose"}, theme: []string{"lineHeight"}},
+	"list-image":           {values: []string{"none"}, theme: []string{"listStyleImage"}},
+	"list":                 {values: []string{"none", "disc", "decimal", "inside", "outside"}, theme: []string{"listStyleType"}},
+	"list-inside":          staticUtility,
+	"list-outside":         staticUtility,
+	"decoration":           {kinds: colorValues | integerValues, values: []string{"solid", "double", "dotted", "dashed", "wavy", "auto", "from-font", "clone", "slice"}, theme: []string{"colors", "textDecorationColor"}},
+	"underline":            staticUtility,
+	"overline":             staticUtility,
+	"line-through":         staticUtility,
+	"no-underline":         staticUtility,
+	"underline-offset":     {kinds: integerValues, values: []string{"auto"}, negative: true, theme: []string{"textUnderlineOffset"}},
+	"uppercase":            staticUtility,
+	"lowercase":            staticUtility,
+	"capitalize":           staticUtility,
+	"normal-case":          staticUtility,
+	"truncate":             staticUtility,
+	"text-ellipsis":        staticUtility,
+	"text-clip":            staticUtility,
+	"indent":               spacingSpec(true, "textIndent"),
+	"align":                namedSpec("baseline", "top", "middle", "bottom", "text-top", "text-bottom", "sub", "super"),
+	"whitespace":           namedSpec("normal", "nowrap", "pre", "pre-line", "pre-wrap", "break-spaces"),
+	"break-normal":         staticUtility,
+	"break-words":          staticUtility,
+	"break-all":            staticUtility,
+	"break-keep":           staticUtility,
+	"wrap":                 namedSpec("break-word", "anywhere", "normal"),
+	"hyphens":              namedSpec("none", "manual", "auto"),
+	"placeholder":          colorSpec(),
+	"placeholder-opacity":  {kinds: integerValues, theme: []string{"opacity"}},
+	"text-opacity":         {kinds: integerValues, theme: []string{"opacity"}},
+	// backgrounds
+	"bg":             {kinds: colorValues, values: backgroundNamed, theme: []string{"colors", "backgroundColor", "backgroundImage", "backgroundSize", "backgroundPosition"}},
+	"bg-opacity":     {kinds: integerValues, theme: []string{"opacity"}},
+	"bg-gradient-to": namedSpec(gradientSides...),
+	"bg-linear":      {kinds: integerValues, values: []string{"to-t", "to-tr", "to-r", "to-br", "to-b", "to-bl", "to-l", "to-tl"}, negative: true},
+	"bg-radial":      {bare: true},
+	"bg-conic":       {kinds: integerValues, bare: true, negative: true},
+	"bg-clip":        namedSpec("border", "padding", "content", "text"),
+	"bg-origin":      namedSpec("border", "padding", "content"),
+	"bg-blend":       namedSpec(blendModes...),
+	"from":           {kinds: colorValues | percentValues, theme: []string{"colors", "gradientColorStops"}},
+	"via":            {kinds: colorValues | percentValues, theme: []string{"colors", "gradientColorStops"}},
+	"to":             {kinds: colorValues | percentValues, theme: []string{"colors", "gradientColorStops"}},
+	"mix-blend":      namedSpec(blendModes...),
+	// borders
+	"rounded":          {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-s":        {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-e":        {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-t":        {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-r":        {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-b":        {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-l":        {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-ss":       {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-se":       {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-ee":       {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-es":       {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-tl":       {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-tr":       {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-br":       {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"rounded-bl":       {values: radii, bare: true, theme: []string{"borderRadius"}},
+	"border":           {kinds: colorValues | integerValues, values: lineStyles, bare: true, theme: []string{"colors", "borderColor", "borderWidth"}},
+	"border-x":         {kinds: colorValues | integerValues, bare: true, theme: []string{"colors", "borderColor", "borderWidth"}},
+	"border-y":         {kinds: colorValues | integerValues, bare: true, theme: []string{"colors", "borderColor", "borderWidth"}},
+	"border-s":         {kinds: colorValues | integerValues, bare: true, theme: []string{"colors", "borderColor", "borderWidth"}},
+	"border-e":         {kinds: colorValues | integerValues, bare: true, theme: []string{"colors", "borderColor", "borderWidth"}},
+	"border-t":         {kinds: colorValues | integerValues, bare: true, theme: []string{"colors", "borderColor", "borderWidth"}},
+	"border-r":         {kinds: colorValues | integerValues, bare: true, theme: []string{"colors", "borderColor", "borderWidth"}},
+	"border-b":         {kinds: colorValues | integerValues, bare: true, theme: []string{"colors", "borderColor", "borderWidth"}},
+	"border-l":         {kinds: colorValues | integerValues, bare: true, theme: []string{"colors", "borderColor", "borderWidth"}},
+	"border-opacity":   {kinds: integerValues, theme: []string{"opacity"}},
+	"divide":           {kinds: colorValues, values: lineStyles, theme: []string{"colors", "divideColor"}},
+	"divide-x":         {kinds: integerValues, bare: true, theme: []string{"divideWidth"}},
+	"divide-y":         {kinds: integerValues, bare: true, theme: []string{"divideWidth"}},
+	"divide-x-reverse": staticUtility,
+	"divide-y-reverse": staticUtility,
+	"divide-opacity":   {kinds: integerValues, theme: []string{"opacity"}},
+	"outline":          {kinds: colorValues | integerValues, values: lineStyles, bare: true, theme: []string{"colors", "outlineColor", "outlineWidth"}},
+	"outline-offset":   {kinds: integerValues, negative: true, theme: []string{"outlineOffset"}},
+	"ring":             {kinds: colorValues | integerValues, values: []string{"inset"}, bare: true, theme: []string{"colors", "ringColor", "ringWidth"}},
+	"ring-inset":       staticUtility,
+	"ring-offset":      {kinds: colorValues | integerValues, theme: []string{"colors", "ringOffsetColor", "ringOffsetWidth"}},
+	"ring-opacity":     {kinds: integerValues, theme: []string{"opacity"}},
+	"inset-ring":       {kinds: colorValues | integerValues, bare: true, theme: []string{"colors"}},
+	// effects and filters
+	"shadow":              {kinds: colorValues, values: shadows, bare: true, theme: []string{"boxShadow", "colors", "boxShadowColor"}},
+	"inset-shadow":        {kinds: colorValues, values: shadows, bare: true, theme: []string{"colors"}},
+	"text-shadow":         {kinds: colorValues, values: shadows, bare: true, theme: []string{"colors"}},
+	"opacity":             {kinds: integerValues, theme: []string{"opacity"}},
+	"filter":              {values: []string{"none"}, bare: true},
+	"blur":                {values: blurs, bare: true, theme: []string{"blur"}},
+	"brightness":          {kinds: integerValues, theme: []string{"brightness"}},
+	"contrast":            {kinds: integerValues, theme: []string{"contrast"}},
+	"drop-shadow":         {kinds: colorValues, values: shadows, bare: true, theme: []string{"dropShadow"}},
+	"grayscale":           {kinds: integerValues, bare: true, theme: []string{"grayscale"}},
+	"hue-rotate":          {kinds: integerValues, negative: true, theme: []string{"hueRotate"}},
+	"invert":              {kinds: integerValues, bare: true, theme: []string{"invert"}},
+	"saturate":            {kinds: integerValues, theme: []string{"saturate"}},
+	"sepia":               {kinds: integerValues, bare: true, theme: []string{"sepia"}},
+	"backdrop-filter":     {values: []string{"none"}, bare: true},
+	"backdrop-blur":       {values: blurs, bare: true, theme: []string{"backdropBlur", "blur"}},
+	"backdrop-brightness": {kinds: integerValues, theme: []string{"backdropBrightness"}},
+	"backdrop-contrast":   {kinds: integerValues, theme: []string{"backdropContrast"}},
+	"backdrop-grayscale":  {kinds: integerValues, bare: true, theme: []string{"backdropGrayscale"}},
+	"backdrop-hue-rotate": {kinds: integerValues, negative: true, theme: []string{"backdropHueRotate"}},
+	"backdrop-invert":     {kinds: integerValues, bare: true, theme: []string{"backdropInvert"}},
+	"backdrop-opacity":    {kinds: integerValues, theme: []string{"backdropOpacity"}},
+	"backdrop-saturate":   {kinds: integerValues, theme: []string{"backdropSaturate"}},
+	"backdrop-sepia":      {kinds: integerValues, bare: true, theme: []string{"backdropSepia"}},
+	"mask":                namedSpec("none", "add", "subtract", "intersect", "exclude", "alpha", "luminance", "match", "type-alpha", "type-luminance"),
+	// tables
+	"border-collapse":  staticUtility,
+	"border-separate":  staticUtility,
+	"border-spacing":   spacingSpec(false, "borderSpacing"),
+	"border-spacing-x": spacingSpec(false, "borderSpacing"),
+	"border-spacing-y": spacingSpec(false, "borderSpacing"),
+	"caption":          namedSpec("top", "bottom"),
+	// transitions, animation and transforms
+	"transition":    {values: []string{"none", "all", "colors", "opacity", "shadow", "transform", "discrete"}, bare: true, theme: []string{"transitionProperty"}},
+	"duration":      {kinds: integerValues, values: []string{"initial"}, theme: []string{"transitionDuration"}},
+	"ease":          {values: []string{"linear", "in", "out", "in-out", "initial"}, theme: []string{"transitionTimingFunction"}},
+	"delay":         {kinds: integerValues, theme: []string{"transitionDelay"}},
+	"animate":       {values: []string{"none", "spin", "ping", "pulse", "bounce"}, theme: []string{"animation"}},
+	"scale":         {kinds: integerValues, values: []string{"none"}, negative: true, theme: []string{"scale"}},
+	"scale-x":       {kinds: integerValues, negative: true, theme: []string{"scale"}},
+	"scale-y":       {kinds: integerValues, negative: true, theme: []string{"scale"}},
+	"rotate":        {kinds: integerValues, values: []string{"none"}, negative: true, theme: []string{"rotate"}},
+	"translate":     {kinds: spacingValues | fractionValues, values: []string{"full", "px", "none"}, negative: true, theme: []string{"spacing", "translate"}},
+	"translate-x":   {kinds: spacingValues | fractionValues, values: []string{"full", "px"}, negative: true, theme: []string{"spacing", "translate"}},
+	"translate-y":   {kinds: spacingValues | fractionValues, values: []string{"full", "px"}, negative: true, theme: []string{"spacing", "translate"}},
+	"skew":          {kinds: integerValues, negative: true, theme: []string{"skew"}},
+	"skew-x":        {kinds: integerValues, negative: true, theme: []string{"skew"}},
+	"skew-y":        {kinds: integerValues, negative: true, theme: []string{"skew"}},
+	"origin":        {values: []string{"center", "top", "top-right", "right", "bottom-right", "bottom", "bottom-left", "left", "top-left"}, theme: []string{"transformOrigin"}},
+	"transform":     {values: []string{"none", "gpu", "cpu"}, bare: true},
+	"transform-gpu": staticUtility,
+	"transform-cpu": staticUtility,
+	"perspective":   namedSpec("dramatic", "near", "normal", "midrange", "distant", "none"),
+	// interactivity
+	"accent":         colorSpec("auto"),
+	"appearance":     namedSpec("none", "auto"),
+	"cursor":         {values: []string{"auto", "default", "pointer", "wait", "text", "move", "help", "not-allowed", "none", "context-menu", "progress", "cell", "crosshair", "vertical-text", "alias", "copy", "no-drop", "grab", "grabbing", "all-scroll", "col-resize", "row-resize", "n-resize", "e-resize", "s-resize", "w-resize", "ne-resize", "nw-resize", "se-resize", "sw-resize", "ew-resize", "ns-resize", "nesw-resize", "nwse-resize", "zoom-in", "zoom-out"}, theme: []string{"cursor"}},
+	"caret":          colorSpec(),
+	"pointer-events": namedSpec("none", "auto"),
+	"resize":         {values: []string{"none", "x", "y"}, bare: true},
+	"scroll":         namedSpec("auto", "smooth"),
+	"scroll-m":       spacingSpec(true, "scrollMargin"),
+	"scroll-mx":      spacingSpec(true, "scrollMargin"),
+	"scroll-my":      spacingSpec(true, "scrollMargin"),
+	"scroll-ms":      spacingSpec(true, "scrollMargin"),
+	"scroll-me":      spacingSpec(true, "scrollMargin"),
+	"scroll-mt":      spacingSpec(true, "scrollMargin"),
+	"scroll-mr":      spacingSpec(true, "scrollMargin"),
+	"scroll-mb":      spacingSpec(true, "scrollMargin"),
+	"scroll-ml":      spacingSpec(true, "scrollMargin"),
+	"scroll-p":       spacingSpec(false, "scrollPadding"),
+	"scroll-px":      spacingSpec(false, "scrollPadding"),
+	"scroll-py":      spacingSpec(false, "scrollPadding"),
+	"scroll-ps":      spacingSpec(false, "scrollPadding"),
+	"scroll-pe":      spacingSpec(false, "scrollPadding"),
+	"scroll-pt":      spacingSpec(false, "scrollPadding"),
+	"scroll-pr":      spacingSpec(false, "scrollPadding"),
+	"scroll-pb":      spacingSpec(false, "scrollPadding"),
+	"scroll-pl":      spacingSpec(false, "scrollPadding"),
+	"snap":           namedSpec(snapValues...),
+	"touch":          namedSpec("auto", "none", "pan-x", "pan-left", "pan-right", "pan-y", "pan-up", "pan-down", "pinch-zoom", "manipulation"),
+	"select":         namedSpec("none", "text", "all", "auto"),
+	"will-change":    {values: []string{"auto", "scroll", "contents", "transform"}, theme: []string{"willChange"}},
+	"field-sizing":   namedSpec("fixed", "content"),
+	"scheme":         namedSpec("normal", "dark", "light", "light-dark", "only-dark", "only-light"),
+	// svg and accessibility
+	"fill":                colorSpec("none"),
+	"stroke":              {kinds: colorValues | integerValues, values: []string{"none"}, theme: []string{"colors", "stroke", "strokeWidth"}},
+	"sr-only":             staticUtility,
+	"not-sr-only":         staticUtility,
+	"forced-color-adjust": namedSpec("auto", "none"),
+	// markers for the group-* and peer-* variants
+	"group": staticUtility,
+	"peer":  staticUtility,
+}
+
+// variants are the built-in variants that don't take a value
+var variants = toSet(
+	// responsive, media and feature queries
+	"sm", "md", "lg", "xl", "2xl", "max-sm", "max-md", "max-lg", "max-xl", "max-2xl",
+	"dark", "print", "portrait", "landscape", "ltr", "rtl", "motion-safe", "motion-reduce",
+	"contrast-more", "contrast-less", "forced-colors", "inverted-colors", "noscript",
+	"pointer-fine", "pointer-coarse", "pointer-none", "any-pointer-fine", "any-pointer-coarse",
+	"any-pointer-none", "starting",
+	// pseudo-classes
+	"hover", "focus", "focus-within", "focus-visible", "active", "visited", "target", "first",
+	"last", "only", "odd", "even", "first-of-type", "last-of-type", "only-of-type", "empty",
+	"disabled", "enabled", "checked", "indeterminate", "default", "optional", "required",
+	"valid", "invalid", "user-valid", "user-invalid", "in-range", "out-of-range",
+	"placeholder-shown", "autofill", "read-only", "open", "inert", "details-content",
+	// pseudo-elements
+	"before", "after", "first-letter", "first-line", "marker", "selection", "file",
+	"placeholder", "backdrop",
+	// children and container queries
+	"*", "**", "@container",
+	// aria states
+	"aria-busy", "aria-checked", "aria-disabled", "aria-expanded", "aria-hidden",
+	"aria-pressed", "aria-readonly", "aria-required", "aria-selected",
+)
+
+// variantPrefixes are the functional variants, the bool tells whether the
+// rest of the variant has to be a variant itself (group-hover) rather than
+// any name or an arbitrary value (data-active, supports-[display:grid])
+var variantPrefixes = []struct {
+	prefix    string
+	isVariant bool
+}{
+	{"group-", true}, {"peer-", true}, {"not-", true}, {"has-", true}, {"in-", true},
+	{"aria-", false}, {"data-", false}, {"supports-", false}, {"min-", false}, {"max-", false},
+	{"nth-last-of-type-", false}, {"nth-of-type-", false}, {"nth-last-", false}, {"nth-", false},
+	{"@min-", false}, {"@max-", false}, {"@", false},
+}
+
+func toSet(values ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// Catalog is the built-in utility and variant catalog plus whatever a
+// project adds to it through its theme
+type Catalog struct {
+	// Theme holds extra values by theme key, e.g. "colors": ["brand", "brand-dark"]
+	Theme map[string][]string
+	// Utilities and Variants are the project's own, from @utility and
+	// @custom-variant, functional utilities like "tab-*" take any value
+	Utilities map[string]bool
+	Variants  map[string]bool
+}
+
+// NewCatalog returns the built-in catalog without any extensions
+func NewCatalog() *Catalog {
+	return &Catalog{Theme: make(map[string][]string), Utilities: make(map[string]bool), Variants: make(map[string]bool)}
+}
+
+// Extend adds values to a theme key the same way theme.extend does
+func (c *Catalog) Extend(key string, values ...string) {
+	c.Theme[key] = append(c.Theme[key], values...)
+}
+
+// AddUtility registers a custom utility, "tab-*" for functional ones
+func (c *Catalog) AddUtility(name string) {
+	c.Utilities[name] = true
+}
+
+// AddVariant registers a custom variant
+func (c *Catalog) AddVariant(name string) {
+	c.Variants[name] = true
+}
+
+// customUtility reports whether the class is one of the project's own
+// utilities, those can shadow built-in roots like @utility content-auto
+func (c *Catalog) customUtility(class Class) bool {
+	if len(c.Utilities) == 0 || class.Property != "" {
+		return false
+	}
+	name := class.Utility
+	if class.Value != "" {
+		name += "-" + class.Value
+	} else if class.Arbitrary != "" {
+		name += "-[" + class.Arbitrary + "]"
+	}
+	if c.Utilities[name] {
+		return true
+	}
+	for utility := range c.Utilities {
+		if root, ok := strings.CutSuffix(utility, "-*"); ok && strings.HasPrefix(name, root+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUtility returns why a parsed class isn't a valid utility, or an
+// empty string when it is
+func (c *Catalog) checkUtility(class Class) string {
+	if c.customUtility(class) {
+		return ""
+	}
+	if !class.Known {
+		return "unknown utility"
+	}
+	if class.Property != "" {
+		return ""
+	}
+	spec := utilities[class.Utility]
+	if class.Negative && !spec.negative {
+		return "utility can't be negative"
+	}
+	switch {
+	case class.Arbitrary != "":
+		if spec.kinds == 0 && len(spec.values) == 0 && len(spec.theme) == 0 {
+			return "utility doesn't take a value"
+		}
+	case class.Value == "":
+		if !spec.bare {
+			return "utility needs a value"
+		}
+	case !c.validValue(spec, class.Value):
+		return "unknown value"
+	}
+	if class.Modifier != "" && !c.validModifier(class, spec) {
+		return "unknown modifier"
+	}
+	return ""
+}
+
+func (c *Catalog) validValue(spec utilitySpec, value string) bool {
+	if slices.Contains(spec.values, value) {
+		return true
+	}
+	for _, key := range spec.theme {
+		if slices.Contains(c.Theme[key], value) {
+			return true
+		}
+	}
+	switch {
+	case spec.kinds&spacingValues != 0 && isSpacing(value),
+		spec.kinds&fractionValues != 0 && isFraction(value),
+		spec.kinds&colorValues != 0 && c.isColor(value),
+		spec.kinds&integerValues != 0 && isDigits(value),
+		spec.kinds&percentValues != 0 && strings.HasSuffix(value, "%") && isDigits(strings.TrimSuffix(value, "%")):
+		return true
+	}
+	return false
+}
+
+// validModifier accepts opacity modifiers on colors and line heights on
+// font sizes, group and peer markers take any name
+func (c *Catalog) validModifier(class Class, spec utilitySpec) bool {
+	if class.Utility == "group" || class.Utility == "peer" {
+		return true
+	}
+	modifier := class.Modifier
+	arbitrary := strings.HasPrefix(modifier, "[") || strings.HasPrefix(modifier, "(")
+	isColor := class.Arbitrary != "" || c.isColor(class.Value) || (spec.kinds&colorValues != 0 && slices.Contains(c.Theme["colors"], class.Value))
+	if class.Utility == "text" && slices.Contains(fontSizes, class.Value) {
+		return arbitrary || isSpacing(modifier) || slices.Contains(utilities["leading"].values, modifier)
+	}
+	return spec.kinds&colorValues != 0 && isColor && (arbitrary || isDigits(modifier))
+}
+
+// isSpacing accepts the v3 spacing scale and any multiple of 0.25 like v4
+func isSpacing(value string) bool {
+	if slices.Contains(spacingScale, value) {
+		return true
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 || value[0] == '.' || strings.HasSuffix(value, ".") {
+		return false
+	}
+	return f*4 == float64(int(f*4))
+}
+
+func isFraction(value string) bool {
+	numerator, denominator, ok := strings.Cut(value, "/")
+	return ok && isDigits(numerator) && isDigits(denominator)
+}
+
+func (c *Catalog) isColor(value string) bool {
+	if slices.Contains(specialColors, value) || slices.Contains(c.Theme["colors"], value) {
+		return true
+	}
+	i := strings.LastIndex(value, "-")
+	return i > 0 && slices.Contains(palette, value[:i]) && slices.Contains(shades, value[i+1:])
+}
+
+// validVariant checks a single variant of a variant chain
+func (c *Catalog) validVariant(variant string) bool {
+	if strings.HasPrefix(variant, "[") && strings.HasSuffix(variant, "]") {
+		return true
+	}
+	if _, ok := variants[variant]; ok || c.Variants[variant] {
+		return true
+	}
+	for _, screen := range c.Theme["screens"] {
+		if variant == screen || variant == "max-"+screen || variant == "min-"+screen {
+			return true
+		}
+	}
+	for _, vp := range variantPrefixes {
+		rest, ok := strings.CutPrefix(variant, vp.prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		// group-hover/item and @md/main name what they refer to
+		if vp.prefix == "group-" || vp.prefix == "peer-" || strings.HasPrefix(vp.prefix, "@") {
+			if i := strings.LastIndex(rest, "/"); i > 0 && !strings.Contains(rest[i:], "]") {
+				rest = rest[:i]
+			}
+		}
+		if strings.HasPrefix(rest, "[") && strings.HasSuffix(rest, "]") {
+			return true
+		}
+		if vp.isVariant {
+			return c.validVariant(rest)
+		}
+		switch vp.prefix {
+		case "min-", "max-", "@min-", "@max-":
+			return slices.Contains(fontSizes, rest) || slices.Contains(containerSizes, rest) || slices.Contains(c.Theme["screens"], rest)
+		case "@":
+			return slices.Contains(containerSizes, rest)
+		case "nth-", "nth-last-", "nth-of-type-", "nth-last-of-type-":
+			return isDigits(rest)
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// candidateValues lists the values of a utility to suggest from, numbers
+// beyond the spacing scale are left out
+func (c *Catalog) candidateValues(root string) []string {
+	spec := utilities[root]
+	candidates := slices.Clone(spec.values)
+	for _, key := range spec.theme {
+		candidates = append(candidates, c.Theme[key]...)
+	}
+	if spec.kinds&spacingValues != 0 {
+		candidates = append(candidates, spacingScale...)
+	}
+	if spec.kinds&colorValues != 0 {
+		candidates = append(candidates, specialColors...)
+		for _, color := range palette {
+			for _, shade := range shades {
+				candidates = append(candidates, color+"-"+shade)
+			}
+		}
+	}
+	if spec.kinds&fractionValues != 0 {
+		candidates = append(candidates, "1/2", "1/3", "2/3", "1/4", "3/4", "1/5", "2/5", "3/5", "4/5", "1/6", "5/6")
+	}
+	return candidates
+}
@@ -0,0 +1,398 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Specificity is the (ids, classes, types) specificity of a selector,
+// attributes and pseudo-classes count as classes and pseudo-elements as
+// types
+type Specificity [3]int
+
+func (s Specificity) String() string {
+	return fmt.Sprintf("(%d,%d,%d)", s[0], s[1], s[2])
+}
+
+// Less reports whether s loses to o in the cascade
+func (s Specificity) Less(o Specificity) bool {
+	for i := range s {
+		if s[i] != o[i] {
+			return s[i] < o[i]
+		}
+	}
+	return false
+}
+
+func (s Specificity) add(o Specificity) Specificity {
+	return Specificity{s[0] + o[0], s[1] + o[1], s[2] + o[2]}
+}
+
+// SelectorComplexity is what makes a selector hard to override, Depth is
+// the number of compound selectors chained with combinators and
+// QualifiedTypes are the compounds pinning a class or ID to an element,
+// like "div.card"
+type SelectorComplexity struct {
+	Sheet          string      `json:"sheet"`
+	Pos            Position    `json:"pos"`
+	Selector       string      `json:"selector"`
+	Specificity    Specificity `json:"specificity"`
+	Depth          int         `json:"depth"`
+	IDs            int         `json:"ids,omitempty"`
+	QualifiedTypes []string    `json:"qualifiedTypes,omitempty"`
+}
+
+// SheetComplexity sums up the selectors of a stylesheet, Important holds
+// where its !important declarations are
+type SheetComplexity struct {
+	Path           string      `json:"path"`
+	Rules          int         `json:"rules"`
+	Selectors      int         `json:"selectors"`
+	Declarations   int         `json:"declarations"`
+	MaxSpecificity Specificity `json:"maxSpecificity"`
+	MaxDepth       int         `json:"maxDepth"`
+	IDs            int         `json:"ids"`
+	QualifiedTypes int         `json:"qualifiedTypes"`
+	Important      []Position  `json:"important,omitempty"`
+}
+
+// Complexity is the specificity report of the project's stylesheets
+type Complexity struct {
+	Sheets    []*SheetComplexity   `json:"sheets"`
+	Selectors []SelectorComplexity `json:"selectors"`
+}
+
+// Distribution counts the selectors falling into one bar of a histogram
+type Distribution struct {
+	Label     string `json:"label"`
+	Selectors int    `json:"selectors"`
+}
+
+// ComplexityLimits turn the complexity report into lint findings, the zero
+// value doesn't limit anything
+type ComplexityLimits struct {
+	// MaxSpecificity is the highest specificity a selector may have
+	MaxSpecificity Specificity `json:"maxSpecificity"`
+	// MaxDepth is how many compound selectors a selector may chain
+	MaxDepth int `json:"maxDepth,omitempty"`
+	// MaxIDs is how many ID selectors a selector may use, 0 bans them
+	MaxIDs *int `json:"maxIds,omitempty"`
+	// MaxImportant is how many !important declarations a stylesheet may have
+	MaxImportant *int `json:"maxImportant,omitempty"`
+	// NoQualifiedTypes flags type selectors qualifying a class or ID
+	NoQualifiedTypes bool `json:"noQualifiedTypes,omitempty"`
+}
+
+// ComputeComplexity measures every selector of the project's stylesheets,
+// nested selectors are measured resolved
+func ComputeComplexity(p *Project) *Complexity {
+	complexity := &Complexity{}
+	for _, sheet := range p.Stylesheets {
+		sc := &SheetComplexity{Path: sheet.Path}
+		sheet.walkStyleRules(func(rule *CSSRule, selectors []string) {
+			sc.Rules++
+			for _, selector := range selectors {
+				s := measureSelector(selector)
+				s.Sheet, s.Pos = sheet.Path, rule.Pos
+				complexity.Selectors = append(complexity.Selectors, s)
+				sc.Selectors++
+				if sc.MaxSpecificity.Less(s.Specificity) {
+					sc.MaxSpecificity = s.Specificity
+				}
+				sc.MaxDepth = max(sc.MaxDepth, s.Depth)
+				if s.IDs > 0 {
+					sc.IDs++
+				}
+				if len(s.QualifiedTypes) > 0 {
+					sc.QualifiedTypes++
+				}
+			}
+		})
+		// declarations count wherever they are, in @font-face too
+		sheet.Walk(func(rule *CSSRule) {
+			for _, d := range rule.Declarations {
+				sc.Declarations++
+				if d.Important {
+					sc.Important = append(sc.Important, d.Pos)
+				}
+			}
+		})
+		complexity.Sheets = append(complexity.Sheets, sc)
+	}
+	return complexity
+}
+
+// selectorScanner walks a selector one simple selector at a time, keeping
+// track of the compound it's in
+type selectorScanner struct {
+	selector    string
+	specificity Specificity
+	ids         int
+	depth       int
+	qualified   []string
+	// the compound being read
+	start         int
+	hasType       bool
+	hasQualifiers bool
+	nonEmpty      bool
+}
+
+// measureSelector computes the specificity, depth, ID count and qualified
+// type selectors of a single complex selector
+func measureSelector(selector string) SelectorComplexity {
+	s := &selectorScanner{selector: selector}
+	s.scan()
+	return SelectorComplexity{Selector: selector, Specificity: s.specificity, Depth: s.depth, IDs: s.ids, QualifiedTypes: s.qualified}
+}
+
+// selectorListSpecificity is the specificity of the most specific selector
+// of a list, what :is(), :not() and :has() count as, with the IDs in it
+func selectorListSpecificity(list string) (Specificity, int) {
+	var highest Specificity
+	ids := 0
+	for _, selector := range splitSelectorList(list) {
+		s := measureSelector(selector)
+		if highest.Less(s.Specificity) {
+			highest = s.Specificity
+		}
+		ids += s.IDs
+	}
+	return highest, ids
+}
+
+func (s *selectorScanner) endCompound(i int) {
+	if s.nonEmpty {
+		s.depth++
+		if s.hasType && s.hasQualifiers {
+			s.qualified = append(s.qualified, strings.TrimSpace(s.selector[s.start:i]))
+		}
+	}
+	s.start, s.hasType, s.hasQualifiers, s.nonEmpty = i+1, false, false, false
+}
+
+func (s *selectorScanner) scan() {
+	sel := s.selector
+	i := 0
+	for i < len(sel) {
+		ch := sel[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '>' || ch == '+' || ch == '~':
+			s.endCompound(i)
+			i++
+			continue
+		case ch == '#':
+			_, n := readCSSIdent(sel[i+1:])
+			s.specificity[0]++
+			s.ids++
+			s.hasQualifiers = true
+			i += 1 + n
+		case ch == '.' || ch == '%':
+			// %placeholder is a Sass class that's only ever extended
+			_, n := readCSSIdent(sel[i+1:])
+			s.specificity[1]++
+			s.hasQualifiers = true
+			i += 1 + n
+		case ch == '[':
+			for i < len(sel) && sel[i] != ']' {
+				if sel[i] == '"' || sel[i] == '\'' {
+					quote := sel[i]
+					for i++; i < len(sel) && sel[i] != quote; i++ {
+						if sel[i] == '\\' {
+							i++
+						}
+					}
+				}
+				i++
+			}
+			s.specificity[1]++
+			i++
+		case ch == ':':
+			i = s.pseudo(i)
+		case ch == '*' || ch == '&' || ch == '|':
+			i++
+		default:
+			name, n := readCSSIdent(sel[i:])
+			if n == 0 {
+				i++
+				continue
+			}
+			i += n
+			if i < len(sel) && sel[i] == '|' && !strings.HasPrefix(sel[i:], "|=") {
+				// a namespace prefix, "svg|a"
+				i++
+				continue
+			}
+			if name != "" {
+				s.specificity[2]++
+				s.hasType = true
+			}
+		}
+		s.nonEmpty = true
+	}
+	s.endCompound(len(sel))
+}
+
+// pseudo reads the pseudo-class or pseudo-element at i and returns where
+// it ends
+func (s *selectorScanner) pseudo(i int) int {
+	sel := s.selector
+	element := strings.HasPrefix(sel[i:], "::")
+	if element {
+		i++
+	}
+	name, n := readCSSIdent(sel[i+1:])
+	name = strings.ToLower(name)
+	i += 1 + n
+	var argument string
+	if i < len(sel) && sel[i] == '(' {
+		var end int
+		argument, end = functionArgument(sel, i)
+		i = end + 1
+	}
+	if _, legacy := legacyPseudoElements[name]; element || legacy {
+		s.specificity[2]++
+		return i
+	}
+	switch name {
+	case "is", "matches", "any", "-webkit-any", "-moz-any", "not", "has":
+		highest, ids := selectorListSpecificity(argument)
+		s.specificity = s.specificity.add(highest)
+		s.ids += ids
+	case "where":
+		_, ids := selectorListSpecificity(argument)
+		s.ids += ids
+	case "nth-child", "nth-last-child":
+		s.specificity[1]++
+		if _, of, ok := strings.Cut(strings.ToLower(argument), " of "); ok {
+			highest, ids := selectorListSpecificity(argument[len(argument)-len(of):])
+			s.specificity = s.specificity.add(highest)
+			s.ids += ids
+		}
+	default:
+		s.specificity[1]++
+	}
+	return i
+}
+
+// Worst returns the n most specific selectors, none when n is negative, the
+// deepest first among equally specific ones
+func (c *Complexity) Worst(n int) []SelectorComplexity {
+	worst := append([]SelectorComplexity(nil), c.Selectors...)
+	sort.SliceStable(worst, func(i, j int) bool {
+		a, b := worst[i], worst[j]
+		if a.Specificity != b.Specificity {
+			return b.Specificity.Less(a.Specificity)
+		}
+		return a.Depth > b.Depth
+	})
+	return worst[:max(0, min(n, len(worst)))]
+}
+
+// SpecificityHistogram buckets the selectors by IDs and then by classes,
+// types don't change the bucket
+func (c *Complexity) SpecificityHistogram() []Distribution {
+	buckets := []Distribution{{Label: "(0,0,*)"}, {Label: "(0,1,*)"}, {Label: "(0,2,*)"}, {Label: "(0,3,*)"}, {Label: "(0,4+,*)"}, {Label: "(1+,*,*)"}}
+	for _, s := range c.Selectors {
+		if s.Specificity[0] > 0 {
+			buckets[5].Selectors++
+		} else {
+			buckets[min(s.Specificity[1], 4)].Selectors++
+		}
+	}
+	return buckets
+}
+
+// DepthHistogram buckets the selectors by how many compounds they chain
+func (c *Complexity) DepthHistogram() []Distribution {
+	buckets := []Distribution{{Label: "1"}, {Label: "2"}, {Label: "3"}, {Label: "4"}, {Label: "5+"}}
+	for _, s := range c.Selectors {
+		buckets[min(max(s.Depth, 1), 5)-1].Selectors++
+	}
+	return buckets
+}
+
+// Lint returns a finding for every selector and !important declaration
+// over the limits, in stylesheet order
+func (c *Complexity) Lint(limits ComplexityLimits) []Finding {
+	var findings []Finding
+	bySheet := make(map[string][]Finding)
+	for _, s := range c.Selectors {
+		add := func(rule, format string, args ...any) {
+			bySheet[s.Sheet] = append(bySheet[s.Sheet], Finding{Rule: rule, Pos: s.Pos, Message: fmt.Sprintf(format, args...)})
+		}
+		if limits.MaxSpecificity != (Specificity{}) && limits.MaxSpecificity.Less(s.Specificity) {
+			add("max-specificity", "selector %s has specificity %s, more than %s", s.Selector, s.Specificity, limits.MaxSpecificity)
+		}
+		if limits.MaxDepth > 0 && s.Depth > limits.MaxDepth {
+			add("max-depth", "selector %s chains %d compound selectors, more than %d", s.Selector, s.Depth, limits.MaxDepth)
+		}
+		if limits.MaxIDs != nil && s.IDs > *limits.MaxIDs {
+			add("max-ids", "selector %s uses %d ID selectors, more than %d", s.Selector, s.IDs, *limits.MaxIDs)
+		}
+		if limits.NoQualifiedTypes {
+			for _, compound := range s.QualifiedTypes {
+				add("qualified-type", "selector %s qualifies %s with a type selector", s.Selector, compound)
+			}
+		}
+	}
+	for _, sheet := range c.Sheets {
+		if limits.MaxImportant != nil && len(sheet.Important) > *limits.MaxImportant {
+			for _, pos := range sheet.Important[*limits.MaxImportant:] {
+				bySheet[sheet.Path] = append(bySheet[sheet.Path], Finding{Rule: "max-important", Pos: pos,
+					Message: fmt.Sprintf("%d !important declarations in %s, more than %d", len(sheet.Important), sheet.Path, *limits.MaxImportant)})
+			}
+		}
+		sheetFindings := bySheet[sheet.Path]
+		sort.SliceStable(sheetFindings, func(i, j int) bool { return sheetFindings[i].Pos.Offset < sheetFindings[j].Pos.Offset })
+		findings = append(findings, sheetFindings...)
+	}
+	return findings
+}
+
+// WriteReport prints the totals per stylesheet, the specificity and depth
+// histograms and the n worst selectors
+func (c *Complexity) WriteReport(w io.Writer, n int) error {
+	var b strings.Builder
+	selectors, rules, important, ids, qualified := 0, 0, 0, 0, 0
+	for _, sheet := range c.Sheets {
+		selectors += sheet.Selectors
+		rules += sheet.Rules
+		important += len(sheet.Important)
+		ids += sheet.IDs
+		qualified += sheet.QualifiedTypes
+	}
+	fmt.Fprintf(&b, "%d selectors in %d rules of %d stylesheets, %d with IDs, %d with qualified types, %d !important declarations\n",
+		selectors, rules, len(c.Sheets), ids, qualified, important)
+
+	b.WriteString("\nStylesheets\n")
+	fmt.Fprintf(&b, "  %-50s %9s %12s %5s %4s %9s %10s\n", "stylesheet", "selectors", "specificity", "depth", "ids", "qualified", "!important")
+	for _, sheet := range c.Sheets {
+		fmt.Fprintf(&b, "  %-50s %9d %12s %5d %4d %9d %10d\n", sheet.Path, sheet.Selectors, sheet.MaxSpecificity,
+			sheet.MaxDepth, sheet.IDs, sheet.QualifiedTypes, len(sheet.Important))
+	}
+
+	writeDistribution := func(title string, buckets []Distribution) {
+		fmt.Fprintf(&b, "\n%s\n", title)
+		labels := make([]string, len(buckets))
+		counts := make([]int, len(buckets))
+		for i, bucket := range buckets {
+			labels[i], counts[i] = bucket.Label, bucket.Selectors
+		}
+		writeBars(&b, labels, counts)
+	}
+	writeDistribution("Specificity (selectors by ids, classes, types)", c.SpecificityHistogram())
+	writeDistribution("Depth (selectors by compound selectors chained)", c.DepthHistogram())
+
+	fmt.Fprintf(&b, "\nTop %d most specific selectors\n", n)
+	worst := c.Worst(n)
+	if len(worst) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, s := range worst {
+		fmt.Fprintf(&b, "  %s: %s depth %d: %s\n", s.Pos, s.Specificity, s.Depth, s.Selector)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
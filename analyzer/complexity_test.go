@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMeasureSelector(t *testing.T) {
+	tests := []struct {
+		selector    string
+		specificity Specificity
+		depth       int
+		ids         int
+		qualified   []string
+	}{
+		{"*", Specificity{0, 0, 0}, 1, 0, nil},
+		{"li", Specificity{0, 0, 1}, 1, 0, nil},
+		{"ul li", Specificity{0, 0, 2}, 2, 0, nil},
+		{".nav > li.item.active a:hover", Specificity{0, 4, 2}, 3, 0, []string{"li.item.active"}},
+		{"#main .card::before", Specificity{1, 1, 1}, 2, 1, nil},
+		{"a:before", Specificity{0, 0, 2}, 1, 0, nil},
+		{"div#app", Specificity{1, 0, 1}, 1, 1, []string{"div#app"}},
+		{"input[type=\"text\"]:focus", Specificity{0, 2, 1}, 1, 0, nil},
+		{":is(#a, .b) p", Specificity{1, 0, 1}, 2, 1, nil},
+		{":where(#a, .b) p", Specificity{0, 0, 1}, 2, 1, nil},
+		{"a:not(.x):has(> img)", Specificity{0, 1, 2}, 1, 0, nil},
+		{"li:nth-child(2n+1 of .item)", Specificity{0, 2, 1}, 1, 0, nil},
+		{".a\\:hover\\:b", Specificity{0, 1, 0}, 1, 0, nil},
+		{"svg|circle", Specificity{0, 0, 1}, 1, 0, nil},
+		{"a ~ b + c", Specificity{0, 0, 3}, 3, 0, nil},
+	}
+	for _, test := range tests {
+		s := measureSelector(test.selector)
+		if s.Specificity != test.specificity || s.Depth != test.depth || s.IDs != test.ids || !slices.Equal(s.QualifiedTypes, test.qualified) {
+			t.Errorf("%s: expected %s depth %d ids %d qualified %v, got %s depth %d ids %d qualified %v", test.selector,
+				test.specificity, test.depth, test.ids, test.qualified, s.Specificity, s.Depth, s.IDs, s.QualifiedTypes)
+		}
+	}
+}
+
+func TestComputeComplexity(t *testing.T) {
+	css := `.btn { color: red }
+#app .sidebar ul li a.link:hover { color: blue !important }
+.card { & .title { margin: 0 !important } }
+@font-face { font-family: x; src: url(x.woff) }
+`
+	dir := writeProject(t, map[string]string{"site.css": css})
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	complexity := ComputeComplexity(project)
+	if len(complexity.Sheets) != 1 {
+		t.Fatalf("Expected 1 stylesheet, got %d", len(complexity.Sheets))
+	}
+	sheet := complexity.Sheets[0]
+	if sheet.Rules != 4 || sheet.Selectors != 4 || sheet.Declarations != 5 || len(sheet.Important) != 2 ||
+		sheet.MaxSpecificity != (Specificity{1, 3, 3}) || sheet.MaxDepth != 5 || sheet.IDs != 1 || sheet.QualifiedTypes != 1 {
+		t.Errorf("Unexpected stylesheet totals %+v", sheet)
+	}
+	worst := complexity.Worst(2)
+	if len(worst) != 2 || worst[0].Selector != "#app .sidebar ul li a.link:hover" || worst[1].Selector != ".card .title" {
+		t.Errorf("Unexpected worst selectors %+v", worst)
+	}
+	if worst := complexity.Worst(-1); len(worst) != 0 {
+		t.Errorf("Expected no selectors for a negative count, got %+v", worst)
+	}
+	if h := complexity.SpecificityHistogram(); h[1].Selectors != 2 || h[2].Selectors != 1 || h[5].Selectors != 1 {
+		t.Errorf("Unexpected specificity histogram %+v", h)
+	}
+	if h := complexity.DepthHistogram(); h[0].Selectors != 2 || h[1].Selectors != 1 || h[4].Selectors != 1 {
+		t.Errorf("Unexpected depth histogram %+v", h)
+	}
+
+	if findings := complexity.Lint(ComplexityLimits{}); len(findings) != 0 {
+		t.Errorf("Expected no findings without limits, got %v", findings)
+	}
+	zero, one := 0, 1
+	findings := complexity.Lint(ComplexityLimits{
+		MaxSpecificity:   Specificity{0, 3, 0},
+		MaxDepth:         3,
+		MaxIDs:           &zero,
+		MaxImportant:     &one,
+		NoQualifiedTypes: true,
+	})
+	var rules []string
+	for _, f := range findings {
+		rules = append(rules, f.Rule)
+	}
+	expected := []string{"max-specificity", "max-depth", "max-ids", "qualified-type", "max-important"}
+	if !slices.Equal(rules, expected) {
+		t.Errorf("Expected findings %v, got %v", expected, findings)
+	}
+	if last := findings[len(findings)-1]; last.Pos.Line != 3 || last.Message != "2 !important declarations in site.css, more than 1" {
+		t.Errorf("Unexpected !important finding %v", last)
+	}
+
+	var b strings.Builder
+	if err := complexity.WriteReport(&b, 3); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"4 selectors in 4 rules of 1 stylesheets, 1 with IDs, 1 with qualified types, 2 !important declarations\n",
+		"site.css:2:1: (1,3,3) depth 5: #app .sidebar ul li a.link:hover\n",
+		"   (0,1,*) | ######################################## 2\n",
+	} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", s, b.String())
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ComponentOptions says which repeated class lists are worth a component
+type ComponentOptions struct {
+	// MinOccurrences is how many elements have to share the classes
+	MinOccurrences int
+	// MinClasses is how many classes they have to share
+	MinClasses int
+	// MaxDifference is how many classes a list can have or lack next to
+	// the most common one and still count as the same, 0 only takes
+	// identical lists
+	MaxDifference int
+}
+
+// DefaultComponentOptions takes lists of 3 classes or more on 3 elements
+// or more, one swapped class apart
+var DefaultComponentOptions = ComponentOptions{MinOccurrences: 3, MinClasses: 3, MaxDifference: 2}
+
+// ComponentCandidate is a set of classes many elements share, which a
+// component class applying them could replace
+type ComponentCandidate struct {
+	Name string `json:"name"`
+	// Classes are the shared classes, in canonical order
+	Classes []string `json:"classes"`
+	// Lists counts the different class lists sharing them, 1 when every
+	// element has exactly the same classes
+	Lists       int        `json:"lists"`
+	Occurrences int        `json:"occurrences"`
+	Files       []string   `json:"files"`
+	Uses        []Position `json:"uses"`
+	// Score ranks candidates, occurrences times classes
+	Score int `json:"score"`
+	// CSS is the rule defining the component with @apply
+	CSS      string `json:"css"`
+	elements []*Element
+}
+
+// classList is one class list, sorted and without repetitions, and the
+// elements that have it
+type classList struct {
+	classes  []string
+	elements []*Element
+}
+
+// ComponentCandidates finds the class lists repeated across the project's
+// markup, sorted so the order of the classes doesn't matter, and groups the
+// near-identical ones around the most common. Each group shares the classes
+// all of its lists have, those Tailwind knows go into the component, the
+// others stay in the markup next to it. The best candidates come first
+func ComponentCandidates(p *Project, parser *ClassParser, catalog *Catalog, options ComponentOptions) []ComponentCandidate {
+	lists := make(map[string]*classList)
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			if !rewritable(element) {
+				continue
+			}
+			var classes []string
+			for _, token := range element.Classes {
+				classes = append(classes, token.Name)
+			}
+			slices.Sort(classes)
+			classes = slices.Compact(classes)
+			if len(classes) < options.MinClasses {
+				continue
+			}
+			key := strings.Join(classes, " ")
+			if lists[key] == nil {
+				lists[key] = &classList{classes: classes}
+			}
+			lists[key].elements = append(lists[key].elements, element)
+		}
+	}
+	var sorted []*classList
+	for _, list := range lists {
+		sorted = append(sorted, list)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if len(a.elements) != len(b.elements) {
+			return len(a.elements) > len(b.elements)
+		}
+		if len(a.classes) != len(b.classes) {
+			return len(a.classes) > len(b.classes)
+		}
+		return strings.Join(a.classes, " ") < strings.Join(b.classes, " ")
+	})
+
+	taken := make(map[string]bool)
+	for _, name := range p.ClassNames() {
+		taken[name] = true
+	}
+	for name := range p.DefinedClasses() {
+		taken[name] = true
+	}
+	claimed := make(map[*classList]bool)
+	var candidates []ComponentCandidate
+	for _, seed := range sorted {
+		if claimed[seed] {
+			continue
+		}
+		claimed[seed] = true
+		shared := seed.classes
+		elements := slices.Clone(seed.elements)
+		members := 1
+		for _, other := range sorted {
+			if claimed[other] || classDifference(seed.classes, other.classes) > options.MaxDifference {
+				continue
+			}
+			common := intersectSorted(shared, other.classes)
+			if len(common) < options.MinClasses {
+				continue
+			}
+			claimed[other] = true
+			shared = common
+			elements = append(elements, other.elements...)
+			members++
+		}
+
+		var apply []string
+		for _, class := range shared {
+			c := parser.Parse(class)
+			if c.Known && (catalog == nil || !catalog.customUtility(c)) {
+				apply = append(apply, class)
+			}
+		}
+		if len(elements) < options.MinOccurrences || len(apply) < options.MinClasses {
+			continue
+		}
+		apply = SortClasses(apply, parser, catalog)
+		candidate := ComponentCandidate{
+			Classes:     apply,
+			Lists:       members,
+			Occurrences: len(elements),
+			Score:       len(elements) * len(apply),
+			elements:    elements,
+		}
+		sort.SliceStable(candidate.elements, func(i, j int) bool {
+			a, b := candidate.elements[i].Pos, candidate.elements[j].Pos
+			if a.File != b.File {
+				return a.File < b.File
+			}
+			return a.Offset < b.Offset
+		})
+		for _, element := range candidate.elements {
+			candidate.Uses = append(candidate.Uses, element.Pos)
+			if !slices.Contains(candidate.Files, element.Pos.File) {
+				candidate.Files = append(candidate.Files, element.Pos.File)
+			}
+		}
+		candidate.Name = componentName(candidate.elements, apply, parser, taken)
+		taken[candidate.Name] = true
+		candidate.CSS = fmt.Sprintf(".%s {\n  @apply %s;\n}\n", candidate.Name, strings.Join(apply, " "))
+		candidates = append(candidates, candidate)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates
+}
+
+// classDifference counts the classes only one of two sorted lists has
+func classDifference(a, b []string) int {
+	return len(a) + len(b) - 2*len(intersectSorted(a, b))
+}
+
+func intersectSorted(a, b []string) []string {
+	var common []string
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			common = append(common, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return common
+}
+
+// componentKinds name components after the element they're mostly on
+var componentKinds = map[string]string{
+	"button": "btn", "a": "link", "input": "field", "select": "field", "textarea": "field",
+	"li": "item", "img": "image", "ul": "list", "ol": "list", "p": "text", "span": "label",
+	"h1": "heading", "h2": "heading", "h3": "heading", "h4": "heading", "h5": "heading", "h6": "heading",
+	"nav": "nav", "table": "table", "label": "label", "section": "section", "form": "form",
+}
+
+// componentName proposes a name from the tag most of the elements have and
+// the color of their background or text, like btn-blue, a number keeps it
+// from clashing with a name that's taken
+func componentName(elements []*Element, classes []string, parser *ClassParser, taken map[string]bool) string {
+	tags := make(map[string]int)
+	for _, element := range elements {
+		tags[strings.ToLower(element.Tag)]++
+	}
+	tag, most := "", 0
+	for t, n := range tags {
+		if n > most || n == most && t < tag {
+			tag, most = t, n
+		}
+	}
+	name, ok := componentKinds[tag]
+	if !ok {
+		name = "box"
+		if tag != "" && tag != "div" && isCSSIdent(tag) {
+			name = tag
+		}
+	}
+	for _, root := range []string{"bg", "text", "border"} {
+		if hint := colorHint(classes, root, parser); hint != "" {
+			name += "-" + hint
+			break
+		}
+	}
+	unique := name
+	for n := 2; taken[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d", name, n)
+	}
+	return unique
+}
+
+// colorHint returns the color name of the first root-color utility of
+// classes without variants, "blue" for bg-blue-600
+func colorHint(classes []string, root string, parser *ClassParser) string {
+	for _, class := range classes {
+		c := parser.Parse(class)
+		if len(c.Variants) > 0 || c.Utility != root || c.Value == "" {
+			continue
+		}
+		if !slices.ContainsFunc(classProperties(c), func(p string) bool { return p == "color" || strings.HasSuffix(p, "-color") }) {
+			continue
+		}
+		color, _, _ := strings.Cut(c.Value, "-")
+		if isCSSIdent(color) {
+			return color
+		}
+	}
+	return ""
+}
+
+func isCSSIdent(s string) bool {
+	name, n := readCSSIdent(s)
+	return name != "" && n == len(s) && name == s
+}
+
+// ComponentEdits returns the edits putting the component class of each
+// candidate in place of the classes it applies on the elements of file,
+// the classes an element has besides them stay
+func ComponentEdits(file *SourceFile, src []byte, candidates []ComponentCandidate) []Edit {
+	inFile := make(map[*Element]bool)
+	for _, element := range file.Elements {
+		inFile[element] = true
+	}
+	var edits []Edit
+	for _, candidate := range candidates {
+		for _, element := range candidate.elements {
+			if !inFile[element] || element.ValueEnd > len(src) {
+				continue
+			}
+			remove := make(map[int]bool)
+			replaced := false
+			for _, token := range element.Classes {
+				if !slices.Contains(candidate.Classes, token.Name) {
+					continue
+				}
+				if !replaced {
+					edits = append(edits, Edit{Start: token.Pos.Offset, End: token.End, Text: candidate.Name})
+					replaced = true
+					continue
+				}
+				remove[token.Pos.Offset] = true
+			}
+			edits = append(edits, removeTokens(src, element, remove)...)
+		}
+	}
+	return edits
+}
+
+// WriteComponentReport writes the candidates with their @apply rules
+func WriteComponentReport(w io.Writer, candidates []ComponentCandidate) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d component candidates\n", len(candidates))
+	for _, c := range candidates {
+		lists := "identical class lists"
+		if c.Lists > 1 {
+			lists = fmt.Sprintf("%d similar class lists", c.Lists)
+		}
+		fmt.Fprintf(&b, "\n%s: %d classes on %d elements in %d files, %s, score %d\n",
+			c.Name, len(c.Classes), c.Occurrences, len(c.Files), lists, c.Score)
+		for i, pos := range c.Uses {
+			if i == 3 {
+				fmt.Fprintf(&b, "  and %d more\n", len(c.Uses)-i)
+				break
+			}
+			fmt.Fprintf(&b, "  %s\n", pos)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(c.CSS, "\n"), "\n") {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
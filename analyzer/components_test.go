@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComponentCandidates(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<button class="px-4 py-2 rounded bg-blue-600 text-white">Save</button>
+<button class="bg-blue-600 text-white rounded px-4 py-2">Send</button>
+<p class="mt-2 text-sm text-gray-500"></p>`,
+		"about.html": `<button class="rounded px-4 py-2 bg-blue-600 text-white font-bold">Go</button>
+<a class="px-4 py-2 rounded bg-blue-600 text-white my-link">Home</a>
+<p class="mt-2 text-sm text-gray-500"></p>`,
+		"style.css": `.btn-blue { color: blue }`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser, catalog := DefaultParser, NewCatalog()
+
+	candidates := ComponentCandidates(project, parser, catalog, DefaultComponentOptions)
+	if len(candidates) != 1 {
+		t.Fatalf("Expected 1 candidate, got %+v", candidates)
+	}
+	c := candidates[0]
+	// btn-blue is taken by the stylesheet
+	if c.Name != "btn-blue-2" {
+		t.Errorf("Expected the name btn-blue-2, got %s", c.Name)
+	}
+	if got := strings.Join(c.Classes, " "); got != "rounded bg-blue-600 px-4 py-2 text-white" {
+		t.Errorf("Expected the classes in canonical order, got %s", got)
+	}
+	if c.Occurrences != 4 || c.Lists != 3 || len(c.Files) != 2 || c.Score != 20 {
+		t.Errorf("Unexpected candidate %+v", c)
+	}
+	if want := ".btn-blue-2 {\n  @apply rounded bg-blue-600 px-4 py-2 text-white;\n}\n"; c.CSS != want {
+		t.Errorf("Expected the rule\n%s\ngot\n%s", want, c.CSS)
+	}
+
+	identical := ComponentCandidates(project, parser, catalog, ComponentOptions{MinOccurrences: 2, MinClasses: 3, MaxDifference: 0})
+	var got []string
+	for _, c := range identical {
+		got = append(got, c.Name+" "+strings.Join(c.Classes, " "))
+	}
+	expected := []string{
+		"btn-blue-2 rounded bg-blue-600 px-4 py-2 text-white",
+		"text-gray mt-2 text-sm text-gray-500",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected the candidates\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	var about *SourceFile
+	for _, file := range project.Files {
+		if file.Path == "about.html" {
+			about = file
+		}
+	}
+	src := []byte(files["about.html"])
+	out, err := ApplyEdits(src, ComponentEdits(about, src, candidates))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<button class="btn-blue-2 font-bold">Go</button>
+<a class="btn-blue-2 my-link">Home</a>
+<p class="mt-2 text-sm text-gray-500"></p>`
+	if string(out) != want {
+		t.Errorf("Expected the page to be\n%s\ngot\n%s", want, out)
+	}
+}
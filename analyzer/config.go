@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// ConfigFile is the name of the analyzer's own config file in the project root
+const ConfigFile = ".css-class-analyzer.json"
+
+// Config is the analyzer's project configuration, everything is optional
+type Config struct {
+	// Allowlist holds globs of classes that are never meant to have styles,
+	// like the "js-*" hooks scripts look elements up by
+	Allowlist []string `json:"allowlist,omitempty"`
+	// Purge holds the safelists of the purge command
+	Purge PurgeOptions `json:"purge,omitempty"`
+	// Complexity holds the selector limits of the complexity command
+	Complexity ComplexityLimits `json:"complexity,omitempty"`
+}
+
+// LoadConfig reads the config file in dir, a missing file is an empty config
+func LoadConfig(dir string) (*Config, error) {
+	config := &Config{}
+	src, err := os.ReadFile(filepath.Join(dir, ConfigFile))
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(src, config); err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile, err)
+	}
+	for _, pattern := range config.Allowlist {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s: bad allowlist pattern %q: %w", ConfigFile, pattern, err)
+		}
+	}
+	if _, _, _, err := config.Purge.compile(); err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile, err)
+	}
+	return config, nil
+}
+
+// Allowed reports whether class matches one of the allowlist globs
+func (c *Config) Allowed(class string) bool {
+	if c == nil {
+		return false
+	}
+	for _, pattern := range c.Allowlist {
+		if ok, _ := path.Match(pattern, class); ok {
+			return true
+		}
+	}
+	return false
+}
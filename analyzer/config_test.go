@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	config, err := LoadConfig(dir)
+	if err != nil || len(config.Allowlist) != 0 {
+		t.Fatalf("Expected an empty config without a file, got %+v and %v", config, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte(`{"allowlist": ["js-*", "is-loading"]}`), 0644)
+	config, err = LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for class, want := range map[string]bool{"js-toggle": true, "is-loading": true, "is-open": false, "xjs-a": false} {
+		if got := config.Allowed(class); got != want {
+			t.Errorf("Expected Allowed(%q) to be %v, got %v", class, want, got)
+		}
+	}
+
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte(`{"complexity": {"maxSpecificity": [0, 3, 0], "maxIds": 0}}`), 0644)
+	config, err = LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits := config.Complexity; limits.MaxSpecificity != (Specificity{0, 3, 0}) || limits.MaxIDs == nil || *limits.MaxIDs != 0 || limits.MaxImportant != nil {
+		t.Errorf("Unexpected complexity limits %+v", limits)
+	}
+
+	os.WriteFile(filepath.Join(dir, ConfigFile), []byte(`{"allowlist": ["[js"]}`), 0644)
+	if _, err := LoadConfig(dir); err == nil {
+		t.Errorf("Expected a bad pattern to fail")
+	}
+}
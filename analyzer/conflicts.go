@@ -0,0 +1,406 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// utilityProperties maps a utility root, or a whole utility name like
+// "flex-row", to the CSS properties it sets. Shorthands keep their own
+// name, p-4 sets padding and px-2 padding-left and padding-right, so a
+// longhand overriding a shorthand on purpose isn't a conflict. Roots whose
+// property depends on the value are in valueProperties
+var utilityProperties = map[string][]string{
+	// layout
+	"aspect": {"aspect-ratio"}, "columns": {"columns"}, "break-after": {"break-after"}, "break-before": {"break-before"},
+	"break-inside": {"break-inside"}, "box-decoration": {"box-decoration-break"}, "box": {"box-sizing"},
+	"block": {"display"}, "inline-block": {"display"}, "inline": {"display"}, "inline-flex": {"display"},
+	"table": {"display"}, "inline-table": {"display"}, "table-caption": {"display"}, "table-cell": {"display"},
+	"table-column": {"display"}, "table-column-group": {"display"}, "table-footer-group": {"display"},
+	"table-header-group": {"display"}, "table-row-group": {"display"}, "table-row": {"display"},
+	"flow-root": {"display"}, "grid": {"display"}, "inline-grid": {"display"}, "contents": {"display"},
+	"list-item": {"display"}, "hidden": {"display"},
+	"float": {"float"}, "clear": {"clear"}, "isolate": {"isolation"}, "isolation-auto": {"isolation"},
+	"overflow": {"overflow"}, "overflow-x": {"overflow-x"}, "overflow-y": {"overflow-y"},
+	"overscroll": {"overscroll-behavior"}, "overscroll-x": {"overscroll-behavior-x"}, "overscroll-y": {"overscroll-behavior-y"},
+	"static": {"position"}, "fixed": {"position"}, "absolute": {"position"}, "relative": {"position"}, "sticky": {"position"},
+	"inset": {"inset"}, "inset-x": {"left", "right"}, "inset-y": {"top", "bottom"},
+	"start": {"inset-inline-start"}, "end": {"inset-inline-end"},
+	"top": {"top"}, "right": {"right"}, "bottom": {"bottom"}, "left": {"left"},
+	"visible": {"visibility"}, "invisible": {"visibility"}, "collapse": {"visibility"}, "z": {"z-index"},
+	// flexbox and grid
+	"basis": {"flex-basis"}, "grow": {"flex-grow"}, "shrink": {"flex-shrink"}, "order": {"order"},
+	"flex-row": {"flex-direction"}, "flex-row-reverse": {"flex-direction"}, "flex-col": {"flex-direction"},
+	"flex-col-reverse": {"flex-direction"}, "flex-wrap": {"flex-wrap"}, "flex-wrap-reverse": {"flex-wrap"},
+	"flex-nowrap": {"flex-wrap"}, "flex-grow": {"flex-grow"}, "flex-shrink": {"flex-shrink"},
+	"grid-cols": {"grid-template-columns"}, "col": {"grid-column"}, "col-span": {"grid-column"},
+	"col-start": {"grid-column-start"}, "col-end": {"grid-column-end"}, "grid-rows": {"grid-template-rows"},
+	"row": {"grid-row"}, "row-span": {"grid-row"}, "row-start": {"grid-row-start"}, "row-end": {"grid-row-end"},
+	"grid-flow": {"grid-auto-flow"}, "auto-cols": {"grid-auto-columns"}, "auto-rows": {"grid-auto-rows"},
+	"gap": {"gap"}, "gap-x": {"column-gap"}, "gap-y": {"row-gap"},
+	"justify": {"justify-content"}, "justify-items": {"justify-items"}, "justify-self": {"justify-self"},
+	"items": {"align-items"}, "self": {"align-self"}, "place-content": {"place-content"},
+	"place-items": {"place-items"}, "place-self": {"place-self"},
+	// spacing
+	"p": {"padding"}, "px": {"padding-left", "padding-right"}, "py": {"padding-top", "padding-bottom"},
+	"ps": {"padding-inline-start"}, "pe": {"padding-inline-end"}, "pt": {"padding-top"}, "pr": {"padding-right"},
+	"pb": {"padding-bottom"}, "pl": {"padding-left"},
+	"m": {"margin"}, "mx": {"margin-left", "margin-right"}, "my": {"margin-top", "margin-bottom"},
+	"ms": {"margin-inline-start"}, "me": {"margin-inline-end"}, "mt": {"margin-top"}, "mr": {"margin-right"},
+	"mb": {"margin-bottom"}, "ml": {"margin-left"},
+	"space-x": {"--tw-space-x"}, "space-y": {"--tw-space-y"},
+	// sizing
+	"w": {"width"}, "min-w": {"min-width"}, "max-w": {"max-width"}, "h": {"height"}, "min-h": {"min-height"},
+	"max-h": {"max-height"}, "size": {"width", "height"},
+	// typography
+	"antialiased": {"-webkit-font-smoothing"}, "subpixel-antialiased": {"-webkit-font-smoothing"},
+	"italic": {"font-style"}, "not-italic": {"font-style"}, "tracking": {"letter-spacing"},
+	"line-clamp": {"-webkit-line-clamp"}, "leading": {"line-height"}, "list-image": {"list-style-image"},
+	"list-inside": {"list-style-position"}, "list-outside": {"list-style-position"},
+	"underline": {"text-decoration-line"}, "overline": {"text-decoration-line"},
+	"line-through": {"text-decoration-line"}, "no-underline": {"text-decoration-line"},
+	"underline-offset": {"text-underline-offset"}, "uppercase": {"text-transform"},
+	"lowercase": {"text-transform"}, "capitalize": {"text-transform"}, "normal-case": {"text-transform"},
+	"truncate": {"overflow", "text-overflow", "white-space"}, "text-ellipsis": {"text-overflow"},
+	"text-clip": {"text-overflow"}, "indent": {"text-indent"}, "align": {"vertical-align"},
+	"whitespace": {"white-space"}, "break-normal": {"overflow-wrap", "word-break"},
+	"break-words": {"overflow-wrap"}, "break-all": {"word-break"}, "break-keep": {"word-break"},
+	"wrap": {"overflow-wrap"}, "hyphens": {"hyphens"}, "placeholder": {"color"},
+	// backgrounds
+	"bg-gradient-to": {"background-image"}, "bg-linear": {"background-image"}, "bg-radial": {"background-image"},
+	"bg-conic": {"background-image"}, "bg-clip": {"background-clip"}, "bg-origin": {"background-origin"},
+	"bg-blend": {"background-blend-mode"}, "from": {"--tw-gradient-from"}, "via": {"--tw-gradient-via"},
+	"to": {"--tw-gradient-to"},
+	// borders
+	"rounded":    {"border-radius"},
+	"rounded-s":  {"border-start-start-radius", "border-end-start-radius"},
+	"rounded-e":  {"border-start-end-radius", "border-end-end-radius"},
+	"rounded-t":  {"border-top-left-radius", "border-top-right-radius"},
+	"rounded-r":  {"border-top-right-radius", "border-bottom-right-radius"},
+	"rounded-b":  {"border-bottom-right-radius", "border-bottom-left-radius"},
+	"rounded-l":  {"border-top-left-radius", "border-bottom-left-radius"},
+	"rounded-ss": {"border-start-start-radius"}, "rounded-se": {"border-start-end-radius"},
+	"rounded-ee": {"border-end-end-radius"}, "rounded-es": {"border-end-start-radius"},
+	"rounded-tl": {"border-top-left-radius"}, "rounded-tr": {"border-top-right-radius"},
+	"rounded-br": {"border-bottom-right-radius"}, "rounded-bl": {"border-bottom-left-radius"},
+	"outline-offset": {"outline-offset"},
+	// effects and filters, the filter utilities each set a variable of
+	// their own so blur and grayscale combine
+	"opacity": {"opacity"}, "mix-blend": {"mix-blend-mode"},
+	"blur": {"--tw-blur"}, "brightness": {"--tw-brightness"}, "contrast": {"--tw-contrast"},
+	"drop-shadow": {"--tw-drop-shadow"}, "grayscale": {"--tw-grayscale"}, "hue-rotate": {"--tw-hue-rotate"},
+	"invert": {"--tw-invert"}, "saturate": {"--tw-saturate"}, "sepia": {"--tw-sepia"},
+	"backdrop-blur": {"--tw-backdrop-blur"}, "backdrop-brightness": {"--tw-backdrop-brightness"},
+	"backdrop-contrast": {"--tw-backdrop-contrast"}, "backdrop-grayscale": {"--tw-backdrop-grayscale"},
+	"backdrop-hue-rotate": {"--tw-backdrop-hue-rotate"}, "backdrop-invert": {"--tw-backdrop-invert"},
+	"backdrop-opacity": {"--tw-backdrop-opacity"}, "backdrop-saturate": {"--tw-backdrop-saturate"},
+	"backdrop-sepia": {"--tw-backdrop-sepia"},
+	// tables
+	"border-collapse": {"border-collapse"}, "border-separate": {"border-collapse"},
+	"border-spacing": {"border-spacing"}, "border-spacing-x": {"--tw-border-spacing-x"},
+	"border-spacing-y": {"--tw-border-spacing-y"}, "table-auto": {"table-layout"}, "table-fixed": {"table-layout"},
+	"caption": {"caption-side"},
+	// transitions and transforms, transforms set a variable per axis too
+	"transition": {"transition-property"}, "duration": {"transition-duration"},
+	"ease": {"transition-timing-function"}, "delay": {"transition-delay"}, "animate": {"animation"},
+	"scale": {"--tw-scale-x", "--tw-scale-y"}, "scale-x": {"--tw-scale-x"}, "scale-y": {"--tw-scale-y"},
+	"rotate": {"--tw-rotate"}, "translate": {"--tw-translate-x", "--tw-translate-y"},
+	"translate-x": {"--tw-translate-x"}, "translate-y": {"--tw-translate-y"},
+	"skew": {"--tw-skew-x", "--tw-skew-y"}, "skew-x": {"--tw-skew-x"}, "skew-y": {"--tw-skew-y"},
+	"origin": {"transform-origin"}, "perspective": {"perspective"},
+	// interactivity
+	"accent": {"accent-color"}, "appearance": {"appearance"}, "cursor": {"cursor"}, "caret": {"caret-color"},
+	"pointer-events": {"pointer-events"}, "resize": {"resize"}, "scroll": {"scroll-behavior"},
+	"scroll-m": {"scroll-margin"}, "scroll-mx": {"scroll-margin-left", "scroll-margin-right"},
+	"scroll-my": {"scroll-margin-top", "scroll-margin-bottom"}, "scroll-ms": {"scroll-margin-inline-start"},
+	"scroll-me": {"scroll-margin-inline-end"}, "scroll-mt": {"scroll-margin-top"},
+	"scroll-mr": {"scroll-margin-right"}, "scroll-mb": {"scroll-margin-bottom"}, "scroll-ml": {"scroll-margin-left"},
+	"scroll-p": {"scroll-padding"}, "scroll-px": {"scroll-padding-left", "scroll-padding-right"},
+	"scroll-py": {"scroll-padding-top", "scroll-padding-bottom"}, "scroll-ps": {"scroll-padding-inline-start"},
+	"scroll-pe": {"scroll-padding-inline-end"}, "scroll-pt": {"scroll-padding-top"},
+	"scroll-pr": {"scroll-padding-right"}, "scroll-pb": {"scroll-padding-bottom"}, "scroll-pl": {"scroll-padding-left"},
+	"touch": {"touch-action"}, "select": {"user-select"}, "will-change": {"will-change"},
+	"field-sizing": {"field-sizing"}, "scheme": {"color-scheme"}, "fill": {"fill"},
+	"forced-color-adjust": {"forced-color-adjust"},
+}
+
+// valueProperties resolves the roots whose property depends on the value,
+// text-sm sets font-size and text-red-500 color
+var valueProperties = map[string]func(class Class) []string{
+	"flex": func(class Class) []string {
+		if class.Value == "" && class.Arbitrary == "" {
+			return []string{"display"}
+		}
+		return []string{"flex"}
+	},
+	"content": func(class Class) []string {
+		if class.Value == "none" || class.Arbitrary != "" {
+			return []string{"content"}
+		}
+		return []string{"align-content"}
+	},
+	"text": func(class Class) []string {
+		if align, ok := textAlign[class.Value]; ok {
+			return []string{align[0]}
+		}
+		switch {
+		case isFontSize(class):
+			if class.Modifier != "" {
+				return []string{"font-size", "line-height"}
+			}
+			return []string{"font-size"}
+		}
+		return []string{"color"}
+	},
+	"font": func(class Class) []string {
+		if _, ok := fontWeights[class.Value]; ok || isDigits(class.Arbitrary) {
+			return []string{"font-weight"}
+		}
+		return []string{"font-family"}
+	},
+	"list": func(class Class) []string {
+		return []string{"list-style-type"}
+	},
+	"decoration": func(class Class) []string {
+		switch {
+		case slices.Contains([]string{"solid", "double", "dotted", "dashed", "wavy"}, class.Value):
+			return []string{"text-decoration-style"}
+		case isDigits(class.Value) || class.Value == "auto" || class.Value == "from-font" || looksLikeLength(class.Arbitrary):
+			return []string{"text-decoration-thickness"}
+		}
+		return []string{"text-decoration-color"}
+	},
+	"bg": func(class Class) []string {
+		switch {
+		case class.Value == "fixed" || class.Value == "local" || class.Value == "scroll":
+			return []string{"background-attachment"}
+		case class.Value == "auto" || class.Value == "cover" || class.Value == "contain":
+			return []string{"background-size"}
+		case strings.HasPrefix(class.Value, "repeat") || class.Value == "no-repeat":
+			return []string{"background-repeat"}
+		case slices.Contains([]string{"bottom", "center", "left", "left-bottom", "left-top", "right", "right-bottom", "right-top", "top"}, class.Value):
+			return []string{"background-position"}
+		case class.Value == "none" || strings.HasPrefix(class.Arbitrary, "url("):
+			return []string{"background-image"}
+		}
+		return []string{"background-color"}
+	},
+	"object": func(class Class) []string {
+		if slices.Contains([]string{"contain", "cover", "fill", "none", "scale-down"}, class.Value) {
+			return []string{"object-fit"}
+		}
+		return []string{"object-position"}
+	},
+	"shadow": func(class Class) []string {
+		if class.Value == "" || class.Value == "none" || class.Value == "inner" || slices.Contains(shadowSizes, class.Value) || class.Arbitrary != "" && !strings.HasPrefix(class.Arbitrary, "color:") {
+			return []string{"box-shadow"}
+		}
+		return []string{"--tw-shadow-color"}
+	},
+	"ring": func(class Class) []string {
+		if class.Value == "" || isDigits(class.Value) || looksLikeLength(class.Arbitrary) {
+			return []string{"--tw-ring-width"}
+		}
+		return []string{"--tw-ring-color"}
+	},
+	"ring-offset": func(class Class) []string {
+		if isDigits(class.Value) || looksLikeLength(class.Arbitrary) {
+			return []string{"--tw-ring-offset-width"}
+		}
+		return []string{"--tw-ring-offset-color"}
+	},
+	"outline": func(class Class) []string {
+		switch {
+		case class.Value == "" || slices.Contains([]string{"dashed", "dotted", "double", "solid"}, class.Value):
+			return []string{"outline-style"}
+		case class.Value == "none" || class.Value == "hidden":
+			return []string{"outline", "outline-offset"}
+		case isDigits(class.Value) || looksLikeLength(class.Arbitrary):
+			return []string{"outline-width"}
+		}
+		return []string{"outline-color"}
+	},
+	"stroke": func(class Class) []string {
+		if isDigits(class.Value) || looksLikeLength(class.Arbitrary) {
+			return []string{"stroke-width"}
+		}
+		return []string{"stroke"}
+	},
+}
+
+// borderSides are the border roots and the side each one's properties are
+// named after, border-t-2 sets border-top-width and border-t-red-500
+// border-top-color
+var borderSides = map[string][]string{
+	"border": {""}, "border-x": {"left", "right"}, "border-y": {"top", "bottom"},
+	"border-s": {"inline-start"}, "border-e": {"inline-end"}, "border-t": {"top"},
+	"border-r": {"right"}, "border-b": {"bottom"}, "border-l": {"left"},
+}
+
+var (
+	borderStyles = []string{"solid", "dashed", "dotted", "double", "hidden", "none"}
+	shadowSizes  = []string{"2xs", "xs", "sm", "md", "lg", "xl", "2xl"}
+)
+
+func init() {
+	for root, sides := range borderSides {
+		root, sides := root, sides
+		valueProperties[root] = func(class Class) []string {
+			part := "color"
+			switch {
+			case root == "border" && slices.Contains(borderStyles, class.Value):
+				return []string{"border-style"}
+			case class.Value == "" || isDigits(class.Value) || looksLikeLength(class.Arbitrary):
+				part = "width"
+			}
+			var properties []string
+			for _, side := range sides {
+				if side == "" {
+					properties = append(properties, "border-"+part)
+				} else {
+					properties = append(properties, "border-"+side+"-"+part)
+				}
+			}
+			return properties
+		}
+	}
+}
+
+func isFontSize(class Class) bool {
+	if _, ok := fontSizeValues[class.Value]; ok {
+		return true
+	}
+	return looksLikeLength(class.Arbitrary)
+}
+
+// classProperties returns the properties a utility sets, nil for classes
+// the table doesn't know
+func classProperties(class Class) []string {
+	if class.Property != "" {
+		return []string{class.Property}
+	}
+	if !class.Known {
+		return nil
+	}
+	if properties, ok := utilityProperties[class.Utility+"-"+class.Value]; ok && class.Value != "" {
+		return properties
+	}
+	if resolve, ok := valueProperties[class.Utility]; ok {
+		return resolve(class)
+	}
+	return utilityProperties[class.Utility]
+}
+
+// Conflicts finds the elements carrying two utilities that set the same
+// property under the same variants, like "p-2 p-4" or "block flex", where
+// the stylesheet order rather than the attribute decides which one wins.
+// An important utility always beats a plain one so those don't conflict,
+// and classes scripts look up aren't one element's classes so they're left
+// out. Custom utilities of the catalog can be anything and are skipped
+func Conflicts(p *Project, parser *ClassParser, catalog *Catalog) []Finding {
+	var findings []Finding
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			if element.Provenance == ProvenanceJS {
+				continue
+			}
+			findings = append(findings, elementConflicts(element, parser, catalog)...)
+		}
+	}
+	return findings
+}
+
+// utilityUse is a class of an element, what it parsed into and the
+// properties it sets
+type utilityUse struct {
+	token      ClassToken
+	class      Class
+	properties []string
+}
+
+// overrides reports whether two utilities setting the same property are
+// meant to, a utility setting part of what another sets like pl-8 next to
+// px-3 is how one side gets overridden and Tailwind always sorts it after
+// the shorthand. Utilities of the same root or setting exactly the same
+// properties have no such order
+func (u utilityUse) overrides(other utilityUse) bool {
+	return u.class.Utility != other.class.Utility && !slices.Equal(u.properties, other.properties)
+}
+
+func elementConflicts(element *Element, parser *ClassParser, catalog *Catalog) []Finding {
+	// setters groups the classes by variants, important flag and property
+	setters := make(map[string][]utilityUse)
+	var keys []string
+	seen := make(map[string]bool)
+	for _, token := range element.Classes {
+		if seen[token.Name] {
+			// repeating a class isn't a conflict with itself
+			continue
+		}
+		seen[token.Name] = true
+		class := parser.Parse(token.Name)
+		if catalog != nil && catalog.customUtility(class) {
+			continue
+		}
+		variants := slices.Clone(class.Variants)
+		slices.Sort(variants)
+		properties := classProperties(class)
+		for _, property := range properties {
+			key := fmt.Sprintf("%s\x00%v\x00%s", strings.Join(variants, ":"), class.Important, property)
+			if _, ok := setters[key]; !ok {
+				keys = append(keys, key)
+			}
+			setters[key] = append(setters[key], utilityUse{token, class, properties})
+		}
+	}
+
+	// classes clashing on several properties, px-2 px-4, get one finding
+	type conflict struct {
+		uses       []utilityUse
+		properties []string
+	}
+	var conflicts []*conflict
+	byGroup := make(map[string]*conflict)
+	for _, key := range keys {
+		var uses []utilityUse
+		for _, use := range setters[key] {
+			for _, other := range setters[key] {
+				if use.token.Name != other.token.Name && !use.overrides(other) {
+					uses = append(uses, use)
+					break
+				}
+			}
+		}
+		if len(uses) < 2 {
+			continue
+		}
+		var names []string
+		for _, use := range uses {
+			names = append(names, use.token.Name)
+		}
+		group := strings.Join(names, " ")
+		c := byGroup[group]
+		if c == nil {
+			c = &conflict{uses: uses}
+			byGroup[group] = c
+			conflicts = append(conflicts, c)
+		}
+		c.properties = append(c.properties, key[strings.LastIndexByte(key, 0)+1:])
+	}
+
+	var findings []Finding
+	for _, c := range conflicts {
+		var names []string
+		for _, use := range c.uses {
+			names = append(names, use.token.Name)
+		}
+		message := fmt.Sprintf("conflicting utilities %s set %s", strings.Join(names, " "), strings.Join(c.properties, ", "))
+		if variants := c.uses[0].class.Variants; len(variants) > 0 {
+			message += " under " + strings.Join(variants, ":")
+		}
+		later := c.uses[1].token
+		findings = append(findings, Finding{Rule: "conflicting-utilities", Pos: later.Pos, Class: later.Name, Message: message})
+	}
+	return findings
+}
@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClassProperties(t *testing.T) {
+	tests := map[string][]string{
+		"p-4":                   {"padding"},
+		"px-2":                  {"padding-left", "padding-right"},
+		"flex":                  {"display"},
+		"flex-1":                {"flex"},
+		"flex-col":              {"flex-direction"},
+		"text-sm":               {"font-size"},
+		"text-sm/6":             {"font-size", "line-height"},
+		"text-[13px]":           {"font-size"},
+		"text-red-500":          {"color"},
+		"text-center":           {"text-align"},
+		"font-bold":             {"font-weight"},
+		"font-mono":             {"font-family"},
+		"border":                {"border-width"},
+		"border-t-2":            {"border-top-width"},
+		"border-x-red-50":       {"border-left-color", "border-right-color"},
+		"border-dashed":         {"border-style"},
+		"bg-cover":              {"background-size"},
+		"bg-blue-500/50":        {"background-color"},
+		"shadow-lg":             {"box-shadow"},
+		"shadow-red-500":        {"--tw-shadow-color"},
+		"ring-2":                {"--tw-ring-width"},
+		"rounded-t-lg":          {"border-top-left-radius", "border-top-right-radius"},
+		"[mask-type:luminance]": {"mask-type"},
+		"not-a-utility":         nil,
+	}
+	for raw, expected := range tests {
+		if got := classProperties(ParseClass(raw)); !slices.Equal(got, expected) {
+			t.Errorf("%s: expected %v, got %v", raw, expected, got)
+		}
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<div class="p-2 p-4 text-sm text-lg"></div>
+<div class="block md:flex flex hidden"></div>
+<div class="px-2 px-4 p-6 pt-1 pl-3 size-4 w-8"></div>
+<div class="p-2 !p-4 hover:p-1 focus:p-3 md:hover:m-1 hover:md:m-2 p-2"></div>
+<div class="text-sm leading-6 text-red-500 font-bold bg-red-500 bg-cover"></div>`,
+		"app.js": `el.classList.add("p-2", "p-4")`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := Conflicts(project, DefaultParser, NewCatalog())
+	expected := []struct {
+		line, column int
+		class        string
+		message      string
+	}{
+		{1, 17, "p-4", "conflicting utilities p-2 p-4 set padding"},
+		{1, 29, "text-lg", "conflicting utilities text-sm text-lg set font-size"},
+		{2, 27, "flex", "conflicting utilities block flex hidden set display"},
+		{3, 18, "px-4", "conflicting utilities px-2 px-4 set padding-left, padding-right"},
+		{4, 55, "hover:md:m-2", "conflicting utilities md:hover:m-1 hover:md:m-2 set margin under md:hover"},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %v", len(expected), findings)
+	}
+	for i, e := range expected {
+		f := findings[i]
+		if f.Pos.Line != e.line || f.Pos.Column != e.column || f.Class != e.class || f.Message != e.message || f.Rule != "conflicting-utilities" {
+			t.Errorf("Expected %d:%d %s %q, got %v", e.line, e.column, e.class, e.message, f)
+		}
+	}
+}
@@ -0,0 +1,442 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// CooccurrenceOptions says which classes and groups of classes are
+// associated strongly enough to report
+type CooccurrenceOptions struct {
+	// MinSupport is how many elements a class or group has to be on
+	MinSupport int
+	// MinConfidence is the least share of the elements with the most common
+	// class of a group that have the whole group
+	MinConfidence float64
+	// MinLift is how much more often than by chance the classes of a group
+	// have to show up together
+	MinLift float64
+	// MaxSize is the most classes a group can have
+	MaxSize int
+	// ClusterConfidence is the least confidence every two classes of a
+	// cluster need
+	ClusterConfidence float64
+}
+
+// DefaultCooccurrenceOptions reports groups of up to 4 classes on 3
+// elements or more, together at least half of the time
+var DefaultCooccurrenceOptions = CooccurrenceOptions{MinSupport: 3, MinConfidence: 0.5, MinLift: 1, MaxSize: 4, ClusterConfidence: 0.8}
+
+// ClassGroup is a set of classes found together on elements. Support is the
+// share of all the elements having them, Confidence the share of the elements
+// with the group's most common class that have the whole group, so 1 means the
+// classes never show up without each other. Lift is how many times more often
+// they're together than they would be by chance
+type ClassGroup struct {
+	Classes    []string `json:"classes"`
+	Elements   int      `json:"elements"`
+	Support    float64  `json:"support"`
+	Confidence float64  `json:"confidence"`
+	Lift       float64  `json:"lift"`
+}
+
+// ClassCluster is a likely design pattern, classes where every two of them
+// go together at least ClusterConfidence of the time. Elements counts the
+// elements having all of them
+type ClassCluster struct {
+	Classes  []string `json:"classes"`
+	Elements int      `json:"elements"`
+	// Cohesion is the lowest confidence of two of its classes
+	Cohesion float64 `json:"cohesion"`
+}
+
+// Cooccurrence is how the classes of a project go together on its elements
+type Cooccurrence struct {
+	// Elements counts the elements with classes, script references aside
+	Elements int `json:"elements"`
+	// Classes counts the elements of each class used on MinSupport
+	// elements or more
+	Classes map[string]int `json:"classes"`
+	// Matrix counts the elements each two of those classes share, both
+	// ways round, pairs never together are left out
+	Matrix   map[string]map[string]int `json:"matrix"`
+	Pairs    []ClassGroup              `json:"pairs"`
+	Groups   []ClassGroup              `json:"groups"`
+	Clusters []ClassCluster            `json:"clusters"`
+	// index has the elements of each class, to count the elements of a
+	// group without going through all of them
+	index map[string]elementSet
+}
+
+// elementSet is a set of element numbers, a bit each
+type elementSet []uint64
+
+func (s elementSet) add(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+// intersectCount counts the elements all of the sets have
+func intersectCount(sets []elementSet) int {
+	n := 0
+	for word := range sets[0] {
+		all := sets[0][word]
+		for _, set := range sets[1:] {
+			all &= set[word]
+		}
+		n += bits.OnesCount64(all)
+	}
+	return n
+}
+
+// AnalyzeCooccurrence builds the co-occurrence matrix of the classes of
+// every element, then finds the pairs and larger groups of classes that are
+// on enough elements and go together often enough, growing groups a class at
+// a time from the ones that qualified. Both support and confidence can only
+// drop as a group grows, so no group is missed. Classes are then clustered,
+// merging the two clusters whose least associated classes are the most
+// associated, until no two clusters are associated enough
+func AnalyzeCooccurrence(p *Project, options CooccurrenceOptions) *Cooccurrence {
+	c := &Cooccurrence{Classes: make(map[string]int), Matrix: make(map[string]map[string]int), index: make(map[string]elementSet)}
+	var elements [][]string
+	counts := make(map[string]int)
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			if element.Provenance == ProvenanceJS || len(element.Classes) == 0 {
+				continue
+			}
+			var classes []string
+			for _, token := range element.Classes {
+				classes = append(classes, token.Name)
+			}
+			slices.Sort(classes)
+			classes = slices.Compact(classes)
+			for _, class := range classes {
+				counts[class]++
+			}
+			elements = append(elements, classes)
+		}
+	}
+	c.Elements = len(elements)
+	for class, n := range counts {
+		if n >= options.MinSupport {
+			c.Classes[class] = n
+		}
+	}
+	// only the classes frequent on their own can be part of a frequent group
+	for class := range c.Classes {
+		c.index[class] = make(elementSet, (len(elements)+63)/64)
+	}
+	for i, classes := range elements {
+		elements[i] = slices.DeleteFunc(classes, func(class string) bool { return c.Classes[class] == 0 })
+		for _, class := range elements[i] {
+			c.index[class].add(i)
+		}
+	}
+	for _, classes := range elements {
+		for i, a := range classes {
+			for _, b := range classes[i+1:] {
+				if c.Matrix[a] == nil {
+					c.Matrix[a] = make(map[string]int)
+				}
+				if c.Matrix[b] == nil {
+					c.Matrix[b] = make(map[string]int)
+				}
+				c.Matrix[a][b]++
+				c.Matrix[b][a]++
+			}
+		}
+	}
+
+	// pairs come from the matrix, larger groups from the level before
+	var level [][]string
+	for a, row := range c.Matrix {
+		for b := range row {
+			if a < b {
+				level = append(level, []string{a, b})
+			}
+		}
+	}
+	for size := 2; size <= options.MaxSize && len(level) > 0; size++ {
+		var next [][]string
+		for _, classes := range level {
+			group := c.group(classes)
+			if group.Elements < options.MinSupport || group.Confidence < options.MinConfidence {
+				continue
+			}
+			next = append(next, classes)
+			if group.Lift < options.MinLift {
+				continue
+			}
+			if size == 2 {
+				c.Pairs = append(c.Pairs, group)
+			} else {
+				c.Groups = append(c.Groups, group)
+			}
+		}
+		level = extendGroups(next)
+	}
+	sortGroups(c.Pairs)
+	sortGroups(c.Groups)
+	c.cluster(options.MinSupport, options.ClusterConfidence)
+	return c
+}
+
+// group measures a sorted set of classes against the elements
+func (c *Cooccurrence) group(classes []string) ClassGroup {
+	group := ClassGroup{Classes: classes}
+	if len(classes) == 2 {
+		group.Elements = c.Matrix[classes[0]][classes[1]]
+	} else {
+		group.Elements = c.elementsWith(classes)
+	}
+	if c.Elements == 0 || group.Elements == 0 {
+		return group
+	}
+	n := float64(c.Elements)
+	group.Support = float64(group.Elements) / n
+	most, chance := 0, 1.0
+	for _, class := range classes {
+		most = max(most, c.Classes[class])
+		chance *= float64(c.Classes[class]) / n
+	}
+	group.Confidence = float64(group.Elements) / float64(most)
+	group.Lift = group.Support / chance
+	return group
+}
+
+// elementsWith counts the elements having all of the classes
+func (c *Cooccurrence) elementsWith(classes []string) int {
+	sets := make([]elementSet, len(classes))
+	for i, class := range classes {
+		sets[i] = c.index[class]
+		if sets[i] == nil {
+			return 0
+		}
+	}
+	return intersectCount(sets)
+}
+
+// extendGroups joins the groups of one size sharing all but their last
+// class into the candidates one class larger, keeping those all of whose
+// smaller groups are among the given ones
+func extendGroups(groups [][]string) [][]string {
+	known := make(map[string]bool)
+	for _, classes := range groups {
+		known[strings.Join(classes, " ")] = true
+	}
+	sort.Slice(groups, func(i, j int) bool { return slices.Compare(groups[i], groups[j]) < 0 })
+	var candidates [][]string
+	for i, a := range groups {
+		for _, b := range groups[i+1:] {
+			k := len(a) - 1
+			if !slices.Equal(a[:k], b[:k]) {
+				break
+			}
+			candidate := append(slices.Clone(a), b[k])
+			ok := true
+			for drop := range candidate[:k] {
+				smaller := slices.Delete(slices.Clone(candidate), drop, drop+1)
+				if !known[strings.Join(smaller, " ")] {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+	return candidates
+}
+
+func sortGroups(groups []ClassGroup) {
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Confidence != b.Confidence {
+			return a.Confidence > b.Confidence
+		}
+		if a.Lift != b.Lift {
+			return a.Lift > b.Lift
+		}
+		if a.Elements != b.Elements {
+			return a.Elements > b.Elements
+		}
+		return slices.Compare(a.Classes, b.Classes) < 0
+	})
+}
+
+// confidence is the confidence of the pair of classes a and b
+func (c *Cooccurrence) confidence(a, b string) float64 {
+	most := max(c.Classes[a], c.Classes[b])
+	if most == 0 {
+		return 0
+	}
+	return float64(c.Matrix[a][b]) / float64(most)
+}
+
+// cluster groups the classes by complete linkage: two clusters are as
+// associated as their two least associated classes, and classes sharing
+// fewer than minSupport elements aren't associated at all. Only the classes
+// associated enough with another one can end up in a cluster, the rest are
+// left out from the start
+func (c *Cooccurrence) cluster(minSupport int, threshold float64) {
+	link := func(a, b string) float64 {
+		if c.Matrix[a][b] < minSupport {
+			return 0
+		}
+		return c.confidence(a, b)
+	}
+	var classes []string
+	for a, row := range c.Matrix {
+		for b := range row {
+			if link(a, b) >= threshold && link(a, b) > 0 {
+				classes = append(classes, a)
+				break
+			}
+		}
+	}
+	sort.Strings(classes)
+	clusters := make([][]string, len(classes))
+	for i, class := range classes {
+		clusters[i] = []string{class}
+	}
+	linkage := make([][]float64, len(classes))
+	for i, a := range classes {
+		linkage[i] = make([]float64, len(classes))
+		for j, b := range classes {
+			linkage[i][j] = link(a, b)
+		}
+	}
+	for {
+		best, bi, bj := -1.0, -1, -1
+		for i := range clusters {
+			if clusters[i] == nil {
+				continue
+			}
+			for j := i + 1; j < len(clusters); j++ {
+				if clusters[j] != nil && linkage[i][j] > best {
+					best, bi, bj = linkage[i][j], i, j
+				}
+			}
+		}
+		if bi < 0 || best < threshold || best == 0 {
+			break
+		}
+		clusters[bi] = append(clusters[bi], clusters[bj]...)
+		clusters[bj] = nil
+		for k := range clusters {
+			linkage[bi][k] = min(linkage[bi][k], linkage[bj][k])
+			linkage[k][bi] = linkage[bi][k]
+		}
+	}
+
+	for _, classes := range clusters {
+		if len(classes) < 2 {
+			continue
+		}
+		sort.Slice(classes, func(a, b int) bool {
+			if c.Classes[classes[a]] != c.Classes[classes[b]] {
+				return c.Classes[classes[a]] > c.Classes[classes[b]]
+			}
+			return classes[a] < classes[b]
+		})
+		cluster := ClassCluster{Classes: classes, Elements: c.elementsWith(classes), Cohesion: 1}
+		for j := range classes {
+			for k := j + 1; k < len(classes); k++ {
+				cluster.Cohesion = min(cluster.Cohesion, c.confidence(classes[j], classes[k]))
+			}
+		}
+		c.Clusters = append(c.Clusters, cluster)
+	}
+	sort.Slice(c.Clusters, func(i, j int) bool {
+		a, b := c.Clusters[i], c.Clusters[j]
+		if len(a.Classes) != len(b.Classes) {
+			return len(a.Classes) > len(b.Classes)
+		}
+		if a.Elements != b.Elements {
+			return a.Elements > b.Elements
+		}
+		return slices.Compare(a.Classes, b.Classes) < 0
+	})
+}
+
+// WriteReport writes the top n pairs and groups and every cluster
+func (c *Cooccurrence) WriteReport(w io.Writer, n int) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d elements, %d classes on enough of them, %d pairs, %d larger groups, %d clusters\n",
+		c.Elements, len(c.Classes), len(c.Pairs), len(c.Groups), len(c.Clusters))
+
+	writeTable := func(title string, groups []ClassGroup) {
+		fmt.Fprintf(&b, "\n%s\n", title)
+		if len(groups) == 0 {
+			b.WriteString("  (none)\n")
+			return
+		}
+		if n > 0 && len(groups) > n {
+			groups = groups[:n]
+		}
+		fmt.Fprintf(&b, "  %-50s %8s %8s %10s %6s\n", "classes", "elements", "support", "confidence", "lift")
+		for _, g := range groups {
+			fmt.Fprintf(&b, "  %-50s %8d %8.3f %10.2f %6.2f\n", strings.Join(g.Classes, " "), g.Elements, g.Support, g.Confidence, g.Lift)
+		}
+	}
+	writeTable("Pairs", c.Pairs)
+	writeTable("Groups", c.Groups)
+
+	b.WriteString("\nClusters\n")
+	if len(c.Clusters) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for i, cluster := range c.Clusters {
+		fmt.Fprintf(&b, "  %d. %s (%d elements have them all, cohesion %.2f)\n",
+			i+1, strings.Join(cluster.Classes, " "), cluster.Elements, cluster.Cohesion)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteDOT writes the pairs as a Graphviz graph, edges get thicker with
+// confidence and every cluster gets a box of its own
+func (c *Cooccurrence) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("graph classes {\n  node [shape=box, style=rounded];\n")
+	nodes := make(map[string]bool)
+	for _, pair := range c.Pairs {
+		nodes[pair.Classes[0]] = true
+		nodes[pair.Classes[1]] = true
+	}
+	for i, cluster := range c.Clusters {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n    label=%s;\n", i+1, dotQuote(fmt.Sprintf("pattern %d", i+1)))
+		for _, class := range cluster.Classes {
+			fmt.Fprintf(&b, "    %s [label=%s];\n", dotQuote(class), dotQuote(fmt.Sprintf("%s\n%d", class, c.Classes[class])))
+			delete(nodes, class)
+		}
+		b.WriteString("  }\n")
+	}
+	var rest []string
+	for class := range nodes {
+		rest = append(rest, class)
+	}
+	sort.Strings(rest)
+	for _, class := range rest {
+		fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote(class), dotQuote(fmt.Sprintf("%s\n%d", class, c.Classes[class])))
+	}
+	for _, pair := range c.Pairs {
+		fmt.Fprintf(&b, "  %s -- %s [label=\"%.2f\", penwidth=%.1f];\n",
+			dotQuote(pair.Classes[0]), dotQuote(pair.Classes[1]), pair.Confidence, 1+4*pair.Confidence)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote quotes s as a Graphviz string, newlines become centered line
+// breaks
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeCooccurrence(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<a class="btn px-4 py-2 rounded"></a>
+<a class="btn px-4 py-2 rounded"></a>
+<button class="btn py-2 px-4 rounded extra"></button>
+<p class="px-4 text-sm"></p>
+<script>el.classList.add("btn", "card")</script>`,
+		"cards.html": `<div class="card shadow p-4"></div>
+<div class="card shadow p-4"></div>
+<div class="shadow card"></div>`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := AnalyzeCooccurrence(project, DefaultCooccurrenceOptions)
+	if c.Elements != 7 {
+		t.Errorf("Expected 7 elements, got %d", c.Elements)
+	}
+	if c.Classes["px-4"] != 4 || c.Classes["p-4"] != 0 || c.Matrix["btn"]["px-4"] != 3 || c.Matrix["px-4"]["btn"] != 3 {
+		t.Errorf("Unexpected counts %v and matrix %v", c.Classes, c.Matrix)
+	}
+
+	format := func(groups []ClassGroup) string {
+		var lines []string
+		for _, g := range groups {
+			lines = append(lines, fmt.Sprintf("%s %d %.2f %.2f %.2f", strings.Join(g.Classes, " "), g.Elements, g.Support, g.Confidence, g.Lift))
+		}
+		return strings.Join(lines, "\n")
+	}
+	pairs := `btn py-2 3 0.43 1.00 2.33
+btn rounded 3 0.43 1.00 2.33
+card shadow 3 0.43 1.00 2.33
+py-2 rounded 3 0.43 1.00 2.33
+btn px-4 3 0.43 0.75 1.75
+px-4 py-2 3 0.43 0.75 1.75
+px-4 rounded 3 0.43 0.75 1.75`
+	if got := format(c.Pairs); got != pairs {
+		t.Errorf("Expected the pairs\n%s\ngot\n%s", pairs, got)
+	}
+	groups := `btn py-2 rounded 3 0.43 1.00 5.44
+btn px-4 py-2 rounded 3 0.43 0.75 9.53
+btn px-4 py-2 3 0.43 0.75 4.08
+btn px-4 rounded 3 0.43 0.75 4.08
+px-4 py-2 rounded 3 0.43 0.75 4.08`
+	if got := format(c.Groups); got != groups {
+		t.Errorf("Expected the groups\n%s\ngot\n%s", groups, got)
+	}
+
+	formatClusters := func(clusters []ClassCluster) string {
+		var lines []string
+		for _, cluster := range clusters {
+			lines = append(lines, fmt.Sprintf("%s %d %.2f", strings.Join(cluster.Classes, " "), cluster.Elements, cluster.Cohesion))
+		}
+		return strings.Join(lines, "\n")
+	}
+	if got, want := formatClusters(c.Clusters), "btn py-2 rounded 3 1.00\ncard shadow 3 1.00"; got != want {
+		t.Errorf("Expected the clusters\n%s\ngot\n%s", want, got)
+	}
+	options := DefaultCooccurrenceOptions
+	options.ClusterConfidence = 0.7
+	loose := AnalyzeCooccurrence(project, options)
+	if got, want := formatClusters(loose.Clusters), "px-4 btn py-2 rounded 3 0.75\ncard shadow 3 1.00"; got != want {
+		t.Errorf("Expected the clusters\n%s\ngot\n%s", want, got)
+	}
+
+	var dot bytes.Buffer
+	if err := c.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"graph classes {\n",
+		"  subgraph cluster_1 {\n    label=\"pattern 1\";\n    \"btn\" [label=\"btn\\n3\"];\n",
+		"  \"px-4\" [label=\"px-4\\n4\"];\n",
+		"  \"btn\" -- \"py-2\" [label=\"1.00\", penwidth=5.0];\n",
+		"  \"btn\" -- \"px-4\" [label=\"0.75\", penwidth=4.0];\n",
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("Expected the graph to contain %q, got\n%s", want, dot.String())
+		}
+	}
+}
+
+func TestIntersectCount(t *testing.T) {
+	a, b, c := make(elementSet, 3), make(elementSet, 3), make(elementSet, 3)
+	for _, i := range []int{0, 63, 64, 130, 150} {
+		a.add(i)
+	}
+	for _, i := range []int{0, 1, 64, 130} {
+		b.add(i)
+	}
+	for _, i := range []int{0, 64, 129, 130, 150} {
+		c.add(i)
+	}
+	if got := intersectCount([]elementSet{a, b, c}); got != 3 {
+		t.Errorf("Expected 3 elements in common, got %d", got)
+	}
+	if got := intersectCount([]elementSet{a, c}); got != 4 {
+		t.Errorf("Expected 4 elements in common, got %d", got)
+	}
+}
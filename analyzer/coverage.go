@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// SelectorCoverage is how many elements a selector of a rule matched,
+// selectors the engine can't evaluate have an Error instead
+type SelectorCoverage struct {
+	Selector string   `json:"selector"`
+	Matches  int      `json:"matches"`
+	Assumed  []string `json:"assumed,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// RuleCoverage is how many elements a style rule matched and on which
+// pages, an element matching several of its selectors counts once
+type RuleCoverage struct {
+	Sheet     string             `json:"sheet"`
+	Pos       Position           `json:"pos"`
+	Selectors []SelectorCoverage `json:"selectors"`
+	Matches   int                `json:"matches"`
+	Pages     []string           `json:"pages,omitempty"`
+}
+
+// Evaluated reports whether every selector of the rule could be evaluated,
+// a rule that matched is evaluated either way
+func (r *RuleCoverage) Evaluated() bool {
+	if r.Matches > 0 {
+		return true
+	}
+	for _, s := range r.Selectors {
+		if s.Error != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Coverage is the rule-level coverage of the project's stylesheets over its
+// HTML pages
+type Coverage struct {
+	Pages []string        `json:"pages"`
+	Rules []*RuleCoverage `json:"rules"`
+}
+
+type coveragePage struct {
+	path     string
+	elements []*html.Node
+}
+
+// ComputeCoverage matches every style rule against every element of the
+// project's .html pages. Markup in other templates can't be evaluated and
+// classes scripts add at runtime aren't there, rules only they match come
+// out unmatched
+func ComputeCoverage(p *Project) (*Coverage, error) {
+	coverage := &Coverage{}
+	var pages []coveragePage
+	for _, file := range p.Files {
+		if ext := strings.ToLower(filepath.Ext(file.Path)); ext != ".html" && ext != ".htm" {
+			continue
+		}
+		f, err := os.Open(p.AbsPath(file.Path))
+		if err != nil {
+			return nil, err
+		}
+		doc, err := html.Parse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Path, err)
+		}
+		page := coveragePage{path: file.Path}
+		findElement(doc, func(n *html.Node) bool {
+			page.elements = append(page.elements, n)
+			return false
+		})
+		pages = append(pages, page)
+		coverage.Pages = append(coverage.Pages, file.Path)
+	}
+
+	for _, sheet := range p.Stylesheets {
+		sheet.walkStyleRules(func(rule *CSSRule, selectors []string) {
+			coverage.Rules = append(coverage.Rules, coverRule(sheet.Path, rule.Pos, selectors, pages))
+		})
+	}
+	return coverage, nil
+}
+
+func coverRule(sheet string, pos Position, selectors []string, pages []coveragePage) *RuleCoverage {
+	rule := &RuleCoverage{Sheet: sheet, Pos: pos}
+	compiled := make([]*Selector, len(selectors))
+	for i, selector := range selectors {
+		rule.Selectors = append(rule.Selectors, SelectorCoverage{Selector: selector})
+		c, err := CompileSelector(selector)
+		if err != nil {
+			rule.Selectors[i].Error = err.Error()
+			continue
+		}
+		compiled[i] = c
+		rule.Selectors[i].Assumed = c.Assumed
+	}
+	for _, page := range pages {
+		matches := 0
+		for _, element := range page.elements {
+			matched := false
+			for i, c := range compiled {
+				if c != nil && c.Match(element) {
+					rule.Selectors[i].Matches++
+					matched = true
+				}
+			}
+			if matched {
+				matches++
+			}
+		}
+		if matches > 0 {
+			rule.Matches += matches
+			rule.Pages = append(rule.Pages, page.path)
+		}
+	}
+	return rule
+}
+
+// WriteReport prints every rule with its matches and the share of rules
+// each stylesheet has matched, unmatchedOnly leaves out the rules that
+// matched something
+func (c *Coverage) WriteReport(w io.Writer, unmatchedOnly bool) error {
+	var b strings.Builder
+	type tally struct{ matched, evaluated int }
+	tallies := make(map[string]*tally)
+	var sheets []string
+	for _, rule := range c.Rules {
+		t := tallies[rule.Sheet]
+		if t == nil {
+			t = &tally{}
+			tallies[rule.Sheet] = t
+			sheets = append(sheets, rule.Sheet)
+		}
+		if rule.Evaluated() {
+			t.evaluated++
+		}
+		if rule.Matches > 0 {
+			t.matched++
+			if unmatchedOnly {
+				continue
+			}
+		}
+
+		var selectors, assumed, errors []string
+		for _, s := range rule.Selectors {
+			selectors = append(selectors, s.Selector)
+			assumed = append(assumed, s.Assumed...)
+			if s.Error != "" {
+				errors = append(errors, s.Error)
+			}
+		}
+		fmt.Fprintf(&b, "%s: %s: ", rule.Pos, strings.Join(selectors, ", "))
+		switch {
+		case rule.Matches > 0:
+			fmt.Fprintf(&b, "%d matches on %d pages (%s)", rule.Matches, len(rule.Pages), listPages(rule.Pages))
+		case len(errors) > 0:
+			fmt.Fprintf(&b, "can't evaluate, %s", errors[0])
+		default:
+			b.WriteString("no matches")
+		}
+		if len(assumed) > 0 {
+			fmt.Fprintf(&b, ", assuming %s", strings.Join(assumed, " "))
+		}
+		b.WriteByte('\n')
+	}
+
+	b.WriteByte('\n')
+	matched, evaluated := 0, 0
+	for _, sheet := range sheets {
+		t := tallies[sheet]
+		matched += t.matched
+		evaluated += t.evaluated
+		fmt.Fprintf(&b, "%-50s %5d of %5d rules matched (%s)\n", sheet, t.matched, t.evaluated, sharePercent(t.matched, t.evaluated))
+	}
+	fmt.Fprintf(&b, "%-50s %5d of %5d rules matched (%s) on %d pages\n", "total", matched, evaluated, sharePercent(matched, evaluated), len(c.Pages))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func listPages(pages []string) string {
+	if len(pages) <= 3 {
+		return strings.Join(pages, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(pages[:3], ", "), len(pages)-3)
+}
+
+func sharePercent(part, whole int) string {
+	if whole == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)/float64(whole)*100)
+}
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComputeCoverage(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<nav class="nav"><ul><li class="item active">Home</li><li class="item">Docs</li></ul></nav>`,
+		"about.html": `<nav class="nav"><ul><li class="item">Home</li></ul></nav><p class="lead">About</p>`,
+		"site.css": `.nav .item { padding: 0 }
+.nav > .item.active { color: red }
+.lead:hover, .lead::first-line { color: blue }
+.card { & .title { margin: 0 } }
+@media (min-width: 640px) { .nav { display: flex } }
+@keyframes spin { from { opacity: 0 } }
+.x:unknown-state { color: green }
+`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coverage, err := ComputeCoverage(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coverage.Pages) != 2 {
+		t.Errorf("Expected 2 pages, got %v", coverage.Pages)
+	}
+
+	rules := make(map[string]*RuleCoverage)
+	for _, rule := range coverage.Rules {
+		rules[rule.Selectors[0].Selector] = rule
+	}
+	if len(rules) != 7 {
+		t.Fatalf("Expected 7 rules, got %d", len(rules))
+	}
+	if r := rules[".nav .item"]; r.Matches != 3 || len(r.Pages) != 2 {
+		t.Errorf("Expected .nav .item to match 3 elements on 2 pages, got %+v", r)
+	}
+	if r := rules[".nav > .item.active"]; r.Matches != 0 || !r.Evaluated() {
+		t.Errorf("Expected .nav > .item.active to match nothing, got %+v", r)
+	}
+	if r := rules[".lead:hover"]; r.Matches != 1 || r.Pages[0] != "about.html" || r.Selectors[1].Assumed[0] != "::first-line" {
+		t.Errorf("Expected .lead to match once assuming its states, got %+v", r)
+	}
+	if r := rules[".card .title"]; r == nil || r.Matches != 0 {
+		t.Errorf("Expected the nested rule to be resolved and unmatched, got %+v", r)
+	}
+	if r := rules[".nav"]; r.Matches != 2 {
+		t.Errorf("Expected the rule in @media to match 2 elements, got %+v", r)
+	}
+	if r := rules[".x:unknown-state"]; r.Evaluated() || r.Selectors[0].Error == "" {
+		t.Errorf("Expected the unknown pseudo-class to be unevaluated, got %+v", r)
+	}
+
+	var b strings.Builder
+	if err := coverage.WriteReport(&b, true); err != nil {
+		t.Fatal(err)
+	}
+	report := b.String()
+	for _, expected := range []string{
+		"site.css:2:1: .nav > .item.active: no matches\n",
+		"site.css:4:9: .card .title: no matches\n",
+		"can't evaluate",
+		"site.css                                               3 of     6 rules matched (50.0%)\n",
+		"on 2 pages\n",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", expected, report)
+		}
+	}
+	if strings.Contains(report, ".nav .item:") {
+		t.Errorf("Expected matched rules to be left out, got:\n%s", report)
+	}
+}
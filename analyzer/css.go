@@ -0,0 +1,386 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gorilla/css/scanner"
+)
+
+// Stylesheet is a parsed CSS file, Source is kept so rules can be cut out of
+// it or rewritten without reformatting anything else. SCSS and Less files
+// parse into the same thing with their nesting resolved
+type Stylesheet struct {
+	Path    string      `json:"path"`
+	Source  string      `json:"-"`
+	Rules   []*CSSRule  `json:"rules"`
+	Extends []Extension `json:"extends,omitempty"`
+}
+
+// CSSRule is a style rule or an at-rule, rules nested in at-rules or with
+// CSS nesting hang off their parent, Start and End delimit the whole rule
+// in the source including its block or closing semicolon
+type CSSRule struct {
+	// AtRule is the at-rule name without the @, empty for style rules
+	AtRule string `json:"atRule,omitempty"`
+	// Prelude is the selector list of a style rule or the params of an at-rule
+	Prelude string `json:"prelude"`
+	// Selectors is the prelude split up, resolved against the parent rules
+	// in SCSS and Less stylesheets
+	Selectors    []string       `json:"selectors,omitempty"`
+	Declarations []*Declaration `json:"declarations,omitempty"`
+	Rules        []*CSSRule     `json:"rules,omitempty"`
+	HasBlock     bool           `json:"hasBlock"`
+	Parent       *CSSRule       `json:"-"`
+	Pos          Position       `json:"pos"`
+	Start        int            `json:"start"`
+	End          int            `json:"end"`
+}
+
+// Declaration is a property: value pair inside a block
+type Declaration struct {
+	Property  string   `json:"property"`
+	Value     string   `json:"value"`
+	Important bool     `json:"important,omitempty"`
+	Pos       Position `json:"pos"`
+}
+
+// ClassDefinition is a class selector found in a stylesheet
+type ClassDefinition struct {
+	Class    string   `json:"class"`
+	Selector string   `json:"selector"`
+	Pos      Position `json:"pos"`
+}
+
+// styleParsers maps a file extension to the parser for that kind of stylesheet
+var styleParsers = map[string]func(path string, src []byte) (*Stylesheet, error){
+	".css":  ParseCSS,
+	".scss": ParseSCSS,
+	".less": ParseLess,
+}
+
+// cssToken is a scanner token with its byte offset, the scanner's own
+// columns are off after newlines so positions are computed from offsets
+type cssToken struct {
+	kind   scanner.Token
+	offset int
+}
+
+// ParseCSS tokenizes src with gorilla/css and builds the rule tree out of
+// the tokens, it's forgiving the same way browsers are
+func ParseCSS(path string, src []byte) (*Stylesheet, error) {
+	sheet := &Stylesheet{Path: path, Source: string(src)}
+	tokens, err := tokenizeCSS(sheet.Source)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	p := &cssParser{sheet: sheet, tokens: tokens, lines: newLineIndex(src)}
+	sheet.Rules, _ = p.parseBlock(nil)
+	return sheet, nil
+}
+
+func tokenizeCSS(src string) ([]cssToken, error) {
+	s := scanner.New(src)
+	var tokens []cssToken
+	offset := 0
+	for {
+		token := s.Next()
+		switch token.Type {
+		case scanner.TokenEOF:
+			return tokens, nil
+		case scanner.TokenError:
+			return nil, fmt.Errorf("%s at offset %d", token.Value, offset)
+		}
+		tokens = append(tokens, cssToken{kind: *token, offset: offset})
+		offset += len(token.Value)
+	}
+}
+
+type cssParser struct {
+	sheet  *Stylesheet
+	tokens []cssToken
+	lines  lineIndex
+	i      int
+}
+
+func (p *cssParser) end() int {
+	if p.i < len(p.tokens) {
+		return p.tokens[p.i].offset
+	}
+	return len(p.sheet.Source)
+}
+
+func isCSSChar(t cssToken, ch string) bool {
+	return t.kind.Type == scanner.TokenChar && t.kind.Value == ch
+}
+
+func isCSSTrivia(t cssToken) bool {
+	switch t.kind.Type {
+	case scanner.TokenS, scanner.TokenComment, scanner.TokenCDO, scanner.TokenCDC, scanner.TokenBOM:
+		return true
+	}
+	return false
+}
+
+// parseBlock reads rules and declarations until the '}' closing the block
+// (which it consumes) or the end of the input
+func (p *cssParser) parseBlock(parent *CSSRule) (rules []*CSSRule, declarations []*Declaration) {
+	for p.i < len(p.tokens) {
+		t := p.tokens[p.i]
+		switch {
+		case isCSSTrivia(t) || isCSSChar(t, ";"):
+			p.i++
+			continue
+		case isCSSChar(t, "}"):
+			p.i++
+			return rules, declarations
+		}
+
+		start := t.offset
+		rule := &CSSRule{Parent: parent, Pos: p.lines.position(p.sheet.Path, start), Start: start}
+		if t.kind.Type == scanner.TokenAtKeyword {
+			rule.AtRule = strings.ToLower(t.kind.Value[1:])
+			p.i++
+		}
+		prelude, terminator := p.readPrelude()
+		switch {
+		case terminator == "{":
+			rule.Prelude = prelude
+			rule.HasBlock = true
+			if rule.AtRule == "" {
+				rule.Selectors = splitSelectorList(prelude)
+			}
+			rule.Rules, rule.Declarations = p.parseBlock(rule)
+			rule.End = p.end()
+			rules = append(rules, rule)
+		case rule.AtRule != "":
+			rule.Prelude = prelude
+			if terminator == ";" {
+				p.i++
+			}
+			rule.End = p.end()
+			rules = append(rules, rule)
+		default:
+			if terminator == ";" {
+				p.i++
+			}
+			if d := parseDeclaration(prelude, rule.Pos); d != nil {
+				declarations = append(declarations, d)
+			}
+		}
+	}
+	return rules, declarations
+}
+
+// readPrelude collects the text up to the next top-level '{', ';' or '}',
+// consuming the '{' but leaving ';' and '}' for the caller
+func (p *cssParser) readPrelude() (string, string) {
+	var b strings.Builder
+	depth := 0
+	for p.i < len(p.tokens) {
+		t := p.tokens[p.i]
+		if t.kind.Type == scanner.TokenFunction {
+			depth++
+		}
+		if t.kind.Type == scanner.TokenChar {
+			switch t.kind.Value {
+			case "(", "[":
+				depth++
+			case ")", "]":
+				depth--
+			case "{":
+				if depth <= 0 {
+					p.i++
+					return collapseSpace(b.String()), "{"
+				}
+			case ";", "}":
+				if depth <= 0 {
+					return collapseSpace(b.String()), t.kind.Value
+				}
+			}
+		}
+		if t.kind.Type == scanner.TokenComment {
+			b.WriteByte(' ')
+		} else {
+			b.WriteString(t.kind.Value)
+		}
+		p.i++
+	}
+	return collapseSpace(b.String()), ""
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func parseDeclaration(text string, pos Position) *Declaration {
+	property, value, ok := strings.Cut(text, ":")
+	if !ok {
+		return nil
+	}
+	d := &Declaration{Property: strings.TrimSpace(property), Value: strings.TrimSpace(value), Pos: pos}
+	if i := strings.LastIndex(d.Value, "!"); i >= 0 && strings.EqualFold(strings.TrimSpace(d.Value[i+1:]), "important") {
+		d.Important = true
+		d.Value = strings.TrimSpace(d.Value[:i])
+	}
+	return d
+}
+
+// splitSelectorList splits a selector list on its top-level commas
+func splitSelectorList(prelude string) []string {
+	var selectors []string
+	for _, s := range splitTopLevel(prelude, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			selectors = append(selectors, s)
+		}
+	}
+	return selectors
+}
+
+// Walk calls fn for every rule of the stylesheet, parents before children
+func (s *Stylesheet) Walk(fn func(rule *CSSRule)) {
+	var walk func(rules []*CSSRule)
+	walk = func(rules []*CSSRule) {
+		for _, rule := range rules {
+			fn(rule)
+			walk(rule.Rules)
+		}
+	}
+	walk(s.Rules)
+}
+
+// walkStyleRules calls fn for every style rule with its selectors resolved
+// against the parent rules, SCSS and Less selectors come resolved already
+// and CSS nesting is resolved here. Keyframe steps are left out
+func (s *Stylesheet) walkStyleRules(fn func(rule *CSSRule, selectors []string)) {
+	nest := !strings.HasSuffix(s.Path, ".scss") && !strings.HasSuffix(s.Path, ".less")
+	var walk func(rules []*CSSRule, parents []string)
+	walk = func(rules []*CSSRule, parents []string) {
+		for _, rule := range rules {
+			switch {
+			case rule.AtRule == "keyframes" || strings.HasSuffix(rule.AtRule, "-keyframes"):
+			case rule.AtRule != "":
+				walk(rule.Rules, parents)
+			case len(rule.Selectors) > 0:
+				selectors := rule.Selectors
+				if nest {
+					selectors = nestSelectors(parents, selectors)
+				}
+				fn(rule, selectors)
+				walk(rule.Rules, selectors)
+			}
+		}
+	}
+	walk(s.Rules, nil)
+}
+
+// Definitions lists every class selector of the stylesheet's style rules,
+// keyframe steps and at-rule preludes are left out
+func (s *Stylesheet) Definitions() []ClassDefinition {
+	var definitions []ClassDefinition
+	s.Walk(func(rule *CSSRule) {
+		if rule.AtRule != "" || rule.Parent != nil && rule.Parent.AtRule == "keyframes" {
+			return
+		}
+		for _, selector := range rule.Selectors {
+			for _, class := range selectorClasses(selector) {
+				definitions = append(definitions, ClassDefinition{Class: class, Selector: selector, Pos: rule.Pos})
+			}
+		}
+	})
+	return definitions
+}
+
+// selectorClasses returns the unescaped class names a selector mentions,
+// including the ones inside :is(), :not() and friends. Names built with
+// Sass or Less interpolation, ".icon-#{$name}", aren't known statically
+// and are left out
+func selectorClasses(selector string) []string {
+	var classes []string
+	for _, span := range selectorClassSpans(selector) {
+		classes = append(classes, span.name)
+	}
+	return classes
+}
+
+// classSpan is a class name in a selector, start and end delimit it in the
+// selector without the dot and with any escapes
+type classSpan struct {
+	name       string
+	start, end int
+}
+
+// selectorClassSpans is selectorClasses keeping where each name is
+func selectorClassSpans(selector string) []classSpan {
+	var spans []classSpan
+	for i := 0; i < len(selector); i++ {
+		switch ch := selector[i]; ch {
+		case '\\':
+			i++
+		case '"', '\'':
+			// skip strings in attribute selectors
+			for i++; i < len(selector) && selector[i] != ch; i++ {
+				if selector[i] == '\\' {
+					i++
+				}
+			}
+		case '[':
+			for i < len(selector) && selector[i] != ']' {
+				if selector[i] == '"' || selector[i] == '\'' {
+					quote := selector[i]
+					for i++; i < len(selector) && selector[i] != quote; i++ {
+					}
+				}
+				i++
+			}
+		case '.':
+			name, n := readCSSIdent(selector[i+1:])
+			rest := selector[i+1+n:]
+			if name != "" && !strings.HasPrefix(rest, "#{") && !strings.HasPrefix(rest, "@{") {
+				spans = append(spans, classSpan{name, i + 1, i + 1 + n})
+			}
+			i += n
+		}
+	}
+	return spans
+}
+
+// readCSSIdent reads an identifier at the start of s, resolving escapes like
+// "\:" and "\32 ", and returns it with the number of bytes it took up
+func readCSSIdent(s string) (string, int) {
+	var b strings.Builder
+	i := 0
+	for i < len(s) {
+		ch := s[i]
+		switch {
+		case ch == '\\' && i+1 < len(s):
+			if isHexDigit(s[i+1]) {
+				j := i + 1
+				for j < len(s) && j < i+7 && isHexDigit(s[j]) {
+					j++
+				}
+				var r rune
+				fmt.Sscanf(s[i+1:j], "%x", &r)
+				b.WriteRune(r)
+				if j < len(s) && (s[j] == ' ' || s[j] == '\t' || s[j] == '\n') {
+					j++
+				}
+				i = j
+			} else {
+				b.WriteByte(s[i+1])
+				i += 2
+			}
+		case ch == '-' || ch == '_' || ch >= 0x80 ||
+			(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9'):
+			b.WriteByte(ch)
+			i++
+		default:
+			return b.String(), i
+		}
+	}
+	return b.String(), i
+}
+
+func isHexDigit(ch byte) bool {
+	return (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
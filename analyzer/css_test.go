@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCSS(t *testing.T) {
+	sampleCSS := `@import "base.css";
+/* buttons */
+.btn, .btn-primary:hover { color: red; background: url(x.png) !important }
+@media (min-width: 640px) {
+  .sm\:p-4 { padding: 1rem }
+  .card { & .card__title { font-weight: bold } }
+}
+@keyframes spin { from { transform: rotate(0) } to { transform: rotate(360deg) } }
+`
+	sheet, err := ParseCSS("sample.css", []byte(sampleCSS))
+	if err != nil {
+		t.Fatalf("ParseCSS failed: %s", err)
+	}
+	if len(sheet.Rules) != 4 {
+		t.Fatalf("Expected 4 top-level rules, got %d", len(sheet.Rules))
+	}
+
+	importRule := sheet.Rules[0]
+	if importRule.AtRule != "import" || importRule.Prelude != `"base.css"` || importRule.HasBlock {
+		t.Errorf("Unexpected import rule %+v", importRule)
+	}
+	buttons := sheet.Rules[1]
+	if !slices.Equal(buttons.Selectors, []string{".btn", ".btn-primary:hover"}) || buttons.Pos.Line != 3 {
+		t.Errorf("Unexpected button rule %+v", buttons)
+	}
+	if len(buttons.Declarations) != 2 || !buttons.Declarations[1].Important || buttons.Declarations[1].Value != "url(x.png)" {
+		t.Errorf("Unexpected declarations %+v", buttons.Declarations)
+	}
+	if sampleCSS[buttons.Start:buttons.End] != ".btn, .btn-primary:hover { color: red; background: url(x.png) !important }" {
+		t.Errorf("Unexpected rule source %q", sampleCSS[buttons.Start:buttons.End])
+	}
+	media := sheet.Rules[2]
+	if media.AtRule != "media" || media.Prelude != "(min-width: 640px)" || len(media.Rules) != 2 {
+		t.Errorf("Unexpected media rule %+v", media)
+	}
+
+	var defined []string
+	for _, d := range sheet.Definitions() {
+		defined = append(defined, d.Class)
+	}
+	expected := []string{"btn", "btn-primary", "sm:p-4", "card", "card__title"}
+	if !slices.Equal(defined, expected) {
+		t.Errorf("Expected definitions %v, got %v", expected, defined)
+	}
+}
+
+func TestSelectorClasses(t *testing.T) {
+	tests := map[string][]string{
+		`.hover\:bg-blue-700:hover`:          {"hover:bg-blue-700"},
+		`.w-1\/2`:                            {"w-1/2"},
+		`.\32xl\:p-4`:                        {"2xl:p-4"},
+		`a[href=".pdf"] > .icon`:             {"icon"},
+		`:is(.a, .b) .c:not(.d)`:             {"a", "b", "c", "d"},
+		`.-ml-\[40rem\]`:                     {"-ml-[40rem]"},
+		`div#main.card.card--active::before`: {"card", "card--active"},
+	}
+	for selector, expected := range tests {
+		if received := selectorClasses(selector); !slices.Equal(received, expected) {
+			t.Errorf("%s: expected %v, got %v", selector, expected, received)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ProvenanceJS marks the elements that are class references in script
+// rather than markup, classes a script adds or looks for at runtime
+const ProvenanceJS = "js-runtime"
+
+var (
+	// domCallPattern matches the DOM and jQuery calls taking classes or
+	// selectors up to their opening parenthesis, jQuery's traversal methods
+	// have names too common to go by and are only read off a jQuery chain
+	domCallPattern = regexp.MustCompile(`(?:\.\s*(classList\s*\.\s*(?:add|remove|toggle|contains|replace)|querySelectorAll|querySelector|closest|matches|getElementsByClassName|setAttribute|addClass|removeClass|toggleClass|hasClass)|(?:^|[^\w$.])(\$|jQuery))\s*\(`)
+	// jqueryVariablePattern matches the variables holding jQuery objects by
+	// convention, $menu or this.$el
+	jqueryVariablePattern = regexp.MustCompile(`(?:^|[^\w$])(\$[A-Za-z_][\w$]*)`)
+	// classNamePattern matches el.className = and el.className +=
+	classNamePattern = regexp.MustCompile(`\.\s*(className)\s*\+?=\s*`)
+)
+
+// scriptTypes are the type attributes of <script> elements holding
+// JavaScript, templates and JSON data don't
+var scriptTypes = toSet("", "text/javascript", "application/javascript", "module", "text/babel")
+
+func isJavaScript(tag []byte, attrs []rawAttr) bool {
+	for _, a := range attrs {
+		if a.key == "type" {
+			_, ok := scriptTypes[strings.ToLower(strings.TrimSpace(string(tag[a.start:a.end])))]
+			return ok
+		}
+	}
+	return true
+}
+
+// jqueryTraversals are the jQuery methods taking a selector that other
+// objects have methods named like, arr.filter('x') or str.find('y')
+var jqueryTraversals = toSet("find", "children", "parents", "parent", "siblings", "next", "prev", "is", "filter", "not", "has")
+
+// classListCalls take class names, the other calls take selectors
+var classListCalls = toSet("classList.add", "classList.remove", "classList.toggle", "classList.contains",
+	"classList.replace", "getElementsByClassName", "addClass", "removeClass", "toggleClass", "hasClass", "className")
+
+// stringLiteral is a string literal, start and end delimit its contents
+type stringLiteral struct {
+	value      string
+	start, end int
+	// escaped is set when the value had escapes decoded, its offsets don't
+	// line up with the source anymore and offsets maps them back: byte i
+	// of the value starts at offsets[i] in the source, offsets[len(value)]
+	// is end
+	escaped bool
+	offsets []int
+}
+
+// sourceSpan returns where the bytes start to end of the value are in the
+// source
+func (l stringLiteral) sourceSpan(start, end int) (int, int) {
+	if !l.escaped {
+		return l.start + start, l.start + end
+	}
+	return l.offsets[start], l.offsets[end]
+}
+
+// domReferences finds the classes scripts add, remove, toggle, assign or
+// select with, every string literal becomes an element of its own tagged
+// with ProvenanceJS
+func domReferences(text, path string, lines lineIndex) []*Element {
+	var elements []*Element
+	add := func(call string, callStart int, literal stringLiteral) {
+		element := &Element{
+			Tag:        call,
+			Pos:        lines.position(path, callStart),
+			ValueStart: literal.start,
+			ValueEnd:   literal.end,
+			Provenance: ProvenanceJS,
+		}
+		token := func(name string, start, end int) {
+			start, end = literal.sourceSpan(start, end)
+			element.Classes = append(element.Classes, ClassToken{Name: name, Pos: lines.position(path, start), End: end})
+		}
+		if _, isList := classListCalls[call]; isList {
+			for _, field := range fieldSpans(literal.value) {
+				token(literal.value[field[0]:field[1]], field[0], field[1])
+			}
+		} else {
+			for _, span := range selectorClassSpans(literal.value) {
+				token(span.name, span.start, span.end)
+			}
+		}
+		if len(element.Classes) > 0 {
+			elements = append(elements, element)
+		}
+	}
+
+	for _, m := range domCallPattern.FindAllStringSubmatchIndex(text, -1) {
+		callStart, callEnd := m[2], m[3]
+		if callStart < 0 {
+			callStart, callEnd = m[4], m[5]
+		}
+		call := strings.Join(strings.Fields(strings.ReplaceAll(text[callStart:callEnd], ".", " . ")), "")
+		arguments := stringArguments(text, m[1])
+		switch {
+		case len(arguments) == 0:
+			continue
+		case call == "setAttribute":
+			// setAttribute('class', 'a b') sets the class list
+			if arguments[0].value != "class" || len(arguments) < 2 {
+				continue
+			}
+			call, arguments = "className", arguments[1:2]
+		case call == "$" || call == "jQuery":
+			// $('<div class="x">') creates markup rather than selecting
+			if strings.HasPrefix(strings.TrimSpace(arguments[0].value), "<") {
+				continue
+			}
+		}
+		if _, isList := classListCalls[call]; !isList {
+			arguments = arguments[:1]
+		}
+		for _, literal := range arguments {
+			add(call, callStart, literal)
+		}
+		if call == "$" || call == "jQuery" {
+			if end := matchBracket(text, m[1]-1); end > 0 {
+				jqueryChain(text, end, add)
+			}
+		}
+	}
+	for _, m := range jqueryVariablePattern.FindAllStringSubmatchIndex(text, -1) {
+		jqueryChain(text, m[3], add)
+	}
+	for _, m := range classNamePattern.FindAllStringSubmatchIndex(text, -1) {
+		// el.className == 'x' compares
+		if m[1] < len(text) && text[m[1]] == '=' {
+			continue
+		}
+		if literal, _, ok := readStringLiteral(text, m[1]); ok {
+			add("className", m[2], literal)
+		}
+	}
+	sort.SliceStable(elements, func(i, j int) bool { return elements[i].Pos.Offset < elements[j].Pos.Offset })
+	return elements
+}
+
+// jqueryChain reads the method calls chained on the jQuery object ending at
+// text[i] and adds the selectors its traversals take, .find('.item') in
+// $('.menu').addClass('open').find('.item')
+func jqueryChain(text string, i int, add func(call string, callStart int, literal stringLiteral)) {
+	for {
+		i = skipSpace(text, i)
+		if i >= len(text) || text[i] != '.' {
+			return
+		}
+		i = skipSpace(text, i+1)
+		nameStart := i
+		for i < len(text) && isIdentifierByte(text[i]) {
+			i++
+		}
+		name := text[nameStart:i]
+		i = skipSpace(text, i)
+		if name == "" || i >= len(text) || text[i] != '(' {
+			return
+		}
+		if _, ok := jqueryTraversals[name]; ok {
+			if arguments := stringArguments(text, i+1); len(arguments) > 0 {
+				add(name, nameStart, arguments[0])
+			}
+		}
+		if i = matchBracket(text, i); i < 0 {
+			return
+		}
+	}
+}
+
+// matchBracket returns the offset after the bracket closing the (, [ or {
+// at text[i], or -1 when it isn't closed. Brackets in strings don't count
+func matchBracket(text string, i int) int {
+	open := text[i]
+	closing := map[byte]byte{'(': ')', '[': ']', '{': '}'}[open]
+	depth := 0
+	for ; i < len(text); i++ {
+		switch text[i] {
+		case open:
+			depth++
+		case closing:
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		case '"', '\'', '`':
+			if _, next, ok := readStringLiteral(text, i); ok {
+				i = next - 1
+			}
+		}
+	}
+	return -1
+}
+
+func skipSpace(text string, i int) int {
+	for i < len(text) && isHTMLSpace(text[i]) {
+		i++
+	}
+	return i
+}
+
+func isIdentifierByte(ch byte) bool {
+	return ch == '_' || ch == '$' || 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || '0' <= ch && ch <= '9'
+}
+
+// stringArguments reads the arguments starting at text[i] up to the first
+// one that isn't a plain string literal
+func stringArguments(text string, i int) []stringLiteral {
+	var arguments []stringLiteral
+	for {
+		for i < len(text) && isHTMLSpace(text[i]) {
+			i++
+		}
+		literal, next, ok := readStringLiteral(text, i)
+		if !ok {
+			return arguments
+		}
+		arguments = append(arguments, literal)
+		for i = next; i < len(text) && isHTMLSpace(text[i]); i++ {
+		}
+		if i >= len(text) || text[i] != ',' {
+			return arguments
+		}
+		i++
+	}
+}
+
+// readStringLiteral reads the string literal at text[i] and returns it with the
+// offset after its closing quote, template literals with substitutions
+// aren't known statically
+func readStringLiteral(text string, i int) (stringLiteral, int, bool) {
+	if i >= len(text) || (text[i] != '"' && text[i] != '\'' && text[i] != '`') {
+		return stringLiteral{}, i, false
+	}
+	quote := text[i]
+	literal := stringLiteral{start: i + 1}
+	var b strings.Builder
+	for j := i + 1; j < len(text); j++ {
+		literal.offsets = append(literal.offsets, j)
+		switch ch := text[j]; {
+		case ch == quote:
+			literal.end = j
+			literal.value = text[literal.start:j]
+			if literal.escaped {
+				literal.value = b.String()
+			} else {
+				literal.offsets = nil
+			}
+			return literal, j + 1, true
+		case ch == '\n' && quote != '`':
+			return stringLiteral{}, j, false
+		case ch == '$' && quote == '`' && j+1 < len(text) && text[j+1] == '{':
+			return stringLiteral{}, j, false
+		case ch == '\\' && j+1 < len(text):
+			literal.escaped = true
+			j++
+			switch text[j] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(text[j])
+			}
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return stringLiteral{}, len(text), false
+}
+
+// fieldSpans returns where the whitespace separated fields of s are
+func fieldSpans(s string) [][2]int {
+	var spans [][2]int
+	for i := 0; i < len(s); {
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		start := i
+		for i < len(s) && !isHTMLSpace(s[i]) {
+			i++
+		}
+		if start < i {
+			spans = append(spans, [2]int{start, i})
+		}
+	}
+	return spans
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDOMReferences(t *testing.T) {
+	src := `const menu = document.querySelector('.menu .is-open, #nav > li.active');
+menu.classList.add('is-open', "is-visible");
+menu.classList.toggle('is-hidden', !open);
+menu.classList.add(` + "`is-${state}`" + `);
+button.closest(".card__body");
+el.className += ' has-error';
+if (el.className == 'nope') {}
+el.setAttribute('class', 'alert alert-info');
+document.getElementsByClassName('tab tab--active');
+$('.dropdown').addClass('show').find('.dropdown-item');
+$('<div class="created">');
+jQuery(".sm\\:p-4");
+menu.classList.add('one\ttwo');
+arr.filter("x"); str.find('.y').not('.z');
+$menu.find('.menu-item').filter(match(')')).not(".is-done");
+`
+	elements := domReferences(src, "app.js", newLineIndex([]byte(src)))
+
+	var got [][]string
+	for _, element := range elements {
+		if element.Provenance != ProvenanceJS {
+			t.Errorf("Expected %s provenance, got %q", ProvenanceJS, element.Provenance)
+		}
+		entry := []string{element.Tag}
+		for _, class := range element.Classes {
+			entry = append(entry, class.Name)
+		}
+		got = append(got, entry)
+	}
+	expected := [][]string{
+		{"querySelector", "menu", "is-open", "active"},
+		{"classList.add", "is-open"},
+		{"classList.add", "is-visible"},
+		{"classList.toggle", "is-hidden"},
+		{"closest", "card__body"},
+		{"className", "has-error"},
+		{"className", "alert", "alert-info"},
+		{"getElementsByClassName", "tab", "tab--active"},
+		{"$", "dropdown"},
+		{"addClass", "show"},
+		{"find", "dropdown-item"},
+		{"jQuery", "sm:p-4"},
+		{"classList.add", "one", "two"},
+		{"find", "menu-item"},
+		{"not", "is-done"},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if !slices.Equal(got[i], expected[i]) {
+			t.Errorf("Expected %v, got %v", expected[i], got[i])
+		}
+	}
+
+	active := elements[0].Classes[2]
+	if active.Pos.Line != 1 || active.Pos.Column != 64 || src[active.Pos.Offset:active.End] != "active" {
+		t.Errorf("Unexpected position of active %+v", active)
+	}
+	// escaped literals still give every class a span of its own
+	escaped := elements[11].Classes[0]
+	if want := `sm\\:p-4`; src[escaped.Pos.Offset:escaped.End] != want {
+		t.Errorf("Expected the span of sm:p-4 to be %s, got %s", want, src[escaped.Pos.Offset:escaped.End])
+	}
+	if two := elements[12].Classes[1]; src[two.Pos.Offset:two.End] != "two" {
+		t.Errorf("Expected the span of two to be two, got %s", src[two.Pos.Offset:two.End])
+	}
+	if visible := elements[2]; src[visible.ValueStart:visible.ValueEnd] != "is-visible" || visible.Pos.Line != 2 {
+		t.Errorf("Unexpected is-visible element %+v", visible)
+	}
+}
+
+func TestExtractHTMLScripts(t *testing.T) {
+	page := `<div class="menu">
+<script>document.body.classList.add('js-ready')</script>
+<script type="text/template"><p class="tpl"></p> el.classList.add('nope')</script>
+</div>`
+	file, err := extractHTML("index.html", []byte(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Elements) != 2 {
+		t.Fatalf("Expected the div and the script reference, got %+v", file.Elements)
+	}
+	ready := file.Elements[1]
+	if ready.Provenance != ProvenanceJS || ready.Classes[0].Name != "js-ready" || ready.Classes[0].Pos.Line != 2 || ready.Classes[0].Pos.Column != 38 {
+		t.Errorf("Unexpected script reference %+v", ready)
+	}
+}
+
+func TestFindingProvenance(t *testing.T) {
+	f := Finding{Rule: "unknown-class", Pos: Position{File: "app.js", Line: 2, Column: 21}, Message: `unknown class "is-opne"`, Provenance: ProvenanceJS}
+	if expected := `app.js:2:21: unknown class "is-opne" (unknown-class, js-runtime)`; f.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, f.String())
+	}
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DuplicateClasses returns a finding for every class repeated within one
+// class attribute, at the repetition. Classes scripts look up aren't an
+// attribute and are left out
+func DuplicateClasses(p *Project) []Finding {
+	var findings []Finding
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			for _, d := range elementDuplicates(element) {
+				findings = append(findings, Finding{
+					Rule:    "duplicate-class",
+					Pos:     d.token.Pos,
+					Class:   d.token.Name,
+					Message: fmt.Sprintf("class %q is already in this attribute at column %d", d.token.Name, d.first.Column),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+type duplicateToken struct {
+	token ClassToken
+	first Position
+}
+
+func elementDuplicates(element *Element) []duplicateToken {
+	if element.Provenance == ProvenanceJS {
+		return nil
+	}
+	var duplicates []duplicateToken
+	first := make(map[string]Position)
+	for _, token := range element.Classes {
+		if pos, ok := first[token.Name]; ok {
+			duplicates = append(duplicates, duplicateToken{token, pos})
+			continue
+		}
+		first[token.Name] = token.Pos
+	}
+	return duplicates
+}
+
+// DuplicateEdits returns the edits removing the repeated classes of file
+// from src, keeping the formatting of the rest of the attribute
+func DuplicateEdits(file *SourceFile, src []byte) []Edit {
+	var edits []Edit
+	for _, element := range file.Elements {
+		if element.ValueEnd > len(src) {
+			continue
+		}
+		repeated := make(map[int]bool)
+		for _, d := range elementDuplicates(element) {
+			repeated[d.token.Pos.Offset] = true
+		}
+		edits = append(edits, removeTokens(src, element, repeated)...)
+	}
+	return edits
+}
+
+// removeTokens returns the edits removing the classes of element starting
+// at the offsets in remove. Classes next to each other go in one edit with
+// the whitespace before them, or with the whitespace after them when they
+// start a line and don't end the attribute, so lines aren't joined
+func removeTokens(src []byte, element *Element, remove map[int]bool) []Edit {
+	var edits []Edit
+	for i := 0; i < len(element.Classes); i++ {
+		if !remove[element.Classes[i].Pos.Offset] {
+			continue
+		}
+		start := element.Classes[i].Pos.Offset
+		for i+1 < len(element.Classes) && remove[element.Classes[i+1].Pos.Offset] {
+			i++
+		}
+		end := element.Classes[i].End
+		edits = append(edits, removeToken(src, element, start, end))
+	}
+	return edits
+}
+
+func removeToken(src []byte, element *Element, start, end int) Edit {
+	before := start
+	for before > element.ValueStart && isHTMLSpace(src[before-1]) {
+		before--
+	}
+	after := end
+	for after < element.ValueEnd && isHTMLSpace(src[after]) {
+		after++
+	}
+	startsLine := strings.ContainsAny(string(src[before:start]), "\r\n")
+	if startsLine && after < element.ValueEnd && !strings.ContainsAny(string(src[end:after]), "\r\n") {
+		return Edit{Start: start, End: after}
+	}
+	return Edit{Start: before, End: end}
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+func TestDuplicateClasses(t *testing.T) {
+	page := `<div class="rounded-md p-2 rounded-md"></div>
+<div class="p-2 flex p-2 p-2 gap-1 flex"></div>
+<ul class="
+    list-disc pl-4
+    list-disc mt-2
+    pl-4
+"></ul>
+<p class="a b
+  a b"></p>
+<i class="x"></i>
+`
+	dir := writeProject(t, map[string]string{"index.html": page})
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := DuplicateClasses(project)
+	expected := []struct {
+		line, column int
+		class        string
+	}{{1, 28, "rounded-md"}, {2, 22, "p-2"}, {2, 26, "p-2"}, {2, 36, "flex"}, {5, 5, "list-disc"}, {6, 5, "pl-4"}, {9, 3, "a"}, {9, 5, "b"}}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %v", len(expected), findings)
+	}
+	for i, e := range expected {
+		if f := findings[i]; f.Pos.Line != e.line || f.Pos.Column != e.column || f.Class != e.class || f.Rule != "duplicate-class" {
+			t.Errorf("Expected %s at %d:%d, got %v", e.class, e.line, e.column, f)
+		}
+	}
+	if message := findings[0].Message; message != `class "rounded-md" is already in this attribute at column 13` {
+		t.Errorf("Unexpected message %q", message)
+	}
+
+	fixed, err := ApplyEdits([]byte(page), DuplicateEdits(project.Files[0], []byte(page)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<div class="rounded-md p-2"></div>
+<div class="p-2 flex gap-1"></div>
+<ul class="
+    list-disc pl-4
+    mt-2
+"></ul>
+<p class="a b"></p>
+<i class="x"></i>
+`
+	if string(fixed) != want {
+		t.Errorf("Expected the fixed page to be\n%s\ngot\n%s", want, fixed)
+	}
+}
+
+func TestApplyEdits(t *testing.T) {
+	src := []byte("0123456789")
+	out, err := ApplyEdits(src, []Edit{{Start: 6, End: 8, Text: "x"}, {Start: 0, End: 1}, {Start: 3, End: 3, Text: "-"}})
+	if err != nil || string(out) != "12-345x89" {
+		t.Errorf("Expected 12-345x89, got %q and %v", out, err)
+	}
+	if _, err := ApplyEdits(src, []Edit{{Start: 2, End: 5}, {Start: 4, End: 6}}); err == nil {
+		t.Errorf("Expected overlapping edits to fail")
+	}
+}
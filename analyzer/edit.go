@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Edit replaces the bytes from Start to End of a file's source with Text,
+// fixes are edits so they can be applied without reformatting anything
+type Edit struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Text  string `json:"text"`
+}
+
+// ApplyEdits returns src with the edits applied, they can come in any order
+// but mustn't overlap
+func ApplyEdits(src []byte, edits []Edit) ([]byte, error) {
+	edits = append([]Edit(nil), edits...)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Start < edits[j].Start })
+	var out []byte
+	last := 0
+	for _, e := range edits {
+		if e.Start < last || e.End < e.Start || e.End > len(src) {
+			return nil, fmt.Errorf("edit of %d:%d overlaps another one or is out of range", e.Start, e.End)
+		}
+		out = append(out, src[last:e.Start]...)
+		out = append(out, e.Text...)
+		last = e.End
+	}
+	return append(out, src[last:]...), nil
+}
+
+// WriteDiff writes what the edits do to src as a unified diff of path
+// without context lines, edits on the same lines go in one hunk
+func WriteDiff(w io.Writer, path string, src []byte, edits []Edit) error {
+	if len(edits) == 0 {
+		return nil
+	}
+	edits = append([]Edit(nil), edits...)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Start < edits[j].Start })
+	lines := newLineIndex(src)
+	lineOf := func(offset int) int { return lines.position(path, offset).Line - 1 }
+	lineEnd := func(line int) int {
+		if line+1 < len(lines) {
+			return lines[line+1]
+		}
+		return len(src)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", filepath.ToSlash(path), filepath.ToSlash(path))
+	delta := 0
+	for i := 0; i < len(edits); {
+		first, last := lineOf(edits[i].Start), lineOf(edits[i].End)
+		j := i + 1
+		for j < len(edits) && lineOf(edits[j].Start) <= last {
+			last = max(last, lineOf(edits[j].End))
+			j++
+		}
+		start := lines[first]
+		hunk := append([]Edit(nil), edits[i:j]...)
+		for k := range hunk {
+			hunk[k].Start -= start
+			hunk[k].End -= start
+		}
+		old := src[start:lineEnd(last)]
+		changed, err := ApplyEdits(old, hunk)
+		if err != nil {
+			return err
+		}
+		oldLines, newLines := diffLines(old), diffLines(changed)
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", first+1, len(oldLines), first+1+delta, len(newLines))
+		for _, line := range oldLines {
+			fmt.Fprintf(&b, "-%s\n", line)
+		}
+		for _, line := range newLines {
+			fmt.Fprintf(&b, "+%s\n", line)
+		}
+		delta += len(newLines) - len(oldLines)
+		i = j
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func diffLines(text []byte) []string {
+	text = bytes.TrimSuffix(text, []byte("\n"))
+	if len(text) == 0 {
+		return nil
+	}
+	return strings.Split(string(text), "\n")
+}
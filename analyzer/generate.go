@@ -0,0 +1,980 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GeneratedRule is the CSS for a single class, AtRules wrap the rule from
+// the outside in
+type GeneratedRule struct {
+	Class        string        `json:"class"`
+	Selector     string        `json:"selector"`
+	AtRules      []string      `json:"atRules,omitempty"`
+	Declarations []Declaration `json:"declarations"`
+	order        [3]int
+}
+
+// UnsupportedClass is a class the generator has no CSS for, and why
+type UnsupportedClass struct {
+	Class  string `json:"class"`
+	Reason string `json:"reason"`
+}
+
+// GeneratedCSS is the output of Generate, rules come in cascade order
+type GeneratedCSS struct {
+	Rules       []GeneratedRule    `json:"rules"`
+	Unsupported []UnsupportedClass `json:"unsupported"`
+}
+
+// Generator turns class names into CSS for a core subset of Tailwind:
+// spacing, sizing, colors, typography, flexbox and grid, borders and a few
+// layout utilities, with the usual variants, breakpoints and arbitrary values
+type Generator struct {
+	Parser *ClassParser
+	// Catalog rejects invalid classes up front, nil means the built-in one
+	Catalog *Catalog
+	// Important mirrors the important option, "true" marks every declaration
+	// !important and a selector like "#app" scopes every rule to it
+	Important string
+}
+
+// generatorPreflight is the bit of Tailwind's preflight the utilities rely
+// on, without it border widths and sizes behave differently
+const generatorPreflight = `*, ::before, ::after {
+  box-sizing: border-box;
+  border: 0 solid;
+}
+`
+
+// Generate builds CSS for classes, every class either gets a rule or an
+// entry in Unsupported
+func (g *Generator) Generate(classes []string) *GeneratedCSS {
+	parser, catalog := g.Parser, g.Catalog
+	if parser == nil {
+		parser = DefaultParser
+	}
+	if catalog == nil {
+		catalog = NewCatalog()
+	}
+	result := &GeneratedCSS{}
+	seen := make(map[string]bool)
+	for _, raw := range classes {
+		if seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		class := parser.Parse(raw)
+		reason := catalog.checkUtility(class)
+		if reason != "" && class.Known {
+			reason = fmt.Sprintf("%s for utility %q", reason, class.Utility)
+		}
+		var rule *GeneratedRule
+		if reason == "" {
+			rule, reason = g.generate(class)
+		}
+		if reason != "" {
+			result.Unsupported = append(result.Unsupported, UnsupportedClass{Class: raw, Reason: reason})
+			continue
+		}
+		result.Rules = append(result.Rules, *rule)
+	}
+	sort.SliceStable(result.Rules, func(i, j int) bool {
+		a, b := result.Rules[i].order, result.Rules[j].order
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return result.Rules[i].Class < result.Rules[j].Class
+	})
+	sort.Slice(result.Unsupported, func(i, j int) bool {
+		return result.Unsupported[i].Class < result.Unsupported[j].Class
+	})
+	return result
+}
+
+func (g *Generator) generate(class Class) (*GeneratedRule, string) {
+	declarations, suffix, order, reason := utilityDeclarations(class)
+	if reason != "" {
+		return nil, reason
+	}
+	important := class.Important || g.Important == "true"
+	for i := range declarations {
+		declarations[i].Important = important
+	}
+
+	rule := &GeneratedRule{Class: class.Raw, Declarations: declarations}
+	selector := "." + escapeClass(class.Raw)
+	variantOrder := 0
+	// variants apply from the inside out, md:hover:x is hover inside md
+	for i := len(class.Variants) - 1; i >= 0; i-- {
+		wrapped, atRule, rank, ok := applyVariant(class.Variants[i], selector)
+		if !ok {
+			return nil, fmt.Sprintf("unsupported variant %q", class.Variants[i])
+		}
+		selector = wrapped
+		if atRule != "" {
+			rule.AtRules = append([]string{atRule}, rule.AtRules...)
+		}
+		variantOrder = max(variantOrder, rank)
+	}
+	if g.Important != "" && g.Important != "true" && g.Important != "false" {
+		selector = g.Important + " " + selector
+	}
+	rule.Selector = selector + suffix
+	rule.order = [3]int{variantOrder, len(class.Variants), order}
+	return rule, ""
+}
+
+// WriteCSS prints the preflight and the rules, rules that share the same
+// at-rules are grouped in one block
+func (gc *GeneratedCSS) WriteCSS(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString(generatorPreflight)
+	for i := 0; i < len(gc.Rules); {
+		j := i + 1
+		for j < len(gc.Rules) && strings.Join(gc.Rules[j].AtRules, "\x00") == strings.Join(gc.Rules[i].AtRules, "\x00") {
+			j++
+		}
+		atRules := gc.Rules[i].AtRules
+		indent := strings.Repeat("  ", len(atRules))
+		for depth, atRule := range atRules {
+			fmt.Fprintf(&b, "\n%s%s {", strings.Repeat("  ", depth), atRule)
+		}
+		for _, rule := range gc.Rules[i:j] {
+			fmt.Fprintf(&b, "\n%s%s {\n", indent, rule.Selector)
+			for _, d := range rule.Declarations {
+				value := d.Value
+				if d.Important {
+					value += " !important"
+				}
+				fmt.Fprintf(&b, "%s  %s: %s;\n", indent, d.Property, value)
+			}
+			fmt.Fprintf(&b, "%s}\n", indent)
+		}
+		for depth := len(atRules) - 1; depth >= 0; depth-- {
+			fmt.Fprintf(&b, "%s}\n", strings.Repeat("  ", depth))
+		}
+		i = j
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteReport lists the unsupported classes with the reason
+func (gc *GeneratedCSS) WriteReport(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Generated %d rules, %d classes unsupported\n", len(gc.Rules), len(gc.Unsupported))
+	for _, u := range gc.Unsupported {
+		fmt.Fprintf(&b, "  %-40s %s\n", u.Class, u.Reason)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeClass escapes a class name for use in a selector
+func escapeClass(class string) string {
+	var b strings.Builder
+	for i := 0; i < len(class); i++ {
+		ch := class[i]
+		switch {
+		case i == 0 && ch >= '0' && ch <= '9':
+			fmt.Fprintf(&b, "\\%x ", ch)
+		case ch == '-' || ch == '_' || ch >= 0x80 ||
+			(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9'):
+			b.WriteByte(ch)
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// pseudoVariants are the variants that only add to the selector, group-*
+// and peer-* reuse them
+var pseudoVariants = map[string]string{
+	"hover":             ":hover",
+	"focus":             ":focus",
+	"focus-visible":     ":focus-visible",
+	"focus-within":      ":focus-within",
+	"active":            ":active",
+	"visited":           ":visited",
+	"target":            ":target",
+	"disabled":          ":disabled",
+	"enabled":           ":enabled",
+	"checked":           ":checked",
+	"required":          ":required",
+	"invalid":           ":invalid",
+	"first":             ":first-child",
+	"last":              ":last-child",
+	"only":              ":only-child",
+	"odd":               ":nth-child(odd)",
+	"even":              ":nth-child(even)",
+	"first-of-type":     ":first-of-type",
+	"last-of-type":      ":last-of-type",
+	"empty":             ":empty",
+	"placeholder":       "::placeholder",
+	"file":              "::file-selector-button",
+	"open":              ":is([open], :popover-open)",
+	"placeholder-shown": ":placeholder-shown",
+}
+
+// mediaVariants map to a media query, breakpoints are ranked by size so
+// larger screens win
+var mediaVariants = map[string]struct {
+	query string
+	rank  int
+}{
+	"sm":            {"(min-width: 640px)", 1},
+	"md":            {"(min-width: 768px)", 2},
+	"lg":            {"(min-width: 1024px)", 3},
+	"xl":            {"(min-width: 1280px)", 4},
+	"2xl":           {"(min-width: 1536px)", 5},
+	"max-sm":        {"not all and (min-width: 640px)", 6},
+	"max-md":        {"not all and (min-width: 768px)", 6},
+	"max-lg":        {"not all and (min-width: 1024px)", 6},
+	"max-xl":        {"not all and (min-width: 1280px)", 6},
+	"max-2xl":       {"not all and (min-width: 1536px)", 6},
+	"dark":          {"(prefers-color-scheme: dark)", 7},
+	"motion-safe":   {"(prefers-reduced-motion: no-preference)", 7},
+	"motion-reduce": {"(prefers-reduced-motion: reduce)", 7},
+	"contrast-more": {"(prefers-contrast: more)", 7},
+	"portrait":      {"(orientation: portrait)", 7},
+	"landscape":     {"(orientation: landscape)", 7},
+	"print":         {"print", 7},
+}
+
+// applyVariant wraps a selector in a variant, it returns the new selector,
+// an at-rule to nest the rule in and how late the rule has to come
+func applyVariant(variant, selector string) (string, string, int, bool) {
+	if pseudo, ok := pseudoVariants[variant]; ok {
+		return selector + pseudo, "", 0, true
+	}
+	if media, ok := mediaVariants[variant]; ok {
+		return selector, "@media " + media.query, media.rank, true
+	}
+	if strings.HasPrefix(variant, "[") && strings.HasSuffix(variant, "]") {
+		inner := strings.ReplaceAll(variant[1:len(variant)-1], "_", " ")
+		if strings.HasPrefix(inner, "@") {
+			return selector, inner, 8, true
+		}
+		if strings.Contains(inner, "&") {
+			return strings.ReplaceAll(inner, "&", selector), "", 0, true
+		}
+		return "", "", 0, false
+	}
+	for _, relation := range []struct{ prefix, format string }{{"group-", ".group%s %s"}, {"peer-", ".peer%s ~ %s"}} {
+		if rest, ok := strings.CutPrefix(variant, relation.prefix); ok {
+			if pseudo, ok := pseudoVariants[rest]; ok && !strings.HasPrefix(pseudo, "::") {
+				return fmt.Sprintf(relation.format, pseudo, selector), "", 0, true
+			}
+			return "", "", 0, false
+		}
+	}
+	for _, attribute := range []string{"aria-", "data-"} {
+		rest, ok := strings.CutPrefix(variant, attribute)
+		if !ok {
+			continue
+		}
+		name := attribute + rest
+		if strings.HasPrefix(rest, "[") && strings.HasSuffix(rest, "]") {
+			name = attribute + strings.ReplaceAll(rest[1:len(rest)-1], "_", " ")
+		} else if attribute == "aria-" {
+			name += `="true"`
+		}
+		return selector + "[" + name + "]", "", 0, true
+	}
+	return "", "", 0, false
+}
+
+// utilityDeclarations returns the declarations of a utility without its
+// variants, a selector suffix for utilities that style children and where
+// the utility goes in the cascade
+func utilityDeclarations(class Class) (declarations []Declaration, suffix string, order int, reason string) {
+	if class.Property != "" {
+		return []Declaration{{Property: class.Property, Value: arbitraryValue(class.Arbitrary)}}, "", len(generatorRoots), ""
+	}
+	name := class.Utility
+	if class.Value != "" {
+		name += "-" + class.Value
+	}
+	if index, ok := generatorIndex[name]; ok && class.Arbitrary == "" && !class.Negative && class.Modifier == "" {
+		return generatorRoots[index].fn(class), "", index, ""
+	}
+	index, ok := generatorIndex[class.Utility]
+	if !ok || generatorRoots[index].static {
+		return nil, "", 0, fmt.Sprintf("utility %q isn't supported by the generator", class.Utility)
+	}
+	declarations = generatorRoots[index].fn(class)
+	if declarations == nil {
+		return nil, "", 0, fmt.Sprintf("value of %q isn't supported by the generator", class.Raw)
+	}
+	if class.Utility == "space-x" || class.Utility == "space-y" {
+		suffix = " > :not(:last-child)"
+	}
+	return declarations, suffix, index, ""
+}
+
+type generatorRoot struct {
+	name   string
+	static bool
+	fn     func(class Class) []Declaration
+}
+
+func decls(pairs ...string) []Declaration {
+	var declarations []Declaration
+	for i := 0; i+1 < len(pairs); i += 2 {
+		declarations = append(declarations, Declaration{Property: pairs[i], Value: pairs[i+1]})
+	}
+	return declarations
+}
+
+func static(name string, pairs ...string) generatorRoot {
+	return generatorRoot{name: name, static: true, fn: func(Class) []Declaration { return decls(pairs...) }}
+}
+
+// withValue builds a root whose properties all get the same resolved value,
+// resolve returns an empty string for values it doesn't know
+func withValue(name string, resolve func(Class) string, properties ...string) generatorRoot {
+	return generatorRoot{name: name, fn: func(class Class) []Declaration {
+		value := resolve(class)
+		if value == "" {
+			return nil
+		}
+		var declarations []Declaration
+		for _, property := range properties {
+			declarations = append(declarations, Declaration{Property: property, Value: value})
+		}
+		return declarations
+	}}
+}
+
+// named resolves values from a fixed table, arbitrary values pass through
+func named(values map[string]string) func(Class) string {
+	return func(class Class) string {
+		if class.Arbitrary != "" {
+			return arbitraryValue(class.Arbitrary)
+		}
+		return values[class.Value]
+	}
+}
+
+func arbitraryValue(value string) string {
+	if strings.HasPrefix(value, "url(") {
+		return value
+	}
+	return strings.ReplaceAll(value, "_", " ")
+}
+
+// spacing resolves spacing scale values, fractions and the named sizes in
+// extra, honoring the negative sign
+func spacing(extra map[string]string) func(Class) string {
+	return func(class Class) string {
+		var value string
+		switch {
+		case class.Arbitrary != "":
+			value = arbitraryValue(class.Arbitrary)
+		case extra[class.Value] != "":
+			value = extra[class.Value]
+		case class.Value == "px":
+			value = "1px"
+		case class.Value == "0":
+			value = "0px"
+		case isSpacing(class.Value):
+			f, _ := strconv.ParseFloat(class.Value, 64)
+			value = formatNumber(f/4) + "rem"
+		case isFraction(class.Value):
+			numerator, denominator, _ := strings.Cut(class.Value, "/")
+			n, _ := strconv.ParseFloat(numerator, 64)
+			d, _ := strconv.ParseFloat(denominator, 64)
+			if d == 0 {
+				return ""
+			}
+			value = formatNumber(n/d*100) + "%"
+		default:
+			return ""
+		}
+		if class.Negative {
+			if value[0] >= '0' && value[0] <= '9' {
+				return "-" + value
+			}
+			return "calc(" + value + " * -1)"
+		}
+		return value
+	}
+}
+
+// formatNumber prints at most six decimals the way Tailwind does, 33.333333%
+func formatNumber(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 6, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// color resolves palette colors, opacity modifiers become an rgb() alpha
+func color(class Class) string {
+	var value string
+	switch {
+	case class.Arbitrary != "":
+		value = arbitraryValue(class.Arbitrary)
+	case namedColors[class.Value] != "":
+		value = namedColors[class.Value]
+	default:
+		i := strings.LastIndex(class.Value, "-")
+		if i < 0 {
+			return ""
+		}
+		value = paletteHex[class.Value[:i]][class.Value[i+1:]]
+	}
+	if value == "" || class.Modifier == "" {
+		return value
+	}
+	var alpha string
+	switch {
+	case strings.HasPrefix(class.Modifier, "[") && strings.HasSuffix(class.Modifier, "]"):
+		alpha = class.Modifier[1 : len(class.Modifier)-1]
+	case isDigits(class.Modifier):
+		n, _ := strconv.Atoi(class.Modifier)
+		alpha = formatNumber(float64(n) / 100)
+	default:
+		return ""
+	}
+	if r, g, b, ok := hexRGB(value); ok {
+		return fmt.Sprintf("rgb(%d %d %d / %s)", r, g, b, alpha)
+	}
+	return fmt.Sprintf("color-mix(in srgb, %s %s, transparent)", value, percentOf(alpha))
+}
+
+func percentOf(alpha string) string {
+	f, err := strconv.ParseFloat(alpha, 64)
+	if err != nil {
+		return alpha
+	}
+	return formatNumber(f*100) + "%"
+}
+
+func hexRGB(value string) (r, g, b int, ok bool) {
+	if len(value) != 7 || value[0] != '#' {
+		return 0, 0, 0, false
+	}
+	n, err := strconv.ParseUint(value[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int(n >> 16), int(n >> 8 & 0xff), int(n & 0xff), true
+}
+
+// looksLikeLength tells the size and width forms of text-[...] and
+// border-[...] apart from colors
+func looksLikeLength(value string) bool {
+	if strings.HasPrefix(value, "length:") {
+		return true
+	}
+	if value == "" || strings.HasPrefix(value, "color:") || strings.HasPrefix(value, "#") {
+		return false
+	}
+	return value[0] >= '0' && value[0] <= '9' || value[0] == '.' || strings.HasPrefix(value, "calc(") || strings.HasPrefix(value, "clamp(")
+}
+
+func stripTypeHint(value string) string {
+	for _, hint := range []string{"length:", "color:"} {
+		value = strings.TrimPrefix(value, hint)
+	}
+	return value
+}
+
+// colorOrWidth builds border-like roots whose value is either a width or a
+// color
+func colorOrWidth(name string, widthProperties, colorProperties []string) generatorRoot {
+	return generatorRoot{name: name, fn: func(class Class) []Declaration {
+		properties := colorProperties
+		var value string
+		switch {
+		case class.Value == "" && class.Arbitrary == "":
+			properties, value = widthProperties, "1px"
+		case class.Arbitrary != "" && looksLikeLength(class.Arbitrary):
+			properties, value = widthProperties, arbitraryValue(stripTypeHint(class.Arbitrary))
+		case isDigits(class.Value):
+			properties, value = widthProperties, class.Value+"px"
+		default:
+			class.Arbitrary = stripTypeHint(class.Arbitrary)
+			value = color(class)
+		}
+		if value == "" {
+			return nil
+		}
+		var declarations []Declaration
+		for _, property := range properties {
+			declarations = append(declarations, Declaration{Property: property, Value: value})
+		}
+		return declarations
+	}}
+}
+
+func textUtility(class Class) []Declaration {
+	if align, ok := textAlign[class.Value]; ok && class.Arbitrary == "" {
+		return decls(align[0], align[1])
+	}
+	if size, ok := fontSizeValues[class.Value]; ok {
+		lineHeight := size[1]
+		if class.Modifier != "" {
+			modifier := Class{Value: class.Modifier}
+			if strings.HasPrefix(class.Modifier, "[") && strings.HasSuffix(class.Modifier, "]") {
+				modifier = Class{Arbitrary: class.Modifier[1 : len(class.Modifier)-1]}
+			}
+			lineHeight = spacing(nil)(modifier)
+			if lineHeight == "" {
+				lineHeight = leadingValues[class.Modifier]
+			}
+			if lineHeight == "" {
+				return nil
+			}
+		}
+		return decls("font-size", size[0], "line-height", lineHeight)
+	}
+	if class.Arbitrary != "" && looksLikeLength(class.Arbitrary) {
+		return decls("font-size", arbitraryValue(stripTypeHint(class.Arbitrary)))
+	}
+	class.Arbitrary = stripTypeHint(class.Arbitrary)
+	if value := color(class); value != "" {
+		return decls("color", value)
+	}
+	return nil
+}
+
+func fontUtility(class Class) []Declaration {
+	if class.Arbitrary != "" {
+		if isDigits(class.Arbitrary) {
+			return decls("font-weight", class.Arbitrary)
+		}
+		return decls("font-family", arbitraryValue(class.Arbitrary))
+	}
+	if weight, ok := fontWeights[class.Value]; ok {
+		return decls("font-weight", weight)
+	}
+	if family, ok := fontFamilies[class.Value]; ok {
+		return decls("font-family", family)
+	}
+	return nil
+}
+
+func flexUtility(class Class) []Declaration {
+	if class.Arbitrary != "" {
+		return decls("flex", arbitraryValue(class.Arbitrary))
+	}
+	switch class.Value {
+	case "":
+		return decls("display", "flex")
+	case "row", "row-reverse", "col", "col-reverse":
+		return decls("flex-direction", strings.Replace(class.Value, "col", "column", 1))
+	case "wrap", "wrap-reverse", "nowrap":
+		return decls("flex-wrap", class.Value)
+	case "1":
+		return decls("flex", "1 1 0%")
+	case "auto":
+		return decls("flex", "1 1 auto")
+	case "initial":
+		return decls("flex", "0 1 auto")
+	case "none":
+		return decls("flex", "none")
+	}
+	if isDigits(class.Value) {
+		return decls("flex", class.Value)
+	}
+	return nil
+}
+
+func gridTemplate(class Class) string {
+	switch {
+	case class.Arbitrary != "":
+		return arbitraryValue(class.Arbitrary)
+	case class.Value == "none" || class.Value == "subgrid":
+		return class.Value
+	case isDigits(class.Value):
+		return "repeat(" + class.Value + ", minmax(0, 1fr))"
+	}
+	return ""
+}
+
+func gridSpan(class Class) string {
+	switch {
+	case class.Arbitrary != "":
+		return arbitraryValue(class.Arbitrary)
+	case class.Value == "full":
+		return "1 / -1"
+	case isDigits(class.Value):
+		return "span " + class.Value + " / span " + class.Value
+	}
+	return ""
+}
+
+func gridLine(class Class) string {
+	switch {
+	case class.Arbitrary != "":
+		return arbitraryValue(class.Arbitrary)
+	case class.Value == "auto":
+		return "auto"
+	case isDigits(class.Value) && class.Negative:
+		return "calc(" + class.Value + " * -1)"
+	case isDigits(class.Value):
+		return class.Value
+	}
+	return ""
+}
+
+func integer(class Class) string {
+	switch {
+	case class.Arbitrary != "":
+		return arbitraryValue(class.Arbitrary)
+	case class.Value == "" || class.Value == "auto":
+		return map[string]string{"": "1", "auto": "auto"}[class.Value]
+	case isDigits(class.Value) && class.Negative:
+		return "calc(" + class.Value + " * -1)"
+	case isDigits(class.Value):
+		return class.Value
+	}
+	return ""
+}
+
+func opacity(class Class) string {
+	switch {
+	case class.Arbitrary != "":
+		return class.Arbitrary
+	case isDigits(class.Value):
+		n, _ := strconv.Atoi(class.Value)
+		return formatNumber(float64(n) / 100)
+	}
+	return ""
+}
+
+func radius(class Class) string {
+	if class.Arbitrary != "" {
+		return arbitraryValue(class.Arbitrary)
+	}
+	return radiusValues[class.Value]
+}
+
+func roundedCorners(name string, corners ...string) generatorRoot {
+	var properties []string
+	for _, corner := range corners {
+		properties = append(properties, "border-"+corner+"-radius")
+	}
+	return withValue(name, radius, properties...)
+}
+
+var (
+	namedColors = map[string]string{
+		"inherit": "inherit", "current": "currentColor", "transparent": "transparent",
+		"black": "#000", "white": "#fff",
+	}
+	textAlign = map[string][2]string{
+		"left": {"text-align", "left"}, "center": {"text-align", "center"}, "right": {"text-align", "right"},
+		"justify": {"text-align", "justify"}, "start": {"text-align", "start"}, "end": {"text-align", "end"},
+		"wrap": {"text-wrap", "wrap"}, "nowrap": {"text-wrap", "nowrap"}, "balance": {"text-wrap", "balance"},
+		"pretty": {"text-wrap", "pretty"}, "ellipsis": {"text-overflow", "ellipsis"}, "clip": {"text-overflow", "clip"},
+	}
+	fontSizeValues = map[string][2]string{
+		"xs": {"0.75rem", "1rem"}, "sm": {"0.875rem", "1.25rem"}, "base": {"1rem", "1.5rem"},
+		"lg": {"1.125rem", "1.75rem"}, "xl": {"1.25rem", "1.75rem"}, "2xl": {"1.5rem", "2rem"},
+		"3xl": {"1.875rem", "2.25rem"}, "4xl": {"2.25rem", "2.5rem"}, "5xl": {"3rem", "1"},
+		"6xl": {"3.75rem", "1"}, "7xl": {"4.5rem", "1"}, "8xl": {"6rem", "1"}, "9xl": {"8rem", "1"},
+	}
+	fontWeights = map[string]string{
+		"thin": "100", "extralight": "200", "light": "300", "normal": "400", "medium": "500",
+		"semibold": "600", "bold": "700", "extrabold": "800", "black": "900",
+	}
+	fontFamilies = map[string]string{
+		"sans":  `ui-sans-serif, system-ui, sans-serif, "Apple Color Emoji", "Segoe UI Emoji", "Segoe UI Symbol", "Noto Color Emoji"`,
+		"serif": `ui-serif, Georgia, Cambria, "Times New Roman", Times, serif`,
+		"mono":  `ui-monospace, SFMono-Regular, Menlo, Monaco, Consolas, "Liberation Mono", "Courier New", monospace`,
+	}
+	leadingValues = map[string]string{
+		"none": "1", "tight": "1.25", "snug": "1.375", "normal": "1.5", "relaxed": "1.625", "loose": "2",
+	}
+	trackingValues = map[string]string{
+		"tighter": "-0.05em", "tight": "-0.025em", "normal": "0em", "wide": "0.025em", "wider": "0.05em", "widest": "0.1em",
+	}
+	radiusValues = map[string]string{
+		"": "0.25rem", "none": "0px", "xs": "0.125rem", "sm": "0.125rem", "md": "0.375rem", "lg": "0.5rem",
+		"xl": "0.75rem", "2xl": "1rem", "3xl": "1.5rem", "4xl": "2rem", "full": "9999px",
+	}
+	containerWidths = map[string]string{
+		"3xs": "16rem", "2xs": "18rem", "xs": "20rem", "sm": "24rem", "md": "28rem", "lg": "32rem",
+		"xl": "36rem", "2xl": "42rem", "3xl": "48rem", "4xl": "56rem", "5xl": "64rem", "6xl": "72rem", "7xl": "80rem",
+	}
+	alignValues = map[string]string{
+		"start": "flex-start", "end": "flex-end", "center": "center", "baseline": "baseline", "stretch": "stretch",
+		"between": "space-between", "around": "space-around", "evenly": "space-evenly", "normal": "normal", "auto": "auto",
+	}
+)
+
+var insetSizes = map[string]string{"auto": "auto", "full": "100%"}
+
+func sizes(axis string) map[string]string {
+	values := map[string]string{"auto": "auto", "full": "100%", "min": "min-content", "max": "max-content", "fit": "fit-content"}
+	if axis == "w" {
+		values["screen"], values["svw"], values["lvw"], values["dvw"] = "100vw", "100svw", "100lvw", "100dvw"
+		for k, v := range containerWidths {
+			values[k] = v
+		}
+	} else {
+		values["screen"], values["svh"], values["lvh"], values["dvh"], values["lh"] = "100vh", "100svh", "100lvh", "100dvh", "1lh"
+	}
+	return values
+}
+
+func maxWidths() map[string]string {
+	values := sizes("w")
+	values["none"], values["prose"] = "none", "65ch"
+	for screen, width := range map[string]string{"sm": "640px", "md": "768px", "lg": "1024px", "xl": "1280px", "2xl": "1536px"} {
+		values["screen-"+screen] = width
+	}
+	return values
+}
+
+// generatorRoots lists what the generator supports in cascade order,
+// shorthands come before the longhands that override them
+var generatorRoots = []generatorRoot{
+	static("sr-only", "position", "absolute", "width", "1px", "height", "1px", "padding", "0", "margin", "-1px", "overflow", "hidden", "clip", "rect(0, 0, 0, 0)", "white-space", "nowrap", "border-width", "0"),
+	static("not-sr-only", "position", "static", "width", "auto", "height", "auto", "padding", "0", "margin", "0", "overflow", "visible", "clip", "auto", "white-space", "normal"),
+	static("visible", "visibility", "visible"),
+	static("invisible", "visibility", "hidden"),
+	static("static", "position", "static"),
+	static("fixed", "position", "fixed"),
+	static("absolute", "position", "absolute"),
+	static("relative", "position", "relative"),
+	static("sticky", "position", "sticky"),
+	withValue("inset", spacing(insetSizes), "inset"),
+	withValue("inset-x", spacing(insetSizes), "left", "right"),
+	withValue("inset-y", spacing(insetSizes), "top", "bottom"),
+	withValue("start", spacing(insetSizes), "inset-inline-start"),
+	withValue("end", spacing(insetSizes), "inset-inline-end"),
+	withValue("top", spacing(insetSizes), "top"),
+	withValue("right", spacing(insetSizes), "right"),
+	withValue("bottom", spacing(insetSizes), "bottom"),
+	withValue("left", spacing(insetSizes), "left"),
+	withValue("z", integer, "z-index"),
+	withValue("order", func(class Class) string {
+		if value, ok := map[string]string{"first": "-9999", "last": "9999", "none": "0"}[class.Value]; ok {
+			return value
+		}
+		return integer(class)
+	}, "order"),
+	withValue("col", named(map[string]string{"auto": "auto"}), "grid-column"),
+	withValue("col-span", gridSpan, "grid-column"),
+	withValue("col-start", gridLine, "grid-column-start"),
+	withValue("col-end", gridLine, "grid-column-end"),
+	withValue("row", named(map[string]string{"auto": "auto"}), "grid-row"),
+	withValue("row-span", gridSpan, "grid-row"),
+	withValue("row-start", gridLine, "grid-row-start"),
+	withValue("row-end", gridLine, "grid-row-end"),
+	withValue("m", spacing(map[string]string{"auto": "auto"}), "margin"),
+	withValue("mx", spacing(map[string]string{"auto": "auto"}), "margin-left", "margin-right"),
+	withValue("my", spacing(map[string]string{"auto": "auto"}), "margin-top", "margin-bottom"),
+	withValue("ms", spacing(map[string]string{"auto": "auto"}), "margin-inline-start"),
+	withValue("me", spacing(map[string]string{"auto": "auto"}), "margin-inline-end"),
+	withValue("mt", spacing(map[string]string{"auto": "auto"}), "margin-top"),
+	withValue("mr", spacing(map[string]string{"auto": "auto"}), "margin-right"),
+	withValue("mb", spacing(map[string]string{"auto": "auto"}), "margin-bottom"),
+	withValue("ml", spacing(map[string]string{"auto": "auto"}), "margin-left"),
+	static("block", "display", "block"),
+	static("inline-block", "display", "inline-block"),
+	static("inline", "display", "inline"),
+	{name: "flex", fn: flexUtility},
+	static("inline-flex", "display", "inline-flex"),
+	static("table", "display", "table"),
+	static("flow-root", "display", "flow-root"),
+	static("grid", "display", "grid"),
+	static("inline-grid", "display", "inline-grid"),
+	static("contents", "display", "contents"),
+	static("list-item", "display", "list-item"),
+	static("hidden", "display", "none"),
+	withValue("size", spacing(sizes("h")), "width", "height"),
+	withValue("w", spacing(sizes("w")), "width"),
+	withValue("min-w", spacing(sizes("w")), "min-width"),
+	withValue("max-w", spacing(maxWidths()), "max-width"),
+	withValue("h", spacing(sizes("h")), "height"),
+	withValue("min-h", spacing(sizes("h")), "min-height"),
+	withValue("max-h", spacing(map[string]string{"none": "none", "full": "100%", "screen": "100vh", "min": "min-content", "max": "max-content", "fit": "fit-content"}), "max-height"),
+	withValue("basis", spacing(sizes("w")), "flex-basis"),
+	withValue("shrink", integer, "flex-shrink"),
+	withValue("grow", integer, "flex-grow"),
+	withValue("grid-cols", gridTemplate, "grid-template-columns"),
+	withValue("grid-rows", gridTemplate, "grid-template-rows"),
+	withValue("grid-flow", named(map[string]string{"row": "row", "col": "column", "dense": "dense", "row-dense": "row dense", "col-dense": "column dense"}), "grid-auto-flow"),
+	withValue("place-content", named(alignValues), "place-content"),
+	withValue("place-items", named(alignValues), "place-items"),
+	withValue("content", named(alignValues), "align-content"),
+	withValue("items", named(alignValues), "align-items"),
+	withValue("justify", named(alignValues), "justify-content"),
+	withValue("justify-items", named(alignValues), "justify-items"),
+	withValue("gap", spacing(nil), "gap"),
+	withValue("gap-x", spacing(nil), "column-gap"),
+	withValue("gap-y", spacing(nil), "row-gap"),
+	withValue("space-x", spacing(nil), "margin-inline-end"),
+	withValue("space-y", spacing(nil), "margin-block-end"),
+	withValue("place-self", named(alignValues), "place-self"),
+	withValue("self", named(alignValues), "align-self"),
+	withValue("justify-self", named(alignValues), "justify-self"),
+	withValue("overflow", named(map[string]string{"auto": "auto", "hidden": "hidden", "clip": "clip", "visible": "visible", "scroll": "scroll"}), "overflow"),
+	withValue("overflow-x", named(map[string]string{"auto": "auto", "hidden": "hidden", "clip": "clip", "visible": "visible", "scroll": "scroll"}), "overflow-x"),
+	withValue("overflow-y", named(map[string]string{"auto": "auto", "hidden": "hidden", "clip": "clip", "visible": "visible", "scroll": "scroll"}), "overflow-y"),
+	static("truncate", "overflow", "hidden", "text-overflow", "ellipsis", "white-space", "nowrap"),
+	withValue("whitespace", named(map[string]string{"normal": "normal", "nowrap": "nowrap", "pre": "pre", "pre-line": "pre-line", "pre-wrap": "pre-wrap", "break-spaces": "break-spaces"}), "white-space"),
+	static("break-normal", "overflow-wrap", "normal", "word-break", "normal"),
+	static("break-words", "overflow-wrap", "break-word"),
+	static("break-all", "word-break", "break-all"),
+	roundedCorners("rounded", "top-left", "top-right", "bottom-right", "bottom-left"),
+	roundedCorners("rounded-s", "start-start", "end-start"),
+	roundedCorners("rounded-e", "start-end", "end-end"),
+	roundedCorners("rounded-t", "top-left", "top-right"),
+	roundedCorners("rounded-r", "top-right", "bottom-right"),
+	roundedCorners("rounded-b", "bottom-right", "bottom-left"),
+	roundedCorners("rounded-l", "top-left", "bottom-left"),
+	roundedCorners("rounded-ss", "start-start"),
+	roundedCorners("rounded-se", "start-end"),
+	roundedCorners("rounded-ee", "end-end"),
+	roundedCorners("rounded-es", "end-start"),
+	roundedCorners("rounded-tl", "top-left"),
+	roundedCorners("rounded-tr", "top-right"),
+	roundedCorners("rounded-br", "bottom-right"),
+	roundedCorners("rounded-bl", "bottom-left"),
+	colorOrWidth("border", []string{"border-width"}, []string{"border-color"}),
+	colorOrWidth("border-x", []string{"border-left-width", "border-right-width"}, []string{"border-left-color", "border-right-color"}),
+	colorOrWidth("border-y", []string{"border-top-width", "border-bottom-width"}, []string{"border-top-color", "border-bottom-color"}),
+	colorOrWidth("border-s", []string{"border-inline-start-width"}, []string{"border-inline-start-color"}),
+	colorOrWidth("border-e", []string{"border-inline-end-width"}, []string{"border-inline-end-color"}),
+	colorOrWidth("border-t", []string{"border-top-width"}, []string{"border-top-color"}),
+	colorOrWidth("border-r", []string{"border-right-width"}, []string{"border-right-color"}),
+	colorOrWidth("border-b", []string{"border-bottom-width"}, []string{"border-bottom-color"}),
+	colorOrWidth("border-l", []string{"border-left-width"}, []string{"border-left-color"}),
+	static("border-solid", "border-style", "solid"),
+	static("border-dashed", "border-style", "dashed"),
+	static("border-dotted", "border-style", "dotted"),
+	static("border-double", "border-style", "double"),
+	static("border-hidden", "border-style", "hidden"),
+	static("border-none", "border-style", "none"),
+	{name: "bg", fn: func(class Class) []Declaration {
+		if strings.HasPrefix(class.Arbitrary, "url(") {
+			return decls("background-image", class.Arbitrary)
+		}
+		if value := color(class); value != "" {
+			return decls("background-color", value)
+		}
+		return nil
+	}},
+	withValue("fill", color, "fill"),
+	withValue("stroke", color, "stroke"),
+	withValue("p", spacing(nil), "padding"),
+	withValue("px", spacing(nil), "padding-left", "padding-right"),
+	withValue("py", spacing(nil), "padding-top", "padding-bottom"),
+	withValue("ps", spacing(nil), "padding-inline-start"),
+	withValue("pe", spacing(nil), "padding-inline-end"),
+	withValue("pt", spacing(nil), "padding-top"),
+	withValue("pr", spacing(nil), "padding-right"),
+	withValue("pb", spacing(nil), "padding-bottom"),
+	withValue("pl", spacing(nil), "padding-left"),
+	{name: "font", fn: fontUtility},
+	{name: "text", fn: textUtility},
+	withValue("leading", func(class Class) string {
+		if value := leadingValues[class.Value]; value != "" {
+			return value
+		}
+		return spacing(nil)(class)
+	}, "line-height"),
+	withValue("tracking", named(trackingValues), "letter-spacing"),
+	static("italic", "font-style", "italic"),
+	static("not-italic", "font-style", "normal"),
+	static("underline", "text-decoration-line", "underline"),
+	static("overline", "text-decoration-line", "overline"),
+	static("line-through", "text-decoration-line", "line-through"),
+	static("no-underline", "text-decoration-line", "none"),
+	withValue("decoration", color, "text-decoration-color"),
+	static("uppercase", "text-transform", "uppercase"),
+	static("lowercase", "text-transform", "lowercase"),
+	static("capitalize", "text-transform", "capitalize"),
+	static("normal-case", "text-transform", "none"),
+	static("antialiased", "-webkit-font-smoothing", "antialiased", "-moz-osx-font-smoothing", "grayscale"),
+	withValue("list", named(map[string]string{"none": "none", "disc": "disc", "decimal": "decimal"}), "list-style-type"),
+	withValue("accent", color, "accent-color"),
+	withValue("caret", color, "caret-color"),
+	withValue("opacity", opacity, "opacity"),
+	withValue("aspect", func(class Class) string {
+		if class.Arbitrary != "" {
+			return arbitraryValue(class.Arbitrary)
+		}
+		if isFraction(class.Value) {
+			return strings.Replace(class.Value, "/", " / ", 1)
+		}
+		return map[string]string{"auto": "auto", "square": "1 / 1", "video": "16 / 9"}[class.Value]
+	}, "aspect-ratio"),
+	{name: "line-clamp", fn: func(class Class) []Declaration {
+		if class.Value == "none" {
+			return decls("overflow", "visible", "display", "block", "-webkit-box-orient", "horizontal", "-webkit-line-clamp", "unset")
+		}
+		if value := integer(class); value != "" {
+			return decls("overflow", "hidden", "display", "-webkit-box", "-webkit-box-orient", "vertical", "-webkit-line-clamp", value)
+		}
+		return nil
+	}},
+	withValue("cursor", func(class Class) string {
+		if class.Arbitrary != "" {
+			return arbitraryValue(class.Arbitrary)
+		}
+		return class.Value
+	}, "cursor"),
+	withValue("pointer-events", named(map[string]string{"none": "none", "auto": "auto"}), "pointer-events"),
+	withValue("select", named(map[string]string{"none": "none", "text": "text", "all": "all", "auto": "auto"}), "user-select"),
+	withValue("appearance", named(map[string]string{"none": "none", "auto": "auto"}), "appearance"),
+}
+
+// generatorIndex maps names in generatorRoots to their position
+var generatorIndex = func() map[string]int {
+	index := make(map[string]int, len(generatorRoots))
+	for i, root := range generatorRoots {
+		index[root.name] = i
+	}
+	return index
+}()
+
+// paletteHex is the v3 color palette, v4 moved to oklch but hex works
+// everywhere and takes an alpha easily
+var paletteHex = map[string]map[string]string{}
+
+func init() {
+	hexes := map[string]string{
+		"slate":   "f8fafc f1f5f9 e2e8f0 cbd5e1 94a3b8 64748b 475569 334155 1e293b 0f172a 020617",
+		"gray":    "f9fafb f3f4f6 e5e7eb d1d5db 9ca3af 6b7280 4b5563 374151 1f2937 111827 030712",
+		"zinc":    "fafafa f4f4f5 e4e4e7 d4d4d8 a1a1aa 71717a 52525b 3f3f46 27272a 18181b 09090b",
+		"neutral": "fafafa f5f5f5 e5e5e5 d4d4d4 a3a3a3 737373 525252 404040 262626 171717 0a0a0a",
+		"stone":   "fafaf9 f5f5f4 e7e5e4 d6d3d1 a8a29e 78716c 57534e 44403c 292524 1c1917 0c0a09",
+		"red":     "fef2f2 fee2e2 fecaca fca5a5 f87171 ef4444 dc2626 b91c1c 991b1b 7f1d1d 450a0a",
+		"orange":  "fff7ed ffedd5 fed7aa fdba74 fb923c f97316 ea580c c2410c 9a3412 7c2d12 431407",
+		"amber":   "fffbeb fef3c7 fde68a fcd34d fbbf24 f59e0b d97706 b45309 92400e 78350f 451a03",
+		"yellow":  "fefce8 fef9c3 fef08a fde047 facc15 eab308 ca8a04 a16207 854d0e 713f12 422006",
+		"lime":    "f7fee7 ecfccb d9f99d bef264 a3e635 84cc16 65a30d 4d7c0f 3f6212 365314 1a2e05",
+		"green":   "f0fdf4 dcfce7 bbf7d0 86efac 4ade80 22c55e 16a34a 15803d 166534 14532d 052e16",
+		"emerald": "ecfdf5 d1fae5 a7f3d0 6ee7b7 34d399 10b981 059669 047857 065f46 064e3b 022c22",
+		"teal":    "f0fdfa ccfbf1 99f6e4 5eead4 2dd4bf 14b8a6 0d9488 0f766e 115e59 134e4a 042f2e",
+		"cyan":    "ecfeff cffafe a5f3fc 67e8f9 22d3ee 06b6d4 0891b2 0e7490 155e75 164e63 083344",
+		"sky":     "f0f9ff e0f2fe bae6fd 7dd3fc 38bdf8 0ea5e9 0284c7 0369a1 075985 0c4a6e 082f49",
+		"blue":    "eff6ff dbeafe bfdbfe 93c5fd 60a5fa 3b82f6 2563eb 1d4ed8 1e40af 1e3a8a 172554",
+		"indigo":  "eef2ff e0e7ff c7d2fe a5b4fc 818cf8 6366f1 4f46e5 4338ca 3730a3 312e81 1e1b4b",
+		"violet":  "f5f3ff ede9fe ddd6fe c4b5fd a78bfa 8b5cf6 7c3aed 6d28d9 5b21b6 4c1d95 2e1065",
+		"purple":  "faf5ff f3e8ff e9d5ff d8b4fe c084fc a855f7 9333ea 7e22ce 6b21a8 581c87 3b0764",
+		"fuchsia": "fdf4ff fae8ff f5d0fe f0abfc e879f9 d946ef c026d3 a21caf 86198f 701a75 4a044e",
+		"pink":    "fdf2f8 fce7f3 fbcfe8 f9a8d4 f472b6 ec4899 db2777 be185d 9d174d 831843 500724",
+		"rose":    "fff1f2 ffe4e6 fecdd3 fda4af fb7185 f43f5e e11d48 be123c 9f1239 881337 4c0519",
+	}
+	for name, list := range hexes {
+		paletteHex[name] = make(map[string]string, len(shades))
+		for i, hex := range strings.Fields(list) {
+			paletteHex[name][shades[i]] = "#" + hex
+		}
+	}
+}
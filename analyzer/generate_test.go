@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	generator := &Generator{}
+	css := generator.Generate([]string{
+		"p-4", "px-2", "-mt-1.5", "w-1/3", "md:hover:bg-red-500/50", "text-sm/6", "text-[#123456]",
+		"text-[14px]", "border", "border-t-2", "!font-bold", "grid-cols-3", "[&>*]:rounded-lg",
+		"group-hover:underline", "2xl:flex-col", "[mask-type:luminance]", "w-[calc(100%_-_2rem)]",
+		"ring-2", "before:block", "bg-brand", "not-a-utility", "p-4",
+	})
+
+	var b strings.Builder
+	if err := css.WriteCSS(&b); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		".p-4 {\n  padding: 1rem;\n}",
+		".px-2 {\n  padding-left: 0.5rem;\n  padding-right: 0.5rem;\n}",
+		".-mt-1\\.5 {\n  margin-top: -0.375rem;\n}",
+		".w-1\\/3 {\n  width: 33.333333%;\n}",
+		"@media (min-width: 768px) {\n  .md\\:hover\\:bg-red-500\\/50:hover {\n    background-color: rgb(239 68 68 / 0.5);\n  }\n}",
+		".text-sm\\/6 {\n  font-size: 0.875rem;\n  line-height: 1.5rem;\n}",
+		".text-\\[\\#123456\\] {\n  color: #123456;\n}",
+		".text-\\[14px\\] {\n  font-size: 14px;\n}",
+		".border {\n  border-width: 1px;\n}",
+		".border-t-2 {\n  border-top-width: 2px;\n}",
+		".\\!font-bold {\n  font-weight: 700 !important;\n}",
+		".grid-cols-3 {\n  grid-template-columns: repeat(3, minmax(0, 1fr));\n}",
+		".\\[\\&\\>\\*\\]\\:rounded-lg>* {\n  border-top-left-radius: 0.5rem;",
+		".group:hover .group-hover\\:underline {\n  text-decoration-line: underline;\n}",
+		".\\[mask-type\\:luminance\\] {\n  mask-type: luminance;\n}",
+		".w-\\[calc\\(100\\%_-_2rem\\)\\] {\n  width: calc(100% - 2rem);\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected the CSS to contain\n%s\ngot\n%s", want, got)
+		}
+	}
+
+	// p-4 has to come before px-2 so the longhand wins, breakpoints come last
+	if strings.Index(got, ".p-4 {") > strings.Index(got, ".px-2 {") {
+		t.Errorf("Expected p-4 to come before px-2")
+	}
+	if strings.Index(got, "(min-width: 768px)") > strings.Index(got, "(min-width: 1536px)") {
+		t.Errorf("Expected md to come before 2xl")
+	}
+
+	unsupported := make(map[string]string)
+	for _, u := range css.Unsupported {
+		unsupported[u.Class] = u.Reason
+	}
+	for class, reason := range map[string]string{
+		"ring-2":        `utility "ring" isn't supported by the generator`,
+		"before:block":  `unsupported variant "before"`,
+		"bg-brand":      `unknown value for utility "bg"`,
+		"not-a-utility": "unknown utility",
+	} {
+		if unsupported[class] != reason {
+			t.Errorf("Expected %q to be unsupported with %q, got %q", class, reason, unsupported[class])
+		}
+	}
+	if len(css.Rules)+len(css.Unsupported) != 21 {
+		t.Errorf("Expected every distinct class once, got %d rules and %d unsupported", len(css.Rules), len(css.Unsupported))
+	}
+}
+
+func TestGenerateImportantSelector(t *testing.T) {
+	generator := &Generator{Important: "#app"}
+	css := generator.Generate([]string{"flex"})
+	if len(css.Rules) != 1 || css.Rules[0].Selector != "#app .flex" {
+		t.Errorf("Expected the rule to be scoped to #app, got %+v", css.Rules)
+	}
+}
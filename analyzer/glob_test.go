@@ -0,0 +1,24 @@
+package analyzer
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"src/**/*.{html,js}", "src/index.html", true},
+		{"src/**/*.{html,js}", "src/a/b/c.js", true},
+		{"src/**/*.{html,js}", "src/a/b/c.css", false},
+		{"src/**/*.{html,js}", "lib/index.html", false},
+		{"**/*.html", "index.html", true},
+		{"pages/*.html", "pages/a/b.html", false},
+		{"{app,components}/**/*.{ts,tsx}", "components/ui/button.tsx", true},
+		{"index.html", "index.html", true},
+	}
+	for _, c := range cases {
+		if got := MatchGlob(c.pattern, c.name); got != c.want {
+			t.Errorf("Expected MatchGlob(%q, %q) to be %v, got %v", c.pattern, c.name, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// StyleAttr is the style attribute of an element, Classes are the classes
+// of the same element
+type StyleAttr struct {
+	Tag          string         `json:"tag"`
+	Pos          Position       `json:"pos"`
+	ValueStart   int            `json:"valueStart"`
+	ValueEnd     int            `json:"valueEnd"`
+	Classes      []string       `json:"classes,omitempty"`
+	Declarations []*Declaration `json:"declarations"`
+}
+
+// keepBlocks returns a copy of src with everything but the blocks blanked
+// out, newlines excepted, so that the blocks can be parsed on their own
+// with positions and offsets that are the ones in the file
+func keepBlocks(src []byte, blocks [][2]int) []byte {
+	kept := make([]byte, len(src))
+	for i, b := range src {
+		if b == '\n' {
+			kept[i] = b
+		} else {
+			kept[i] = ' '
+		}
+	}
+	for _, block := range blocks {
+		copy(kept[block[0]:block[1]], src[block[0]:block[1]])
+	}
+	return kept
+}
+
+// parseStyleAttr reads the declarations of the style attribute value
+// src[start:end], each one positioned where it starts. Character
+// references can hide semicolons, a value with any is decoded first and
+// its declarations all get the position of the value
+func parseStyleAttr(src []byte, start, end int, lines lineIndex, path string) []*Declaration {
+	value := string(src[start:end])
+	decoded := strings.Contains(value, "&")
+	if decoded {
+		value = html.UnescapeString(value)
+	}
+	var declarations []*Declaration
+	offset := start
+	for _, part := range splitTopLevel(value, ";") {
+		pos := lines.position(path, start)
+		if !decoded {
+			pos = lines.position(path, offset+len(part)-len(strings.TrimLeft(part, " \t\r\n\f")))
+		}
+		if d := parseDeclaration(strings.TrimSpace(part), pos); d != nil {
+			declarations = append(declarations, d)
+		}
+		offset += len(part) + 1
+	}
+	return declarations
+}
+
+// PropertyCount is how many style attributes set a property
+type PropertyCount struct {
+	Property string `json:"property"`
+	Count    int    `json:"count"`
+}
+
+// InlineStyles is the inventory of the style attributes of a project
+type InlineStyles struct {
+	Attributes   []*StyleAttr    `json:"attributes"`
+	Declarations int             `json:"declarations"`
+	Files        int             `json:"files"`
+	Properties   []PropertyCount `json:"properties"`
+}
+
+// ComputeInlineStyles lists every style attribute of the project and counts
+// the properties they set, most common first
+func ComputeInlineStyles(p *Project) *InlineStyles {
+	inventory := &InlineStyles{}
+	counts := make(map[string]int)
+	for _, file := range p.Files {
+		if len(file.StyleAttrs) > 0 {
+			inventory.Files++
+		}
+		for _, attr := range file.StyleAttrs {
+			inventory.Attributes = append(inventory.Attributes, attr)
+			inventory.Declarations += len(attr.Declarations)
+			for _, d := range attr.Declarations {
+				counts[strings.ToLower(d.Property)]++
+			}
+		}
+	}
+	for property, count := range counts {
+		inventory.Properties = append(inventory.Properties, PropertyCount{property, count})
+	}
+	sort.Slice(inventory.Properties, func(i, j int) bool {
+		a, b := inventory.Properties[i], inventory.Properties[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Property < b.Property
+	})
+	return inventory
+}
+
+// WriteReport prints every style attribute with the element it's on and
+// the top most common properties
+func (s *InlineStyles) WriteReport(w io.Writer, top int) error {
+	var b strings.Builder
+	for _, attr := range s.Attributes {
+		fmt.Fprintf(&b, "%s: <%s", attr.Pos, attr.Tag)
+		if len(attr.Classes) > 0 {
+			fmt.Fprintf(&b, " class=%q", strings.Join(attr.Classes, " "))
+		}
+		b.WriteString(">")
+		for _, d := range attr.Declarations {
+			fmt.Fprintf(&b, " %s: %s;", d.Property, d.Value)
+		}
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(&b, "%d style attributes with %d declarations in %d files\n", len(s.Attributes), s.Declarations, s.Files)
+	if len(s.Properties) > 0 {
+		b.WriteString("\nMost common properties\n")
+		for i, p := range s.Properties {
+			if i == top {
+				break
+			}
+			fmt.Fprintf(&b, "  %-30s %d\n", p.Property, p.Count)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInlineStyles(t *testing.T) {
+	page := `<html><head>
+<style>
+  .hero { padding: 2rem }
+  .old { color: gray }
+</style>
+</head><body>
+<div class="hero" style="color: red; margin:0">Hi</div>
+<p style="color:&quot;blue&quot;">Bye</p>
+<style media="print">.print-only { display: block }</style>
+</body></html>
+`
+	path := filepath.Join(writeProject(t, map[string]string{"email.html": page}), "email.html")
+
+	// a single self-contained file is a project of its own
+	project, err := Scan(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Stylesheets) != 1 || project.Stylesheets[0].Path != "email.html" {
+		t.Fatalf("Expected the <style> blocks as one stylesheet, got %+v", project.Stylesheets)
+	}
+	unused := project.UnusedClasses()
+	if len(unused) != 2 || unused[0].Class != "old" || unused[0].Pos.Line != 4 || unused[0].Pos.Column != 3 ||
+		unused[1].Class != "print-only" || unused[1].Pos.Line != 9 {
+		t.Errorf("Expected old and print-only to be unused, got %+v", unused)
+	}
+	if !project.DefinedClasses()["hero"] {
+		t.Errorf("Expected hero to be defined")
+	}
+
+	attrs := project.Files[0].StyleAttrs
+	if len(attrs) != 2 {
+		t.Fatalf("Expected 2 style attributes, got %+v", attrs)
+	}
+	if attrs[0].Tag != "div" || len(attrs[0].Classes) != 1 || attrs[0].Classes[0] != "hero" || len(attrs[0].Declarations) != 2 {
+		t.Errorf("Unexpected first style attribute %+v", attrs[0])
+	}
+	if margin := attrs[0].Declarations[1]; margin.Property != "margin" || margin.Value != "0" || margin.Pos.Line != 7 || margin.Pos.Column != 38 {
+		t.Errorf("Unexpected margin declaration %+v", margin)
+	}
+	if color := attrs[1].Declarations[0]; color.Value != `"blue"` {
+		t.Errorf("Expected character references to be decoded, got %+v", color)
+	}
+
+	var b strings.Builder
+	if err := ComputeInlineStyles(project).WriteReport(&b, 10); err != nil {
+		t.Fatal(err)
+	}
+	expected := `email.html:7:1: <div class="hero"> color: red; margin: 0;
+email.html:8:1: <p> color: "blue";
+2 style attributes with 3 declarations in 1 files
+
+Most common properties
+  color                          2
+  margin                         1
+`
+	if b.String() != expected {
+		t.Errorf("Expected report\n%s\ngot\n%s", expected, b.String())
+	}
+}
@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// jsxClassPattern matches the className= and class= attributes of JSX
+// elements, and of markup in script strings, up to their value. The name
+// has to follow whitespace and go straight into the '=' so neither
+// el.className = nor const className = is one
+var jsxClassPattern = regexp.MustCompile(`(?:^|\s)(className|class)=`)
+
+// jsxAttributes finds the class attributes of JSX elements with a static
+// value, className="a b", className={'a b'} or className={` + "`a b`" + `},
+// values built at runtime aren't known statically and are left out
+func jsxAttributes(text, path string, lines lineIndex) []*Element {
+	var elements []*Element
+	for _, m := range jsxClassPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end, ok := jsxAttributeValue(text, m[1])
+		if !ok {
+			continue
+		}
+		element := &Element{Tag: jsxTag(text, m[2]), Pos: lines.position(path, m[2]), ValueStart: start, ValueEnd: end}
+		if tagStart := strings.LastIndexByte(text[:m[2]], '<'); tagStart >= 0 && element.Tag != "" {
+			element.Pos = lines.position(path, tagStart)
+		}
+		for _, field := range fieldSpans(text[start:end]) {
+			element.Classes = append(element.Classes, ClassToken{
+				Name: text[start+field[0] : start+field[1]],
+				Pos:  lines.position(path, start+field[0]),
+				End:  start + field[1],
+			})
+		}
+		elements = append(elements, element)
+	}
+	return elements
+}
+
+// jsxAttributeValue returns where the static value of the attribute whose
+// value starts at text[i] is, without its quotes
+func jsxAttributeValue(text string, i int) (start, end int, ok bool) {
+	if i >= len(text) {
+		return 0, 0, false
+	}
+	switch text[i] {
+	case '"', '\'':
+		// JSX attribute strings have no escapes and can span lines
+		end := strings.IndexByte(text[i+1:], text[i])
+		if end < 0 {
+			return 0, 0, false
+		}
+		return i + 1, i + 1 + end, true
+	case '{':
+		j := i + 1
+		for j < len(text) && isHTMLSpace(text[j]) {
+			j++
+		}
+		literal, next, ok := readStringLiteral(text, j)
+		if !ok || literal.escaped {
+			return 0, 0, false
+		}
+		for next < len(text) && isHTMLSpace(text[next]) {
+			next++
+		}
+		if next >= len(text) || text[next] != '}' {
+			return 0, 0, false
+		}
+		return literal.start, literal.end, true
+	}
+	return 0, 0, false
+}
+
+// jsxTag returns the name of the element whose attribute starts at i, or
+// an empty string when there's no tag before it
+func jsxTag(text string, i int) string {
+	tagStart := strings.LastIndexByte(text[:i], '<')
+	if tagStart < 0 || strings.ContainsAny(text[tagStart:i], ">") {
+		return ""
+	}
+	name := text[tagStart+1:]
+	end := 0
+	for end < len(name) && (isIdentifierByte(name[end]) || name[end] == '.' || name[end] == '-' || name[end] == ':') {
+		end++
+	}
+	return name[:end]
+}
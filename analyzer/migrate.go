@@ -0,0 +1,334 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MigrationRule is a change of Tailwind v4 that breaks v3 classes
+type MigrationRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// MigrationRules are the v3 to v4 changes the migration looks for, in the
+// order the report lists them
+var MigrationRules = []MigrationRule{
+	{"scale-rename", "the shadow, drop-shadow, blur and rounded scales moved down a step, shadow-sm is shadow-xs and shadow is shadow-sm"},
+	{"utility-rename", "utilities renamed in v4, like flex-grow to grow and outline-none to outline-hidden"},
+	{"ring-width", "ring is 1px wide in v4, ring-3 keeps the 3px of v3"},
+	{"opacity-modifier", "the color opacity utilities like bg-opacity-50 are gone, the color takes an opacity modifier like bg-red-500/50"},
+	{"important-suffix", "the ! important mark goes at the end of the class"},
+	{"default-border-color", "borders and divides default to currentColor instead of gray-200"},
+	{"default-ring-color", "rings default to currentColor instead of blue-500"},
+	{"space-selector", "space-x/y and divide-x/y select the children differently, flex or grid with gap is the v4 way"},
+}
+
+// MigrationChange is a class a migration rule applies to. Safe changes
+// are rewritten as To, the others need a look and Note says why
+type MigrationChange struct {
+	Rule string   `json:"rule"`
+	Safe bool     `json:"safe"`
+	Pos  Position `json:"pos"`
+	From string   `json:"from"`
+	To   string   `json:"to,omitempty"`
+	Note string   `json:"note,omitempty"`
+}
+
+var (
+	// migrationRenames are the utilities v4 renamed
+	migrationRenames = map[string]string{
+		"outline-none":      "outline-hidden",
+		"overflow-ellipsis": "text-ellipsis",
+		"decoration-slice":  "box-decoration-slice",
+		"decoration-clone":  "box-decoration-clone",
+	}
+	// migrationRootRenames are the roots v4 renamed, whatever their value
+	migrationRootRenames = map[string]string{
+		"flex-grow":      "grow",
+		"flex-shrink":    "shrink",
+		"bg-gradient-to": "bg-linear-to",
+	}
+	// migrationScales are the roots whose scale moved down a step
+	migrationScales = []string{"shadow", "drop-shadow", "blur", "backdrop-blur"}
+
+	opacityUtility = regexp.MustCompile(`^(bg|text|border|divide|ring|placeholder)-opacity-(.+)$`)
+)
+
+func init() {
+	for _, side := range []string{"", "-s", "-e", "-t", "-r", "-b", "-l", "-ss", "-se", "-ee", "-es", "-tl", "-tr", "-br", "-bl"} {
+		migrationScales = append(migrationScales, "rounded"+side)
+	}
+}
+
+// renameForV4 returns what a utility without variants, important mark,
+// prefix or minus sign is called in v4 and the rule renaming it
+func renameForV4(name string) (string, string, bool) {
+	if to, ok := migrationRenames[name]; ok {
+		return to, "utility-rename", true
+	}
+	for from, to := range migrationRootRenames {
+		if rest, ok := strings.CutPrefix(name, from); ok && (rest == "" || rest[0] == '-') {
+			return to + rest, "utility-rename", true
+		}
+	}
+	for _, root := range migrationScales {
+		switch name {
+		case root:
+			return root + "-sm", "scale-rename", true
+		case root + "-sm":
+			return root + "-xs", "scale-rename", true
+		}
+	}
+	if name == "ring" {
+		return "ring-3", "ring-width", true
+	}
+	return "", "", false
+}
+
+// migratingClass is a class of an element on its way to v4
+type migratingClass struct {
+	token                       ClassToken
+	class                       Class
+	variants, lead, base, trail string
+	// minus and name are base split into its minus sign and prefix, and
+	// the utility
+	minus, name string
+	modifier    string
+	remove      bool
+	changes     []MigrationChange
+}
+
+func (c *migratingClass) rewritten() string {
+	lead, trail := c.lead, c.trail
+	if lead == "!" {
+		lead, trail = "", "!"
+	}
+	return c.variants + lead + c.minus + c.name + c.modifier + trail
+}
+
+func (c *migratingClass) isColor(root string) bool {
+	if !c.class.Known || c.class.Modifier != "" {
+		return false
+	}
+	if root == "divide" {
+		return c.class.Utility == "divide" && (c.class.Value != "" || c.class.Arbitrary != "")
+	}
+	if c.class.Utility != root && !(root == "border" && strings.HasPrefix(c.class.Utility, "border-")) {
+		return false
+	}
+	return slices.ContainsFunc(classProperties(c.class), func(p string) bool { return p == "color" || strings.HasSuffix(p, "-color") })
+}
+
+// isWidth reports whether the class is a border, divide or ring width
+// from the theme, like border, border-t-2 or ring
+func (c *migratingClass) isWidth() bool {
+	if !c.class.Known || c.class.Arbitrary != "" || (c.class.Value != "" && !isDigits(c.class.Value)) {
+		return false
+	}
+	switch c.class.Utility {
+	case "ring", "divide-x", "divide-y":
+		return true
+	}
+	return slices.ContainsFunc(classProperties(c.class), func(p string) bool {
+		return strings.HasPrefix(p, "border") && strings.HasSuffix(p, "-width")
+	})
+}
+
+// migrateElement works out the changes v4 needs for the classes of element
+func migrateElement(element *Element, parser *ClassParser) []*migratingClass {
+	var classes []*migratingClass
+	for _, token := range element.Classes {
+		if strings.ContainsAny(token.Name, "{}$") {
+			continue
+		}
+		c := &migratingClass{token: token, class: parser.Parse(token.Name)}
+		c.variants, c.lead, c.base, c.trail = parser.splitRaw(token.Name)
+		c.name = c.base
+		if rest, ok := strings.CutPrefix(c.name, "-"); ok {
+			c.minus, c.name = "-", rest
+		}
+		if rest, ok := strings.CutPrefix(c.name, parser.Prefix); ok && parser.Prefix != "" {
+			c.minus, c.name = c.minus+parser.Prefix, rest
+		}
+		classes = append(classes, c)
+	}
+
+	change := func(c *migratingClass, rule string, safe bool, note string) {
+		c.changes = append(c.changes, MigrationChange{Rule: rule, Safe: safe, Pos: c.token.Pos, From: c.token.Name, Note: note})
+	}
+	for _, c := range classes {
+		if to, rule, ok := renameForV4(c.name); ok {
+			c.name = to
+			change(c, rule, true, "")
+		}
+		if c.lead == "!" {
+			change(c, "important-suffix", true, "")
+		}
+	}
+
+	for _, c := range classes {
+		m := opacityUtility.FindStringSubmatch(c.name)
+		if m == nil {
+			continue
+		}
+		root, value := m[1], m[2]
+		var colors []*migratingClass
+		for _, other := range classes {
+			if other != c && other.variants == c.variants && other.isColor(root) {
+				colors = append(colors, other)
+			}
+		}
+		if len(colors) != 1 {
+			change(c, "opacity-modifier", false, fmt.Sprintf("no single %s color under the same variants to take /%s", root, value))
+			continue
+		}
+		color := colors[0]
+		color.modifier = "/" + value
+		c.remove = true
+		change(c, "opacity-modifier", true, fmt.Sprintf("merged into %s", color.token.Name))
+		change(color, "opacity-modifier", true, fmt.Sprintf("takes the opacity of %s", c.token.Name))
+	}
+
+	// the default colors changed, a border or ring without one of its own
+	// looks different
+	hasColor := func(roots ...string) bool {
+		return slices.ContainsFunc(classes, func(c *migratingClass) bool {
+			return slices.ContainsFunc(roots, c.isColor)
+		})
+	}
+	borderColor, ringColor := hasColor("border", "divide"), hasColor("ring")
+	for _, c := range classes {
+		switch {
+		case !c.isWidth():
+		case !borderColor && (strings.HasPrefix(c.class.Utility, "border") || strings.HasPrefix(c.class.Utility, "divide-")):
+			change(c, "default-border-color", false, "add a color like border-gray-200 to keep the v3 look")
+			borderColor = true
+		case !ringColor && c.class.Utility == "ring":
+			change(c, "default-ring-color", false, "add a color like ring-blue-500 to keep the v3 look")
+			ringColor = true
+		}
+		if c.class.Known && (c.class.Utility == "space-x" || c.class.Utility == "space-y" || c.class.Utility == "divide-x" || c.class.Utility == "divide-y") {
+			change(c, "space-selector", false, "check the spacing of inline children, or use gap")
+		}
+	}
+
+	for _, c := range classes {
+		to := c.rewritten()
+		if c.remove {
+			to = ""
+		}
+		for i := range c.changes {
+			switch {
+			case !c.changes[i].Safe:
+			case element.Provenance == ProvenanceJS:
+				// scripts build class lists at runtime, they're left to a person
+				c.changes[i].Safe = false
+				c.changes[i].Note = "in a script, rewrite it by hand as " + to
+				if to == "" {
+					c.changes[i].Note = "in a script, remove it by hand"
+				}
+			default:
+				c.changes[i].To = to
+			}
+		}
+	}
+	return classes
+}
+
+// MigrateV4 returns the changes the classes of the project need to move
+// from Tailwind v3 to v4, ordered by file and position
+func MigrateV4(p *Project, parser *ClassParser) []MigrationChange {
+	var changes []MigrationChange
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			for _, c := range migrateElement(element, parser) {
+				changes = append(changes, c.changes...)
+			}
+		}
+	}
+	return changes
+}
+
+// MigrationEdits returns the edits making the safe changes to the class
+// attributes of file, the formatting of the attributes stays as it is
+func MigrationEdits(file *SourceFile, src []byte, parser *ClassParser) []Edit {
+	var edits []Edit
+	for _, element := range file.Elements {
+		if element.Provenance == ProvenanceJS || element.ValueEnd > len(src) {
+			continue
+		}
+		remove := make(map[int]bool)
+		for _, c := range migrateElement(element, parser) {
+			if !slices.ContainsFunc(c.changes, func(change MigrationChange) bool { return change.Safe }) {
+				continue
+			}
+			if c.remove {
+				remove[c.token.Pos.Offset] = true
+			} else if to := c.rewritten(); to != c.token.Name {
+				edits = append(edits, Edit{Start: c.token.Pos.Offset, End: c.token.End, Text: to})
+			}
+		}
+		edits = append(edits, removeTokens(src, element, remove)...)
+	}
+	return edits
+}
+
+// WriteMigrationReport writes the changes grouped by rule, the safe ones a
+// rewrite takes care of first and the ones that need a look after them
+func WriteMigrationReport(w io.Writer, changes []MigrationChange) error {
+	var b strings.Builder
+	// a class several rules apply to, like !shadow, counts once, and as one
+	// to look at when any of its changes needs a look
+	lookAt := make(map[Position]bool)
+	for _, c := range changes {
+		lookAt[c.Pos] = lookAt[c.Pos] || !c.Safe
+	}
+	look := 0
+	for _, l := range lookAt {
+		if l {
+			look++
+		}
+	}
+	fmt.Fprintf(&b, "%d classes to migrate to Tailwind v4, %d safe to rewrite, %d to look at\n", len(lookAt), len(lookAt)-look, look)
+	for _, section := range []struct {
+		title string
+		safe  bool
+	}{{"Safe to rewrite", true}, {"Needs a look", false}} {
+		fmt.Fprintf(&b, "\n%s\n", section.title)
+		empty := true
+		for _, rule := range MigrationRules {
+			var matching []MigrationChange
+			for _, c := range changes {
+				if c.Rule == rule.Name && c.Safe == section.safe {
+					matching = append(matching, c)
+				}
+			}
+			if len(matching) == 0 {
+				continue
+			}
+			empty = false
+			fmt.Fprintf(&b, "  %s (%d): %s\n", rule.Name, len(matching), rule.Description)
+			for _, c := range matching {
+				line := fmt.Sprintf("    %s: %s", c.Pos, c.From)
+				if c.Safe && c.To == "" {
+					line += " -> (removed)"
+				} else if c.Safe {
+					line += " -> " + c.To
+				}
+				if c.Note != "" {
+					line += " (" + c.Note + ")"
+				}
+				b.WriteString(line + "\n")
+			}
+		}
+		if empty {
+			b.WriteString("  (none)\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrateV4(t *testing.T) {
+	page := `<div class="shadow-sm md:shadow !shadow flex-grow outline-none !p-2 hover:!-mt-1 ring rounded-t">
+<p class="bg-red-500 bg-opacity-50 md:text-opacity-75 border-2 border-gray-300"></p>
+<ul class="space-y-2 divide-y bg-gradient-to-r flex-shrink-0"></ul>
+</div>
+<script>el.classList.add("flex-grow")</script>
+`
+	dir := writeProject(t, map[string]string{"index.html": page})
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := MigrateV4(project, DefaultParser)
+	expected := []string{
+		"scale-rename shadow-sm -> shadow-xs",
+		"scale-rename md:shadow -> md:shadow-sm",
+		"scale-rename !shadow -> shadow-sm!",
+		"important-suffix !shadow -> shadow-sm!",
+		"utility-rename flex-grow -> grow",
+		"utility-rename outline-none -> outline-hidden",
+		"important-suffix !p-2 -> p-2!",
+		"important-suffix hover:!-mt-1 -> hover:-mt-1!",
+		"ring-width ring -> ring-3",
+		"default-ring-color ring ?",
+		"scale-rename rounded-t -> rounded-t-sm",
+		"opacity-modifier bg-red-500 -> bg-red-500/50",
+		"opacity-modifier bg-opacity-50 -> ",
+		"opacity-modifier md:text-opacity-75 ?",
+		"space-selector space-y-2 ?",
+		"default-border-color divide-y ?",
+		"space-selector divide-y ?",
+		"utility-rename bg-gradient-to-r -> bg-linear-to-r",
+		"utility-rename flex-shrink-0 -> shrink-0",
+		"utility-rename flex-grow ?",
+	}
+	var got []string
+	for _, c := range changes {
+		if c.Safe {
+			got = append(got, c.Rule+" "+c.From+" -> "+c.To)
+		} else {
+			got = append(got, c.Rule+" "+c.From+" ?")
+		}
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected the changes\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	fixed, err := ApplyEdits([]byte(page), MigrationEdits(project.Files[0], []byte(page), DefaultParser))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<div class="shadow-xs md:shadow-sm shadow-sm! grow outline-hidden p-2! hover:-mt-1! ring-3 rounded-t-sm">
+<p class="bg-red-500/50 md:text-opacity-75 border-2 border-gray-300"></p>
+<ul class="space-y-2 divide-y bg-linear-to-r shrink-0"></ul>
+</div>
+<script>el.classList.add("flex-grow")</script>
+`
+	if string(fixed) != want {
+		t.Errorf("Expected the migrated page to be\n%s\ngot\n%s", want, fixed)
+	}
+
+	var report strings.Builder
+	if err := WriteMigrationReport(&report, changes); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		// !shadow and ring count once, ring as one to look at
+		"17 classes to migrate to Tailwind v4, 12 safe to rewrite, 5 to look at",
+		"  scale-rename (4): the shadow",
+		"  opacity-modifier (2): the color opacity utilities",
+		"    index.html:2:22: bg-opacity-50 -> (removed) (merged into bg-red-500)",
+		"    index.html:5:27: flex-grow (in a script, rewrite it by hand as grow)",
+	} {
+		if !strings.Contains(report.String(), line) {
+			t.Errorf("Expected the report to have %q, got\n%s", line, report.String())
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MinifyOptions says which classes minification leaves alone
+type MinifyOptions struct {
+	// AllowJS minifies the classes scripts use too, rewriting their
+	// string literals, they're kept otherwise
+	AllowJS bool
+	// Keep holds globs of classes to keep as they are
+	Keep []string
+}
+
+// ClassMap maps the classes of a project to short names
+type ClassMap struct {
+	Classes map[string]string `json:"classes"`
+	// Kept has why each class used in markup kept its name
+	Kept    map[string]string `json:"kept,omitempty"`
+	options MinifyOptions
+}
+
+// minifyAlphabet is what short names are made of, they start with a letter
+// and stay lowercase since quirks mode matches classes without case
+const minifyAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// shortName returns the nth short name: a to z, then aa to z9 and so on
+func shortName(n int) string {
+	length, size := 1, 26
+	for n >= size {
+		n -= size
+		length, size = length+1, size*len(minifyAlphabet)
+	}
+	name := make([]byte, length)
+	for i := length - 1; i > 0; i-- {
+		name[i] = minifyAlphabet[n%len(minifyAlphabet)]
+		n /= len(minifyAlphabet)
+	}
+	name[0] = minifyAlphabet[n]
+	return string(name)
+}
+
+// MinifyClasses maps the classes the markup uses and the project's plain
+// CSS stylesheets define to short names, the most used get the shortest
+// ones and ties go by name, so the same project always gets the same map.
+// Classes defined in SCSS, Less or CSS modules keep their names since those
+// stylesheets aren't rewritten, and so do the ones scripts use or name in
+// any string literal unless options allow it, names made up at runtime
+// can't be followed
+func MinifyClasses(p *Project, config *Config, options MinifyOptions) *ClassMap {
+	m := &ClassMap{Classes: make(map[string]string), Kept: make(map[string]string), options: options}
+	counts := make(map[string]int)
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			for _, token := range element.Classes {
+				if element.Provenance == ProvenanceJS && !options.AllowJS {
+					m.Kept[token.Name] = "used by a script"
+				} else if strings.ContainsAny(token.Name, "{}$") {
+					m.Kept[token.Name] = "template code"
+				}
+				counts[token.Name]++
+			}
+		}
+	}
+	if !options.AllowJS {
+		// a class can reach the DOM through a variable or a helper, any
+		// script string naming it keeps it
+		for _, file := range p.Files {
+			for _, word := range file.ScriptWords {
+				if counts[word] > 0 && m.Kept[word] == "" {
+					m.Kept[word] = "in a script string"
+				}
+			}
+		}
+	}
+
+	defined := make(map[string]bool)
+	for _, sheet := range p.Stylesheets {
+		plain := filepath.Ext(sheet.Path) == ".css" && !sheet.IsModule()
+		for _, definition := range sheet.Definitions() {
+			if !plain {
+				m.Kept[definition.Class] = "defined in " + sheet.Path
+			}
+			defined[definition.Class] = true
+		}
+	}
+
+	var classes []string
+	for class := range counts {
+		switch {
+		case m.Kept[class] != "":
+		case config.Allowed(class) || matchesGlob(options.Keep, class):
+			m.Kept[class] = "kept by a pattern"
+		case !defined[class]:
+			// styles nothing, third party scripts may look it up
+			m.Kept[class] = "not defined in a stylesheet"
+		default:
+			classes = append(classes, class)
+		}
+	}
+	for class := range m.Kept {
+		if counts[class] == 0 {
+			delete(m.Kept, class)
+		}
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		if counts[classes[i]] != counts[classes[j]] {
+			return counts[classes[i]] > counts[classes[j]]
+		}
+		return classes[i] < classes[j]
+	})
+
+	n := 0
+	for _, class := range classes {
+		name := shortName(n)
+		// a short name mustn't collide with a class that keeps its name
+		for counts[name] > 0 || defined[name] {
+			n++
+			name = shortName(n)
+		}
+		m.Classes[class] = name
+		n++
+	}
+	return m
+}
+
+func matchesGlob(patterns []string, class string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, class); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkupEdits returns the edits giving the classes of file their short
+// names
+func (m *ClassMap) MarkupEdits(file *SourceFile) []Edit {
+	var edits []Edit
+	for _, element := range file.Elements {
+		if element.Provenance == ProvenanceJS && !m.options.AllowJS {
+			continue
+		}
+		for _, token := range element.Classes {
+			if name, ok := m.Classes[token.Name]; ok {
+				edits = append(edits, Edit{Start: token.Pos.Offset, End: token.End, Text: name})
+			}
+		}
+	}
+	return edits
+}
+
+// StylesheetEdits returns the edits giving the class selectors of a plain
+// CSS stylesheet their short names, nothing else in it changes
+func (m *ClassMap) StylesheetEdits(sheet *Stylesheet) []Edit {
+	var edits []Edit
+	sheet.Walk(func(rule *CSSRule) {
+		if rule.AtRule != "" || !rule.HasBlock || rule.Parent != nil && rule.Parent.AtRule == "keyframes" {
+			return
+		}
+		end := preludeEnd(sheet.Source, rule.Start)
+		for _, span := range selectorClassSpans(sheet.Source[rule.Start:end]) {
+			if name, ok := m.Classes[span.name]; ok {
+				edits = append(edits, Edit{Start: rule.Start + span.start, End: rule.Start + span.end, Text: name})
+			}
+		}
+	})
+	return edits
+}
+
+// preludeEnd returns the offset of the '{' opening the block of the rule
+// starting at start
+func preludeEnd(src string, start int) int {
+	depth := 0
+	for i := start; i < len(src); i++ {
+		switch ch := src[i]; ch {
+		case '\\':
+			i++
+		case '"', '\'':
+			for i++; i < len(src) && src[i] != ch; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+		case '/':
+			if strings.HasPrefix(src[i:], "/*") {
+				if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+					i += end + 3
+				} else {
+					return len(src)
+				}
+			}
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '{':
+			if depth <= 0 {
+				return i
+			}
+		}
+	}
+	return len(src)
+}
+
+// WriteReport writes how many classes got short names and the bytes they
+// save in the given markup and stylesheet sources
+func (m *ClassMap) WriteReport(w io.Writer, saved int) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d classes get short names, %d keep theirs, %d bytes saved\n", len(m.Classes), len(m.Kept), saved)
+	reasons := make(map[string][]string)
+	for class, reason := range m.Kept {
+		reasons[reason] = append(reasons[reason], class)
+	}
+	var keys []string
+	for reason := range reasons {
+		keys = append(keys, reason)
+	}
+	sort.Strings(keys)
+	for _, reason := range keys {
+		classes := reasons[reason]
+		sort.Strings(classes)
+		fmt.Fprintf(&b, "  %s (%d): %s\n", reason, len(classes), strings.Join(classes, " "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// EditsSaved returns how many bytes shorter the edits make a source
+func EditsSaved(edits []Edit) int {
+	saved := 0
+	for _, e := range edits {
+		saved += e.End - e.Start - len(e.Text)
+	}
+	return saved
+}
@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShortName(t *testing.T) {
+	tests := map[int]string{0: "a", 25: "z", 26: "aa", 27: "ab", 61: "a9", 62: "ba", 26 + 26*36: "aaa"}
+	for n, expected := range tests {
+		if got := shortName(n); got != expected {
+			t.Errorf("%d: expected %s, got %s", n, expected, got)
+		}
+	}
+}
+
+func TestMinifyClasses(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<div class="card shadow js-toggle">
+  <p class="card-title card">Hi</p>
+  <span class="b note"></span>
+</div>
+<script>document.querySelector(".shadow").classList.add("open")</script>`,
+		"style.css": `.card { padding: 1rem }
+.card > .card-title, .b:hover { color: red }
+@media (min-width: 40rem) { .shadow.open { box-shadow: none } }
+/* .card in a comment */ .md\:card { color: blue }`,
+		"theme.scss": `.note { color: gray; }`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := MinifyClasses(project, &Config{Allowlist: []string{"js-*"}}, MinifyOptions{})
+	// b is taken by a class of the project, so the short names skip it
+	expected := map[string]string{"card": "a", "b": "c", "card-title": "d"}
+	if len(m.Classes) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, m.Classes)
+	}
+	for class, name := range expected {
+		if m.Classes[class] != name {
+			t.Errorf("Expected %s to be %s, got %q", class, name, m.Classes[class])
+		}
+	}
+	kept := map[string]string{
+		"shadow":    "used by a script",
+		"open":      "used by a script",
+		"js-toggle": "kept by a pattern",
+		"note":      "defined in theme.scss",
+	}
+	for class, reason := range kept {
+		if m.Kept[class] != reason {
+			t.Errorf("Expected %s to be kept for %q, got %q", class, reason, m.Kept[class])
+		}
+	}
+
+	var html *SourceFile
+	for _, file := range project.Files {
+		if file.Path == "index.html" {
+			html = file
+		}
+	}
+	out, err := ApplyEdits([]byte(files["index.html"]), m.MarkupEdits(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<div class="a shadow js-toggle">
+  <p class="d a">Hi</p>
+  <span class="c note"></span>
+</div>
+<script>document.querySelector(".shadow").classList.add("open")</script>`
+	if string(out) != want {
+		t.Errorf("Expected the page to be\n%s\ngot\n%s", want, out)
+	}
+
+	var sheet *Stylesheet
+	for _, s := range project.Stylesheets {
+		if s.Path == "style.css" {
+			sheet = s
+		}
+	}
+	out, err = ApplyEdits([]byte(sheet.Source), m.StylesheetEdits(sheet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCSS := `.a { padding: 1rem }
+.a > .d, .c:hover { color: red }
+@media (min-width: 40rem) { .shadow.open { box-shadow: none } }
+/* .card in a comment */ .md\:card { color: blue }`
+	if string(out) != wantCSS {
+		t.Errorf("Expected the stylesheet to be\n%s\ngot\n%s", wantCSS, out)
+	}
+
+	m = MinifyClasses(project, nil, MinifyOptions{AllowJS: true})
+	if m.Classes["shadow"] == "" || m.Classes["open"] == "" {
+		t.Errorf("Expected the classes of scripts to get short names, got %v", m.Classes)
+	}
+	out, err = ApplyEdits([]byte(files["index.html"]), m.MarkupEdits(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `document.querySelector(".` + m.Classes["shadow"] + `").classList.add("` + m.Classes["open"] + `")`; !strings.Contains(string(out), want) {
+		t.Errorf("Expected the script to become %s, got\n%s", want, out)
+	}
+}
+
+func TestMinifyKeepsScriptStrings(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<div class="menu open-state">
+  <a class="item">Home</a>
+</div>
+<script>
+  const cls = isOpen ? 'open-state' : 'closed';
+  el.classList.add(cls);
+</script>`,
+		"app.js":    "const selector = `.menu > .${current}`;\nfind(selector);",
+		"style.css": `.menu, .item, .open-state { color: red }`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := MinifyClasses(project, nil, MinifyOptions{})
+	for _, class := range []string{"open-state", "menu"} {
+		if m.Kept[class] != "in a script string" {
+			t.Errorf("Expected %s to be kept for being in a script string, got %q", class, m.Kept[class])
+		}
+	}
+	if len(m.Classes) != 1 || m.Classes["item"] != "a" {
+		t.Errorf("Expected only item to get a short name, got %v", m.Classes)
+	}
+
+	m = MinifyClasses(project, nil, MinifyOptions{AllowJS: true})
+	if m.Classes["open-state"] == "" {
+		t.Errorf("Expected open-state to get a short name with AllowJS, got %v", m.Classes)
+	}
+}
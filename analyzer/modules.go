@@ -0,0 +1,247 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// IsModule reports whether the stylesheet is a CSS module, Button.module.css,
+// whose classes are scoped to the scripts importing it
+func (s *Stylesheet) IsModule() bool {
+	return strings.Contains(filepath.Base(s.Path), ".module.")
+}
+
+// moduleClasses splits the classes of a CSS module selector into the local
+// ones and the ones under :global, ":global(.a) .b" and ":global .a .b"
+// both make .a global
+func moduleClasses(selector string) (local, global []string) {
+	var b strings.Builder
+	for {
+		i := strings.Index(selector, ":global(")
+		if i < 0 {
+			break
+		}
+		inner, end := functionArgument(selector, i+len(":global"))
+		global = append(global, selectorClasses(inner)...)
+		b.WriteString(selector[:i])
+		if end >= len(selector) {
+			selector = ""
+			break
+		}
+		selector = selector[end+1:]
+	}
+	b.WriteString(selector)
+	before, after, _ := strings.Cut(b.String(), ":global")
+	return selectorClasses(before), append(global, selectorClasses(after)...)
+}
+
+// cssModule is what the scripts of the project use of one CSS module
+type cssModule struct {
+	sheet *Stylesheet
+	// classes maps every key a script can use to the class, "is-active" can
+	// also be looked up as isActive with camelCase conversion turned on
+	classes map[string]string
+	used    map[string]bool
+	dynamic bool
+	refs    []ModuleReference
+}
+
+func newCSSModule(sheet *Stylesheet) *cssModule {
+	m := &cssModule{sheet: sheet, classes: make(map[string]string), used: make(map[string]bool)}
+	for _, d := range sheet.Definitions() {
+		local, _ := moduleClasses(d.Selector)
+		for _, class := range local {
+			m.classes[class] = class
+			if camel := camelCase(class); camel != class {
+				m.classes[camel] = class
+			}
+		}
+	}
+	return m
+}
+
+func camelCase(class string) string {
+	parts := strings.Split(class, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// cssModules links every module lookup of the project's scripts to the
+// module it imports, lookups of modules that aren't in the project are
+// returned on their own
+func (p *Project) cssModules() (map[string]*cssModule, []ModuleReference) {
+	modules := make(map[string]*cssModule)
+	var sheets []*Stylesheet
+	for _, sheet := range p.Stylesheets {
+		if sheet.IsModule() {
+			modules[sheet.Path] = newCSSModule(sheet)
+			sheets = append(sheets, sheet)
+		}
+	}
+
+	var missing []ModuleReference
+	for _, file := range p.Files {
+		for _, ref := range file.ModuleRefs {
+			m := modules[ref.Module]
+			if m == nil {
+				m = modules[findModule(sheets, ref.Module)]
+			}
+			if m == nil {
+				missing = append(missing, ref)
+				continue
+			}
+			m.refs = append(m.refs, ref)
+			if ref.Dynamic {
+				m.dynamic = true
+			} else if class, ok := m.classes[ref.Key]; ok {
+				m.used[class] = true
+			}
+		}
+	}
+
+	// "composes: base" makes base part of every class composing it, from
+	// another module too
+	for changed := true; changed; {
+		changed = false
+		for _, m := range modules {
+			for _, c := range m.compositions() {
+				target := m
+				if c.from != "" {
+					target = modules[findModule(sheets, resolveModule(m.sheet.Path, c.from))]
+				}
+				if target == nil || !(m.dynamic || m.usesAny(c.composers)) {
+					continue
+				}
+				for _, class := range c.classes {
+					if !target.used[class] {
+						target.used[class] = true
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return modules, missing
+}
+
+// findModule returns the path of the module an import that isn't relative
+// points to, "@/styles/x.module.css" and "~/x.module.css" style aliases
+// are matched on the end of the path
+func findModule(sheets []*Stylesheet, module string) string {
+	module = strings.TrimLeft(filepath.ToSlash(module), "@~/")
+	for _, sheet := range sheets {
+		if name := filepath.ToSlash(sheet.Path); name == module || strings.HasSuffix(name, "/"+module) {
+			return sheet.Path
+		}
+	}
+	return ""
+}
+
+type composition struct {
+	composers []string
+	classes   []string
+	from      string
+}
+
+func (m *cssModule) compositions() []composition {
+	var compositions []composition
+	m.sheet.Walk(func(rule *CSSRule) {
+		for _, d := range rule.Declarations {
+			if d.Property != "composes" {
+				continue
+			}
+			c := composition{}
+			names, from, _ := strings.Cut(d.Value, " from ")
+			if c.from = unquoteCSS(from); c.from == "global" {
+				continue
+			}
+			c.classes = strings.Fields(names)
+			for _, selector := range rule.Selectors {
+				local, _ := moduleClasses(selector)
+				c.composers = append(c.composers, local...)
+			}
+			compositions = append(compositions, c)
+		}
+	})
+	return compositions
+}
+
+func (m *cssModule) usesAny(classes []string) bool {
+	for _, class := range classes {
+		if m.used[class] {
+			return true
+		}
+	}
+	return false
+}
+
+// unused returns the definitions of the module no script uses, global
+// classes count as used when the markup uses them, a dynamic lookup like
+// styles[variant] could be any class so it keeps them all
+func (m *cssModule) unused(markup map[string]bool) []ClassDefinition {
+	if m.dynamic {
+		return nil
+	}
+	var unused []ClassDefinition
+	for _, d := range m.sheet.Definitions() {
+		local, _ := moduleClasses(d.Selector)
+		if slices.Contains(local, d.Class) && !m.used[d.Class] || !slices.Contains(local, d.Class) && !markup[d.Class] {
+			unused = append(unused, d)
+		}
+	}
+	return unused
+}
+
+// MissingModuleKeys returns the lookups of keys a CSS module doesn't have
+// and of modules that aren't in the project, grouped like Undefined
+func (p *Project) MissingModuleKeys() []UndefinedClass {
+	modules, missing := p.cssModules()
+	var undefined []UndefinedClass
+	index := make(map[string]int)
+	add := func(ref ModuleReference, problem string, suggestions []string) {
+		key := ref.Module + "\x00" + ref.Key
+		i, exists := index[key]
+		if !exists {
+			i = len(undefined)
+			index[key] = i
+			undefined = append(undefined, UndefinedClass{Class: ref.Key, Problem: problem, Suggestions: suggestions})
+		}
+		undefined[i].Usages = append(undefined[i].Usages, ref.Pos)
+	}
+
+	for _, ref := range missing {
+		if !ref.Dynamic {
+			add(ref, fmt.Sprintf("CSS module %s isn't in the project", ref.Module), nil)
+		}
+	}
+	for _, sheet := range p.Stylesheets {
+		m := modules[sheet.Path]
+		if m == nil {
+			continue
+		}
+		for _, ref := range m.refs {
+			if _, ok := m.classes[ref.Key]; ok || ref.Dynamic {
+				continue
+			}
+			add(ref, fmt.Sprintf("no class %q in %s", ref.Key, sheet.Path), m.suggest(ref.Key))
+		}
+	}
+	return undefined
+}
+
+// suggest returns the module's classes closest to a missing key
+func (m *cssModule) suggest(key string) []string {
+	var scored []scoredSuggestion
+	for candidate := range m.classes {
+		if distance := levenshtein(key, candidate); distance <= maxSuggestionDistance {
+			scored = append(scored, scoredSuggestion{candidate, distance})
+		}
+	}
+	return bestSuggestions(scored)
+}
@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestModuleClasses(t *testing.T) {
+	tests := []struct {
+		selector      string
+		local, global []string
+	}{
+		{".button.primary", []string{"button", "primary"}, nil},
+		{":global(.dark) .button", []string{"button"}, []string{"dark"}},
+		{".button :global .icon", []string{"button"}, []string{"icon"}},
+	}
+	for _, test := range tests {
+		local, global := moduleClasses(test.selector)
+		if !slices.Equal(local, test.local) || !slices.Equal(global, test.global) {
+			t.Errorf("%s: expected %v and %v, got %v and %v", test.selector, test.local, test.global, local, global)
+		}
+	}
+}
+
+func TestCSSModules(t *testing.T) {
+	files := map[string]string{
+		"components/Button.tsx": `import styles from './Button.module.css'
+export const Button = () => <button className={styles.primary + ' ' + styles.isActive + ' ' + styles.primray} />
+`,
+		"components/Button.module.css": `.base { padding: 1rem }
+.primary { composes: base; color: blue }
+.is-active { outline: 1px solid }
+.danger { color: red }
+:global(.dark) .primary { color: white }
+`,
+		"components/Card.jsx": `import styles from './Card.module.css'
+import missing from './Gone.module.css'
+export const Card = ({ tone }) => <div className={styles[tone]}>{missing.title}</div>
+`,
+		"components/Card.module.css": `.quiet { color: gray } .loud { color: black }`,
+		"index.html":                 `<div class="light"></div>`,
+	}
+	dir := writeProject(t, files)
+
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unused []string
+	for _, d := range project.UnusedClasses() {
+		unused = append(unused, d.Class)
+	}
+	if !slices.Equal(unused, []string{"danger", "dark"}) {
+		t.Errorf("Expected danger and dark to be unused, got %v", unused)
+	}
+	if defined := project.DefinedClasses(); defined["primary"] || !defined["dark"] {
+		t.Errorf("Expected only the global class to be defined for markup, got %v", defined)
+	}
+
+	missing := project.MissingModuleKeys()
+	if len(missing) != 2 {
+		t.Fatalf("Expected 2 missing keys, got %+v", missing)
+	}
+	if missing[0].Class != "title" || missing[0].Problem != "CSS module "+filepath.Join("components", "Gone.module.css")+" isn't in the project" {
+		t.Errorf("Unexpected missing module %+v", missing[0])
+	}
+	if missing[1].Class != "primray" || !slices.Equal(missing[1].Suggestions, []string{"primary"}) || missing[1].Usages[0].Line != 2 {
+		t.Errorf("Unexpected missing key %+v", missing[1])
+	}
+}
@@ -0,0 +1,329 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// utilityOrder is the order Tailwind v3 emits utilities in, which is the
+// order prettier-plugin-tailwindcss sorts classes by. An entry is a whole
+// utility name, a root and the first property it sets ("text:font-size"),
+// a root or a property, classUtilityOrder tries them in that order
+var utilityOrder = []string{
+	"container", "sr-only", "not-sr-only", "pointer-events", "visibility", "position",
+	"inset", "inset-x", "inset-y", "start", "end", "top", "right", "bottom", "left",
+	"isolation", "z-index", "order",
+	"grid-column", "grid-column-start", "grid-column-end", "grid-row", "grid-row-start", "grid-row-end",
+	"float", "clear",
+	"m", "mx", "my", "ms", "me", "mt", "mr", "mb", "ml",
+	"box-sizing", "-webkit-line-clamp", "display", "aspect-ratio",
+	"size", "h", "max-h", "min-h", "w", "min-w", "max-w",
+	"flex:flex", "flex-shrink", "flex-grow", "flex-basis",
+	"table-layout", "caption-side", "border-collapse", "border-spacing", "--tw-border-spacing-x", "--tw-border-spacing-y",
+	"transform-origin", "perspective",
+	"translate", "translate-x", "translate-y", "rotate", "skew", "skew-x", "skew-y", "scale", "scale-x", "scale-y",
+	"transform", "transform-gpu", "transform-cpu",
+	"animation", "cursor", "touch-action", "user-select", "resize", "snap",
+	"scroll-m", "scroll-mx", "scroll-my", "scroll-ms", "scroll-me", "scroll-mt", "scroll-mr", "scroll-mb", "scroll-ml",
+	"scroll-p", "scroll-px", "scroll-py", "scroll-ps", "scroll-pe", "scroll-pt", "scroll-pr", "scroll-pb", "scroll-pl",
+	"list-style-position", "list-style-type", "list-style-image", "appearance", "field-sizing", "columns",
+	"break-before", "break-inside", "break-after",
+	"grid-auto-columns", "grid-auto-flow", "grid-auto-rows", "grid-template-columns", "grid-template-rows",
+	"flex-direction", "flex-wrap",
+	"place-content", "place-items", "align-content", "align-items", "justify-content", "justify-items",
+	"gap", "gap-x", "gap-y", "space-x", "space-y", "space-x-reverse", "space-y-reverse",
+	"divide-x", "divide-y", "divide-x-reverse", "divide-y-reverse", "divide", "divide-opacity",
+	"place-self", "align-self", "justify-self",
+	"overflow", "overflow-x", "overflow-y", "overscroll-behavior", "overscroll-behavior-x", "overscroll-behavior-y",
+	"scroll-behavior", "truncate", "text-overflow", "hyphens", "white-space", "text-wrap",
+	"break-normal", "overflow-wrap", "word-break",
+	"rounded", "rounded-s", "rounded-e", "rounded-t", "rounded-r", "rounded-b", "rounded-l",
+	"rounded-ss", "rounded-se", "rounded-ee", "rounded-es", "rounded-tl", "rounded-tr", "rounded-br", "rounded-bl",
+	"border:border-width", "border-x:border-left-width", "border-y:border-top-width",
+	"border-s:border-inline-start-width", "border-e:border-inline-end-width", "border-t:border-top-width",
+	"border-r:border-right-width", "border-b:border-bottom-width", "border-l:border-left-width",
+	"border-style",
+	"border:border-color", "border-x:border-left-color", "border-y:border-top-color",
+	"border-s:border-inline-start-color", "border-e:border-inline-end-color", "border-t:border-top-color",
+	"border-r:border-right-color", "border-b:border-bottom-color", "border-l:border-left-color",
+	"border-opacity",
+	"background-color", "bg-opacity", "background-image", "--tw-gradient-from", "--tw-gradient-via", "--tw-gradient-to",
+	"box-decoration-break", "background-size", "background-attachment", "background-clip",
+	"background-position", "background-repeat", "background-origin",
+	"fill", "stroke", "stroke-width", "object-fit", "object-position",
+	"p", "px", "py", "ps", "pe", "pt", "pr", "pb", "pl",
+	"text-align", "text-indent", "vertical-align", "font-family", "font-size", "font-weight", "text-transform",
+	"font-style", "normal-nums", "ordinal", "slashed-zero", "lining-nums", "oldstyle-nums", "proportional-nums",
+	"tabular-nums", "diagonal-fractions", "stacked-fractions",
+	"line-height", "letter-spacing", "placeholder", "placeholder-opacity", "color", "text-opacity",
+	"text-decoration-line", "text-decoration-color", "text-decoration-style", "text-decoration-thickness",
+	"text-underline-offset", "-webkit-font-smoothing",
+	"caret-color", "accent-color", "opacity", "background-blend-mode", "mix-blend-mode",
+	"box-shadow", "--tw-shadow-color", "inset-shadow", "text-shadow",
+	"outline", "outline-style", "outline-width", "outline-offset", "outline-color",
+	"--tw-ring-width", "ring-inset", "--tw-ring-color", "ring-opacity", "inset-ring",
+	"--tw-ring-offset-width", "--tw-ring-offset-color",
+	"--tw-blur", "--tw-brightness", "--tw-contrast", "--tw-drop-shadow", "--tw-grayscale", "--tw-hue-rotate",
+	"--tw-invert", "--tw-saturate", "--tw-sepia", "filter",
+	"--tw-backdrop-blur", "--tw-backdrop-brightness", "--tw-backdrop-contrast", "--tw-backdrop-grayscale",
+	"--tw-backdrop-hue-rotate", "--tw-backdrop-invert", "--tw-backdrop-opacity", "--tw-backdrop-saturate",
+	"--tw-backdrop-sepia", "backdrop-filter", "mask",
+	"transition-property", "transition-delay", "transition-duration", "transition-timing-function",
+	"will-change", "content", "forced-color-adjust", "color-scheme",
+}
+
+// variantOrder is the order Tailwind v3 registers variants in, entries
+// ending in * take any value
+var variantOrder = []string{
+	"*",
+	"first-letter", "first-line", "marker", "selection", "file", "placeholder", "backdrop", "before", "after",
+	"first", "last", "only", "odd", "even", "first-of-type", "last-of-type", "only-of-type", "visited",
+	"target", "open", "default", "checked", "indeterminate", "placeholder-shown", "autofill", "optional",
+	"required", "valid", "invalid", "in-range", "out-of-range", "read-only", "empty", "focus-within",
+	"hover", "focus", "focus-visible", "active", "enabled", "disabled",
+	"group-*", "peer-*",
+	"has-*", "group-has-*", "peer-has-*", "aria-*", "group-aria-*", "peer-aria-*",
+	"data-*", "group-data-*", "peer-data-*", "supports-*",
+	"ltr", "rtl", "motion-safe", "motion-reduce", "dark", "print",
+	"sm", "md", "lg", "xl", "2xl", "max-*", "min-*",
+	"portrait", "landscape", "contrast-more", "contrast-less", "forced-colors",
+}
+
+var (
+	utilityRank = rankIndex(utilityOrder)
+	variantRank = rankIndex(variantOrder)
+	// pseudoVariantCount is how many of the variants group- and peer- take
+	pseudoVariantCount = slices.Index(variantOrder, "group-*") - 1
+)
+
+func rankIndex(order []string) map[string]int {
+	index := make(map[string]int, len(order))
+	for i, key := range order {
+		index[key] = i
+	}
+	return index
+}
+
+// classUtilityOrder returns where a utility goes, false for classes
+// Tailwind doesn't know
+func classUtilityOrder(class Class) (int, bool) {
+	if !class.Known {
+		return 0, false
+	}
+	if class.Property != "" {
+		// arbitrary properties come after every utility
+		return len(utilityOrder), true
+	}
+	properties := classProperties(class)
+	candidates := []string{class.Utility + "-" + class.Value}
+	if class.Value == "" {
+		candidates[0] = class.Utility
+	}
+	if len(properties) > 0 {
+		candidates = append(candidates, class.Utility+":"+properties[0])
+	}
+	candidates = append(candidates, class.Utility)
+	if len(properties) > 0 {
+		candidates = append(candidates, properties[0])
+	}
+	for _, candidate := range candidates {
+		if rank, ok := utilityRank[candidate]; ok {
+			return rank, true
+		}
+	}
+	return 0, false
+}
+
+// variantKey is where a variant goes, variants Tailwind doesn't know come
+// after the ones it does, by name
+type variantKey struct {
+	rank int
+	name string
+}
+
+func orderOfVariant(variant string) variantKey {
+	name := variant
+	if i := strings.LastIndexByte(name, '/'); i > 0 && !strings.HasSuffix(name, "]") {
+		// group-hover/item names the group
+		name = name[:i]
+	}
+	if rank, ok := variantRank[name]; ok {
+		return variantKey{rank, variant}
+	}
+	for _, family := range []string{"group-", "peer-"} {
+		if rest, ok := strings.CutPrefix(name, family); ok {
+			if rank, ok := variantRank[rest]; ok && rank <= pseudoVariantCount {
+				// group-hover goes with the group variants, in hover's place
+				return variantKey{variantRank[family+"*"]*len(variantOrder) + rank, variant}
+			}
+		}
+	}
+	best, bestLength := -1, 0
+	for i, entry := range variantOrder {
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok && prefix != "" && strings.HasPrefix(name, prefix) && len(prefix) > bestLength {
+			best, bestLength = i, len(prefix)
+		}
+	}
+	if best >= 0 {
+		return variantKey{best * len(variantOrder), variant}
+	}
+	if strings.HasPrefix(name, "[") {
+		return variantKey{len(variantOrder) * len(variantOrder), variant}
+	}
+	return variantKey{len(variantOrder)*len(variantOrder) + 1, variant}
+}
+
+// compareVariants orders classes the way Tailwind's variant bits do: no
+// variants first, then by the latest variant, then the one before it
+func compareVariants(a, b []string) int {
+	keys := func(variants []string) []variantKey {
+		var keys []variantKey
+		for _, v := range variants {
+			keys = append(keys, orderOfVariant(v))
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].rank != keys[j].rank {
+				return keys[i].rank > keys[j].rank
+			}
+			return keys[i].name > keys[j].name
+		})
+		return keys
+	}
+	ka, kb := keys(a), keys(b)
+	for i := 0; i < len(ka) && i < len(kb); i++ {
+		switch {
+		case ka[i].rank != kb[i].rank:
+			return ka[i].rank - kb[i].rank
+		case ka[i].name != kb[i].name:
+			return strings.Compare(ka[i].name, kb[i].name)
+		}
+	}
+	return len(ka) - len(kb)
+}
+
+// SortClasses returns classes in the order prettier-plugin-tailwindcss
+// puts them in: classes Tailwind doesn't know first as they were, then the
+// utilities without variants in the order Tailwind emits them, then the
+// ones with variants by variant. Custom utilities of the catalog are left
+// at the front too, catalog can be nil
+func SortClasses(classes []string, parser *ClassParser, catalog *Catalog) []string {
+	type entry struct {
+		raw      string
+		known    bool
+		variants []string
+		rank     int
+	}
+	entries := make([]entry, len(classes))
+	for i, raw := range classes {
+		class := parser.Parse(raw)
+		e := entry{raw: raw, variants: class.Variants}
+		if catalog == nil || !catalog.customUtility(class) {
+			e.rank, e.known = classUtilityOrder(class)
+		}
+		entries[i] = e
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.known != b.known {
+			return !a.known
+		}
+		if !a.known {
+			return false
+		}
+		if c := compareVariants(a.variants, b.variants); c != 0 {
+			return c < 0
+		}
+		return a.rank < b.rank
+	})
+	sorted := make([]string, len(entries))
+	for i, e := range entries {
+		sorted[i] = e.raw
+	}
+	return sorted
+}
+
+// templateMarkers are what template languages put inside attribute values,
+// moving classes around them would break the template
+var templateMarkers = []string{"{", "}", "<%", "%>", "<?", "?>", "$"}
+
+// rewritable reports whether the classes of an element can be moved around
+// or rewritten, class lists of script calls aren't attributes and templates
+// interleave theirs with code
+func rewritable(element *Element) bool {
+	if element.Provenance == ProvenanceJS || len(element.Classes) < 2 {
+		return false
+	}
+	for _, token := range element.Classes {
+		for _, marker := range templateMarkers {
+			if strings.Contains(token.Name, marker) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ClassOrder returns a finding for every class attribute whose classes
+// aren't in canonical order, at the first class out of place
+func ClassOrder(p *Project, parser *ClassParser, catalog *Catalog) []Finding {
+	var findings []Finding
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			if !rewritable(element) {
+				continue
+			}
+			names := make([]string, len(element.Classes))
+			for i, token := range element.Classes {
+				names[i] = token.Name
+			}
+			sorted := SortClasses(names, parser, catalog)
+			for i, token := range element.Classes {
+				if sorted[i] != token.Name {
+					findings = append(findings, Finding{
+						Rule:    "class-order",
+						Pos:     token.Pos,
+						Class:   token.Name,
+						Message: fmt.Sprintf("classes aren't in canonical order, expected %q", strings.Join(sorted, " ")),
+					})
+					break
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// OrderEdits returns the edits putting the classes of every class
+// attribute of file in canonical order. The classes trade places and the
+// whitespace between them stays where it is, so attributes spread over
+// several lines keep their shape
+func OrderEdits(file *SourceFile, src []byte, parser *ClassParser, catalog *Catalog) []Edit {
+	var edits []Edit
+	for _, element := range file.Elements {
+		if !rewritable(element) || element.ValueEnd > len(src) {
+			continue
+		}
+		names := make([]string, len(element.Classes))
+		raw := make(map[string]string, len(element.Classes))
+		for i, token := range element.Classes {
+			names[i] = token.Name
+			raw[token.Name] = string(src[token.Pos.Offset:token.End])
+		}
+		sorted := SortClasses(names, parser, catalog)
+		if slices.Equal(sorted, names) {
+			continue
+		}
+		var b strings.Builder
+		first, last := element.Classes[0], element.Classes[len(element.Classes)-1]
+		for i, token := range element.Classes {
+			if i > 0 {
+				b.Write(src[element.Classes[i-1].End:token.Pos.Offset])
+			}
+			b.WriteString(raw[sorted[i]])
+		}
+		edits = append(edits, Edit{Start: first.Pos.Offset, End: last.End, Text: b.String()})
+	}
+	return edits
+}
@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortClasses(t *testing.T) {
+	tests := map[string]string{
+		"text-white px-4 sm:px-8 py-2 sm:py-3 bg-sky-700 hover:bg-sky-800": "bg-sky-700 px-4 py-2 text-white hover:bg-sky-800 sm:px-8 sm:py-3",
+		"p-4 justify-between card items-center flex":                       "card flex items-center justify-between p-4",
+		"lg:hidden md:flex dark:block":                                     "dark:block md:flex lg:hidden",
+		"hover:focus:text-red-500 focus:text-blue-500 hover:text-blue-500": "hover:text-blue-500 focus:text-blue-500 hover:focus:text-red-500",
+		"[mask-type:luminance] mt-2 absolute":                              "absolute mt-2 [mask-type:luminance]",
+		"font-bold text-sm text-center":                                    "text-center text-sm font-bold",
+		"group-hover:underline hover:underline group peer":                 "group peer hover:underline group-hover:underline",
+		"border-red-500 border-2 rounded":                                  "rounded border-2 border-red-500",
+		"shadow ring-2 md:p-2 max-md:p-1":                                  "shadow ring-2 md:p-2 max-md:p-1",
+	}
+	for in, expected := range tests {
+		if got := strings.Join(SortClasses(strings.Fields(in), DefaultParser, NewCatalog()), " "); got != expected {
+			t.Errorf("%s: expected %q, got %q", in, expected, got)
+		}
+	}
+}
+
+func TestClassOrder(t *testing.T) {
+	page := `<div class="text-white px-4 bg-sky-700"></div>
+<div class="flex p-4"></div>
+<ul class="
+    mt-2 flex
+    hover:underline   p-2
+"></ul>
+<p class="{{ cls }} p-2 flex"></p>
+`
+	files := map[string]string{
+		"index.html": page,
+		"Card.jsx":   `export const Card = () => <div className="p-2 flex">{children}</div>`,
+		"app.js":     `el.classList.add("p-2", "flex")`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := ClassOrder(project, DefaultParser, NewCatalog())
+	expected := []struct {
+		file         string
+		line, column int
+		class        string
+	}{{"Card.jsx", 1, 43, "p-2"}, {"index.html", 1, 13, "text-white"}, {"index.html", 5, 5, "hover:underline"}}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %v", len(expected), findings)
+	}
+	for i, e := range expected {
+		if f := findings[i]; f.Pos.File != e.file || f.Pos.Line != e.line || f.Pos.Column != e.column || f.Class != e.class || f.Rule != "class-order" {
+			t.Errorf("Expected %s at %s:%d:%d, got %v", e.class, e.file, e.line, e.column, f)
+		}
+	}
+	if message := findings[1].Message; message != `classes aren't in canonical order, expected "bg-sky-700 px-4 text-white"` {
+		t.Errorf("Unexpected message %q", message)
+	}
+
+	var html *SourceFile
+	for _, file := range project.Files {
+		if file.Path == "index.html" {
+			html = file
+		}
+	}
+	fixed, err := ApplyEdits([]byte(page), OrderEdits(html, []byte(page), DefaultParser, NewCatalog()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<div class="bg-sky-700 px-4 text-white"></div>
+<div class="flex p-4"></div>
+<ul class="
+    mt-2 flex
+    p-2   hover:underline
+"></ul>
+<p class="{{ cls }} p-2 flex"></p>
+`
+	if string(fixed) != want {
+		t.Errorf("Expected the fixed page to be\n%s\ngot\n%s", want, fixed)
+	}
+}
@@ -0,0 +1,329 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PurgeOptions are the safelist options of the config, PurgeCSS style, every
+// entry is a regular expression matched against class names
+type PurgeOptions struct {
+	// Safelist keeps the classes it matches as if they were used
+	Safelist []string `json:"safelist,omitempty"`
+	// Deep keeps a rule whose selector mentions a matching class along with
+	// everything nested in it, ".modal .anything" stays for "^modal$"
+	Deep []string `json:"deep,omitempty"`
+	// Greedy keeps any selector that mentions a matching class at all
+	Greedy []string `json:"greedy,omitempty"`
+}
+
+// compile turns the patterns into regular expressions
+func (o PurgeOptions) compile() (safelist, deep, greedy []*regexp.Regexp, err error) {
+	lists := []struct {
+		patterns []string
+		into     *[]*regexp.Regexp
+	}{{o.Safelist, &safelist}, {o.Deep, &deep}, {o.Greedy, &greedy}}
+	for _, list := range lists {
+		for _, pattern := range list.patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("bad purge pattern %q: %w", pattern, err)
+			}
+			*list.into = append(*list.into, re)
+		}
+	}
+	return safelist, deep, greedy, nil
+}
+
+// Purger drops the rules of a stylesheet that can't match any markup
+type Purger struct {
+	// Used is the set of classes the markup references
+	Used map[string]bool
+	// Keep reports whether an unused class must be kept anyway, like the
+	// ones in the Tailwind safelist
+	Keep                   func(class string) bool
+	safelist, deep, greedy []*regexp.Regexp
+}
+
+// NewPurger returns a purger for the used classes honoring the options
+func NewPurger(used map[string]bool, options PurgeOptions) (*Purger, error) {
+	safelist, deep, greedy, err := options.compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Purger{Used: used, safelist: safelist, deep: deep, greedy: greedy}, nil
+}
+
+// PurgeResult is the purged version of a stylesheet
+type PurgeResult struct {
+	Path         string `json:"path"`
+	Before       int    `json:"before"`
+	After        int    `json:"after"`
+	RemovedRules int    `json:"removedRules"`
+	CSS          string `json:"-"`
+}
+
+// purgeGroupingRules are the at-rules purge looks into, any other at-rule is
+// kept as it is
+var purgeGroupingRules = toSet("media", "supports", "layer", "container", "document", "scope", "starting-style")
+
+func (pg *Purger) used(class string) bool {
+	if pg.Used[class] || (pg.Keep != nil && pg.Keep(class)) {
+		return true
+	}
+	return matchesAny(pg.safelist, class)
+}
+
+func matchesAny(patterns []*regexp.Regexp, class string) bool {
+	for _, re := range patterns {
+		if re.MatchString(class) {
+			return true
+		}
+	}
+	return false
+}
+
+// purgeEdit replaces src[start:end] with text
+type purgeEdit struct {
+	start, end int
+	text       string
+}
+
+// Purge removes the style rules none of whose selectors can match, selector
+// lists lose the selectors that can't, at-rules left empty go too and
+// keyframes stay only when a kept rule animates with them
+func (pg *Purger) Purge(sheet *Stylesheet) *PurgeResult {
+	result := &PurgeResult{Path: sheet.Path, Before: len(sheet.Source)}
+	var edits []purgeEdit
+	var keyframes []*CSSRule
+	animations := make(map[string]bool)
+
+	// purgeRules returns whether anything in rules is left
+	var purgeRules func(rules []*CSSRule, deep bool) bool
+	purgeRules = func(rules []*CSSRule, deep bool) bool {
+		left := false
+		for _, rule := range rules {
+			switch {
+			case rule.AtRule == "keyframes" || strings.HasSuffix(rule.AtRule, "-keyframes"):
+				keyframes = append(keyframes, rule)
+				left = true
+			case rule.AtRule != "":
+				if _, grouping := purgeGroupingRules[rule.AtRule]; !grouping || !rule.HasBlock {
+					left = true
+					continue
+				}
+				if purgeRules(rule.Rules, deep) || len(rule.Declarations) > 0 {
+					left = true
+					continue
+				}
+				edits = append(edits, pg.removal(sheet, rule))
+				result.RemovedRules++
+			default:
+				var kept []string
+				keepDeep := deep
+				for _, selector := range rule.Selectors {
+					isDeep := pg.deepSelector(selector)
+					if deep || isDeep || pg.keepSelector(selector) {
+						kept = append(kept, selector)
+					}
+					keepDeep = keepDeep || isDeep
+				}
+				if len(kept) == 0 {
+					edits = append(edits, pg.removal(sheet, rule))
+					result.RemovedRules++
+					continue
+				}
+				left = true
+				if len(kept) < len(rule.Selectors) {
+					if open := strings.IndexByte(sheet.Source[rule.Start:rule.End], '{'); open >= 0 {
+						edits = append(edits, purgeEdit{rule.Start, rule.Start + open, strings.Join(kept, ", ") + " "})
+					}
+				}
+				for _, d := range rule.Declarations {
+					if strings.Contains(d.Property, "animation") || strings.HasPrefix(d.Property, "--") {
+						for _, name := range strings.FieldsFunc(d.Value, func(r rune) bool { return r == ' ' || r == ',' }) {
+							animations[name] = true
+						}
+					}
+				}
+				purgeRules(rule.Rules, keepDeep)
+			}
+		}
+		return left
+	}
+	purgeRules(sheet.Rules, false)
+
+	for _, rule := range keyframes {
+		if !animations[unquoteCSS(rule.Prelude)] {
+			edits = append(edits, pg.removal(sheet, rule))
+			result.RemovedRules++
+		}
+	}
+
+	result.CSS = applyPurgeEdits(sheet.Source, edits)
+	result.After = len(result.CSS)
+	return result
+}
+
+// removal cuts a rule out along with its whole line when nothing but
+// whitespace shares the line with it
+func (pg *Purger) removal(sheet *Stylesheet, rule *CSSRule) purgeEdit {
+	start, end := rule.Start, rule.End
+	for start > 0 && (sheet.Source[start-1] == ' ' || sheet.Source[start-1] == '\t') {
+		start--
+	}
+	if start > 0 && sheet.Source[start-1] != '\n' {
+		start = rule.Start
+	}
+	for end < len(sheet.Source) && (sheet.Source[end] == ' ' || sheet.Source[end] == '\t') {
+		end++
+	}
+	if end < len(sheet.Source) && sheet.Source[end] == '\n' {
+		end++
+	} else {
+		start, end = rule.Start, rule.End
+	}
+	return purgeEdit{start: start, end: end}
+}
+
+// applyPurgeEdits applies the edits, edits inside a removed range are
+// dropped with it
+func applyPurgeEdits(src string, edits []purgeEdit) string {
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start < edits[j].start
+		}
+		return edits[i].end > edits[j].end
+	})
+	var b strings.Builder
+	offset := 0
+	for _, e := range edits {
+		if e.start < offset {
+			continue
+		}
+		b.WriteString(src[offset:e.start])
+		b.WriteString(e.text)
+		offset = e.end
+	}
+	b.WriteString(src[offset:])
+	return b.String()
+}
+
+func (pg *Purger) deepSelector(selector string) bool {
+	for _, class := range selectorClasses(selector) {
+		if matchesAny(pg.deep, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// keepSelector reports whether a selector could match the markup: every
+// class it requires has to be used, :is() and :where() need one of their
+// alternatives and :not() requires nothing
+func (pg *Purger) keepSelector(selector string) bool {
+	if len(pg.greedy) > 0 {
+		for _, class := range selectorClasses(selector) {
+			if matchesAny(pg.greedy, class) {
+				return true
+			}
+		}
+	}
+	return pg.satisfiable(selector)
+}
+
+func (pg *Purger) satisfiable(selector string) bool {
+	for i := 0; i < len(selector); i++ {
+		switch ch := selector[i]; ch {
+		case '\\':
+			i++
+		case '"', '\'':
+			for i++; i < len(selector) && selector[i] != ch; i++ {
+				if selector[i] == '\\' {
+					i++
+				}
+			}
+		case '[':
+			for i < len(selector) && selector[i] != ']' {
+				i++
+			}
+		case '.':
+			name, n := readCSSIdent(selector[i+1:])
+			if name != "" && !pg.used(name) {
+				return false
+			}
+			i += n
+		case ':':
+			j := i + 1
+			for j < len(selector) && selector[j] == ':' {
+				j++
+			}
+			name, n := readCSSIdent(selector[j:])
+			j += n
+			if j >= len(selector) || selector[j] != '(' {
+				i = j - 1
+				continue
+			}
+			inner, end := functionArgument(selector, j)
+			switch strings.ToLower(name) {
+			case "is", "where", "matches", "any", "-webkit-any", "-moz-any", "has":
+				matched := false
+				for _, alternative := range splitSelectorList(inner) {
+					if pg.satisfiable(alternative) {
+						matched = true
+						break
+					}
+				}
+				if !matched {
+					return false
+				}
+			}
+			i = end
+		}
+	}
+	return true
+}
+
+// functionArgument returns what's between the parenthesis at open and its
+// match, and the position of the closing one
+func functionArgument(s string, open int) (string, int) {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return s[open+1 : i], i
+			}
+		}
+	}
+	return s[open+1:], len(s)
+}
+
+// WritePurgeReport prints the bytes before and after for every stylesheet
+func WritePurgeReport(w io.Writer, results []*PurgeResult) error {
+	var b strings.Builder
+	before, after := 0, 0
+	for _, r := range results {
+		before += r.Before
+		after += r.After
+		fmt.Fprintf(&b, "%-50s %9d -> %9d bytes (%s), %d rules removed\n", r.Path, r.Before, r.After, percentChange(r.Before, r.After), r.RemovedRules)
+	}
+	fmt.Fprintf(&b, "%-50s %9d -> %9d bytes (%s)\n", "total", before, after, percentChange(before, after))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func percentChange(before, after int) string {
+	if before == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%+.1f%%", float64(after-before)/float64(before)*100)
+}
@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPurge(t *testing.T) {
+	src := `@charset "utf-8";
+body { margin: 0 }
+.used { color: red }
+.dead { color: blue }
+.used, .dead:hover { animation: spin 1s linear }
+.used .dead { color: green }
+.used:not(.dead) { color: black }
+:is(.dead, .used) > a { color: pink }
+:where(.dead, .gone) p { color: gray }
+@media (min-width: 640px) {
+  .dead { display: none }
+}
+@media print {
+  .used { display: block }
+  .dead { display: none }
+}
+@keyframes spin { to { transform: rotate(360deg) } }
+@keyframes pulse { 50% { opacity: .5 } }
+.modal .title { font-weight: bold }
+.modal { & .body { padding: 0 } }
+.is-open { display: block }
+`
+	sheet, err := ParseCSS("site.css", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	purger, err := NewPurger(map[string]bool{"used": true}, PurgeOptions{Safelist: []string{"^is-"}, Deep: []string{"^modal$"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := purger.Purge(sheet)
+
+	expected := `@charset "utf-8";
+body { margin: 0 }
+.used { color: red }
+.used { animation: spin 1s linear }
+.used:not(.dead) { color: black }
+:is(.dead, .used) > a { color: pink }
+@media print {
+  .used { display: block }
+}
+@keyframes spin { to { transform: rotate(360deg) } }
+.modal .title { font-weight: bold }
+.modal { & .body { padding: 0 } }
+.is-open { display: block }
+`
+	if result.CSS != expected {
+		t.Errorf("Expected purged CSS\n%s\ngot\n%s", expected, result.CSS)
+	}
+	if result.Before != len(src) || result.After != len(expected) {
+		t.Errorf("Expected %d -> %d bytes, got %d -> %d", len(src), len(expected), result.Before, result.After)
+	}
+	if result.RemovedRules != 7 {
+		t.Errorf("Expected 7 removed rules, got %d", result.RemovedRules)
+	}
+
+	var b strings.Builder
+	WritePurgeReport(&b, []*PurgeResult{result})
+	if !strings.Contains(b.String(), "site.css") || !strings.Contains(b.String(), "7 rules removed") {
+		t.Errorf("Unexpected report %q", b.String())
+	}
+}
+
+func TestNewPurgerRejectsBadPatterns(t *testing.T) {
+	if _, err := NewPurger(nil, PurgeOptions{Deep: []string{"("}}); err == nil {
+		t.Errorf("Expected a bad pattern to fail")
+	}
+}
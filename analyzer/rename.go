@@ -0,0 +1,223 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// RenameRule maps a class to the classes replacing it. From is the class
+// name, or a regular expression matching the whole name when Regex is set,
+// To can then use its groups as $1. To can only be empty when Delete is
+// set, the rule then removes the class
+type RenameRule struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Regex  bool   `json:"regex,omitempty"`
+	Delete bool   `json:"delete,omitempty"`
+}
+
+// LoadRenameRules reads a mapping file, a JSON array of rules
+func LoadRenameRules(path string) ([]RenameRule, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []RenameRule
+	if err := json.Unmarshal(src, &rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+type renamePattern struct {
+	pattern *regexp.Regexp
+	to      string
+}
+
+// Renamer renames classes by a list of rules, exact rules win over
+// patterns and patterns are tried in order
+type Renamer struct {
+	exact    map[string]string
+	patterns []renamePattern
+}
+
+// NewRenamer compiles rules, it fails on a bad pattern
+func NewRenamer(rules []RenameRule) (*Renamer, error) {
+	r := &Renamer{exact: make(map[string]string)}
+	for _, rule := range rules {
+		if rule.From == "" {
+			return nil, fmt.Errorf("rename rule to %q has no class to rename", rule.To)
+		}
+		if empty := strings.TrimSpace(rule.To) == ""; empty != rule.Delete {
+			if empty {
+				return nil, fmt.Errorf("rename rule for %q has no classes to rename it to, set \"delete\": true to remove it", rule.From)
+			}
+			return nil, fmt.Errorf("rename rule for %q deletes the class but renames it to %q", rule.From, rule.To)
+		}
+		if !rule.Regex {
+			r.exact[rule.From] = rule.To
+			continue
+		}
+		pattern, err := regexp.Compile("^(?:" + rule.From + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("bad rename pattern %q: %w", rule.From, err)
+		}
+		r.patterns = append(r.patterns, renamePattern{pattern, rule.To})
+	}
+	return r, nil
+}
+
+// Rename returns the classes replacing class, false when no rule matches
+// it or the rule keeps it as it is
+func (r *Renamer) Rename(class string) ([]string, bool) {
+	to, ok := r.exact[class]
+	if !ok {
+		for _, p := range r.patterns {
+			if m := p.pattern.FindStringSubmatchIndex(class); m != nil {
+				to, ok = string(p.pattern.ExpandString(nil, p.to, class, m)), true
+				break
+			}
+		}
+	}
+	if !ok || to == class {
+		return nil, false
+	}
+	return strings.Fields(to), true
+}
+
+// renameClass renames a class of an attribute. The rules are tried on the
+// whole class first, then on the class without its variants and important
+// mark, which the classes replacing it then get back, so a rule for
+// btn-primary turns md:btn-primary into md:btn md:btn-brand
+func renameClass(raw string, renamer *Renamer, parser *ClassParser) ([]string, bool) {
+	if to, ok := renamer.Rename(raw); ok {
+		return to, true
+	}
+	variants, lead, base, trail := parser.splitRaw(raw)
+	if base == raw {
+		return nil, false
+	}
+	to, ok := renamer.Rename(base)
+	if !ok {
+		return nil, false
+	}
+	for i, name := range to {
+		to[i] = variants + lead + name + trail
+	}
+	return to, true
+}
+
+// Renamed is a class a rename rule rewrote, To is empty when it went
+type Renamed struct {
+	Pos  Position `json:"pos"`
+	From string   `json:"from"`
+	To   []string `json:"to"`
+}
+
+// RenameEdits returns the edits renaming the classes of file's class
+// attributes in src and what they rename
+func RenameEdits(file *SourceFile, src []byte, renamer *Renamer, parser *ClassParser) ([]Edit, []Renamed) {
+	return replaceClasses(file, src, func(class string) ([]string, bool) {
+		return renameClass(class, renamer, parser)
+	})
+}
+
+// replaceClasses returns the edits replacing the classes of file's class
+// attributes in src that replace has other classes for. Only the classes
+// change, the rest of the file and the whitespace of the attributes stay as
+// they are, but for the quotes an unquoted value gets when the new classes
+// need them. A class the attribute already has isn't added twice, classes
+// scripts look up aren't attributes and are left out
+func replaceClasses(file *SourceFile, src []byte, replace func(class string) ([]string, bool)) ([]Edit, []Renamed) {
+	var edits []Edit
+	var renamed []Renamed
+	for _, element := range file.Elements {
+		if element.Provenance == ProvenanceJS || element.ValueEnd > len(src) {
+			continue
+		}
+		type rename struct {
+			token ClassToken
+			to    []string
+		}
+		var renames []rename
+		present := make(map[string]bool)
+		for _, token := range element.Classes {
+			to, ok := replace(token.Name)
+			if !ok || strings.ContainsAny(token.Name, "{}$") {
+				present[token.Name] = true
+				continue
+			}
+			renames = append(renames, rename{token, to})
+		}
+		quote, script := attributeQuote(src, element)
+		var replaced []Edit
+		unquoted := false
+		remove := make(map[int]bool)
+		for _, r := range renames {
+			var to []string
+			for _, name := range r.to {
+				if !present[name] {
+					to = append(to, name)
+					present[name] = true
+				}
+			}
+			renamed = append(renamed, Renamed{Pos: r.token.Pos, From: r.token.Name, To: to})
+			if len(to) == 0 {
+				remove[r.token.Pos.Offset] = true
+				continue
+			}
+			text := escapeClasses(strings.Join(to, " "), quote, script)
+			// an unquoted value ends at the first space
+			unquoted = unquoted || quote == 0 && strings.ContainsAny(text, " =`")
+			replaced = append(replaced, Edit{Start: r.token.Pos.Offset, End: r.token.End, Text: text})
+		}
+		if unquoted {
+			edits = append(edits, Edit{Start: element.ValueStart, End: element.ValueStart, Text: `"`})
+		}
+		edits = append(edits, replaced...)
+		edits = append(edits, removeTokens(src, element, remove)...)
+		if unquoted {
+			edits = append(edits, Edit{Start: element.ValueEnd, End: element.ValueEnd, Text: `"`})
+		}
+	}
+	return edits, renamed
+}
+
+// attributeQuote returns the quote around element's class attribute, 0
+// when the value isn't quoted, and whether the value is a script string as
+// in className={'a b'}
+func attributeQuote(src []byte, element *Element) (quote byte, script bool) {
+	if element.ValueStart == 0 {
+		return 0, false
+	}
+	switch quote = src[element.ValueStart-1]; quote {
+	case '"', '\'', '`':
+	default:
+		return 0, false
+	}
+	i := element.ValueStart - 2
+	for i >= 0 && isHTMLSpace(src[i]) {
+		i--
+	}
+	return quote, i >= 0 && src[i] == '{'
+}
+
+// escapeClasses escapes the classes text to go in an attribute value
+// quoted by quote. Markup attributes get character references for '&',
+// '<', '>' and the quotes, script strings a backslash before their quote
+// and backslashes
+func escapeClasses(text string, quote byte, script bool) string {
+	if !script {
+		return html.EscapeString(text)
+	}
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, string(quote), `\`+string(quote))
+	if quote == '`' {
+		text = strings.ReplaceAll(text, "${", `\${`)
+	}
+	return text
+}
@@ -0,0 +1,190 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRenamer(t *testing.T) {
+	renamer, err := NewRenamer([]RenameRule{
+		{From: "btn-primary", To: "btn btn-brand"},
+		{From: `text-gray-(\d+)`, To: "text-slate-$1", Regex: true},
+		{From: `text-gray-900`, To: "text-black"},
+		{From: "clearfix", Delete: true},
+		{From: "same", To: "same"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]string{
+		"btn-primary":      {"btn", "btn-brand"},
+		"text-gray-500":    {"text-slate-500"},
+		"text-gray-900":    {"text-black"},
+		"my-text-gray-500": nil,
+		"clearfix":         {},
+		"same":             nil,
+	}
+	for class, expected := range tests {
+		got, ok := renamer.Rename(class)
+		if ok != (expected != nil) || !slices.Equal(got, expected) {
+			t.Errorf("%s: expected %v, got %v and %t", class, expected, got, ok)
+		}
+	}
+	if _, err := NewRenamer([]RenameRule{{From: "(", Regex: true}}); err == nil {
+		t.Errorf("Expected a bad pattern to fail")
+	}
+	if _, err := NewRenamer([]RenameRule{{From: "clearfix"}}); err == nil {
+		t.Errorf("Expected a rule without classes to rename to and without delete to fail")
+	}
+	if _, err := NewRenamer([]RenameRule{{From: "clearfix", To: "flow-root", Delete: true}}); err == nil {
+		t.Errorf("Expected a rule that both renames and deletes to fail")
+	}
+}
+
+func TestRenameEdits(t *testing.T) {
+	page := `<a class="btn btn-primary md:!btn-primary">Go</a>
+<p class="text-gray-500
+   clearfix">text-gray-500 btn-primary</p>
+<div class="{{ cls }} text-gray-700"></div>
+<script>el.classList.add("btn-primary")</script>
+`
+	dir := writeProject(t, map[string]string{"index.html": page})
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renamer, err := NewRenamer([]RenameRule{
+		{From: "btn-primary", To: "btn btn-brand"},
+		{From: `text-gray-(\d+)`, To: "text-slate-$1", Regex: true},
+		{From: "clearfix", Delete: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edits, renamed := RenameEdits(project.Files[0], []byte(page), renamer, DefaultParser)
+	expected := []string{
+		"index.html:1:15 btn-primary -> btn-brand",
+		"index.html:1:27 md:!btn-primary -> md:!btn md:!btn-brand",
+		"index.html:2:11 text-gray-500 -> text-slate-500",
+		"index.html:3:4 clearfix ->",
+		"index.html:4:23 text-gray-700 -> text-slate-700",
+	}
+	var got []string
+	for _, r := range renamed {
+		got = append(got, strings.TrimSpace(r.Pos.String()+" "+r.From+" -> "+strings.Join(r.To, " ")))
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if expected[i] != got[i] {
+			t.Errorf("Expected %q, got %q", expected[i], got[i])
+		}
+	}
+
+	fixed, err := ApplyEdits([]byte(page), edits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<a class="btn btn-brand md:!btn md:!btn-brand">Go</a>
+<p class="text-slate-500">text-gray-500 btn-primary</p>
+<div class="{{ cls }} text-slate-700"></div>
+<script>el.classList.add("btn-primary")</script>
+`
+	if string(fixed) != want {
+		t.Errorf("Expected the renamed page to be\n%s\ngot\n%s", want, fixed)
+	}
+
+	var diff strings.Builder
+	if err := WriteDiff(&diff, "index.html", []byte(page), edits); err != nil {
+		t.Fatal(err)
+	}
+	wantDiff := `--- a/index.html
++++ b/index.html
+@@ -1,1 +1,1 @@
+-<a class="btn btn-primary md:!btn-primary">Go</a>
++<a class="btn btn-brand md:!btn md:!btn-brand">Go</a>
+@@ -2,2 +2,1 @@
+-<p class="text-gray-500
+-   clearfix">text-gray-500 btn-primary</p>
++<p class="text-slate-500">text-gray-500 btn-primary</p>
+@@ -4,1 +3,1 @@
+-<div class="{{ cls }} text-gray-700"></div>
++<div class="{{ cls }} text-slate-700"></div>
+`
+	if diff.String() != wantDiff {
+		t.Errorf("Expected the diff\n%s\ngot\n%s", wantDiff, diff.String())
+	}
+}
+
+func TestRenameEscapes(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<a class="x btn">Go</a>
+<b class='btn'></b>
+<i class=btn></i>
+`,
+		"app.jsx": "const a = <a className=\"btn\" />, b = <b className={'btn'} />, c = <i className={`btn`} />\n",
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renamer, err := NewRenamer([]RenameRule{{From: "btn", To: `a"b c&d'e<f\g${h}`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"index.html": `<a class="x a&#34;b c&amp;d&#39;e&lt;f\g${h}">Go</a>
+<b class='a&#34;b c&amp;d&#39;e&lt;f\g${h}'></b>
+<i class="a&#34;b c&amp;d&#39;e&lt;f\g${h}"></i>
+`,
+		"app.jsx": "const a = <a className=\"a&#34;b c&amp;d&#39;e&lt;f\\g${h}\" />, b = <b className={'a\"b c&d\\'e<f\\\\g${h}'} />, c = <i className={`a\"b c&d'e<f\\\\g\\${h}`} />\n",
+	}
+	for _, file := range project.Files {
+		src := []byte(files[file.Path])
+		edits, _ := RenameEdits(file, src, renamer, DefaultParser)
+		renamed, err := ApplyEdits(src, edits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(renamed) != expected[file.Path] {
+			t.Errorf("Expected %s to be\n%s\ngot\n%s", file.Path, expected[file.Path], renamed)
+		}
+	}
+}
+
+func TestRenameTemplates(t *testing.T) {
+	files := map[string]string{
+		"Card.vue":   `<template><div class="btn-primary p-4" :class="{ on }">{{ a > b }}</div></template>`,
+		"button.hbs": `<a class="btn-primary btn-{{size}}" title="{{t "a>b"}}">Go</a>`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renamer, err := NewRenamer([]RenameRule{{From: "btn-primary", To: "btn btn-brand"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Card.vue":   `<template><div class="btn btn-brand p-4" :class="{ on }">{{ a > b }}</div></template>`,
+		"button.hbs": `<a class="btn btn-brand btn-{{size}}" title="{{t "a>b"}}">Go</a>`,
+	}
+	for _, file := range project.Files {
+		src := []byte(files[file.Path])
+		edits, _ := RenameEdits(file, src, renamer, DefaultParser)
+		renamed, err := ApplyEdits(src, edits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(renamed) != expected[file.Path] {
+			t.Errorf("Expected %s to be\n%s\ngot\n%s", file.Path, expected[file.Path], renamed)
+		}
+	}
+}
@@ -0,0 +1,482 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+// Position is a place in a source file, lines and columns are 1-based and
+// columns count bytes, offsets are 0-based bytes from the start of the file
+type Position struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Offset int    `json:"offset"`
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// ClassToken is a single class name inside a class attribute
+type ClassToken struct {
+	Name string   `json:"name"`
+	Pos  Position `json:"pos"`
+	End  int      `json:"end"` // byte offset right after the token
+}
+
+// Element is an element (or component) carrying a class attribute,
+// ValueStart and ValueEnd delimit the raw attribute value in the source.
+// Class references in script are elements too, with the call as Tag, the
+// string literal as value and ProvenanceJS as Provenance
+type Element struct {
+	Tag        string       `json:"tag"`
+	Pos        Position     `json:"pos"`
+	ValueStart int          `json:"valueStart"`
+	ValueEnd   int          `json:"valueEnd"`
+	Classes    []ClassToken `json:"classes"`
+	Provenance string       `json:"provenance,omitempty"`
+}
+
+// SourceFile holds everything the extractors found in one file
+type SourceFile struct {
+	Path       string            `json:"path"` // relative to the project root
+	Elements   []*Element        `json:"elements"`
+	ModuleRefs []ModuleReference `json:"moduleRefs,omitempty"`
+	StyleAttrs []*StyleAttr      `json:"styleAttrs,omitempty"`
+	// ScriptWords are the words of the file's script string literals, the
+	// classes scripts may hand to the DOM through a variable, sorted
+	ScriptWords []string `json:"scriptWords,omitempty"`
+	// Stylesheet holds the rules of the file's <style> blocks, Scan adds it
+	// to the project's stylesheets
+	Stylesheet *Stylesheet `json:"-"`
+}
+
+// Project is the result of scanning a directory, files and stylesheets are
+// sorted by path
+type Project struct {
+	Root        string        `json:"root"`
+	Files       []*SourceFile `json:"files"`
+	Stylesheets []*Stylesheet `json:"stylesheets"`
+}
+
+// Extractor pulls class usages out of the contents of a single file
+type Extractor func(path string, src []byte) (*SourceFile, error)
+
+// extractors maps a file extension to the extractor for that kind of file,
+// this is the place to plug in support for other templating languages
+var extractors = map[string]Extractor{
+	".html": extractHTML,
+	".htm":  extractHTML,
+	".js":   extractScript,
+	".jsx":  extractScript,
+	".mjs":  extractScript,
+	".cjs":  extractScript,
+	".ts":   extractScript,
+	".tsx":  extractScript,
+}
+
+// RegisterExtractor makes Scan hand files with the given extension to fn,
+// it's meant to be called from init functions
+func RegisterExtractor(ext string, fn Extractor) {
+	extractors[ext] = fn
+}
+
+// ScanOptions narrows down the markup Scan looks at, Include and Exclude
+// are globs relative to the root like the content globs of tailwind.config.js,
+// stylesheets are always read since they define classes rather than use them
+type ScanOptions struct {
+	Include []string
+	Exclude []string
+	// Roots are extra directories to walk, relative to the root, like the
+	// ones a v4 @source pulls in from outside the project or node_modules
+	Roots []string
+}
+
+func (o ScanOptions) wants(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, glob := range o.Exclude {
+		if MatchGlob(glob, rel) {
+			return false
+		}
+	}
+	if len(o.Include) == 0 {
+		return true
+	}
+	for _, glob := range o.Include {
+		if MatchGlob(glob, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan walks dir and its children and runs the matching extractor or
+// stylesheet parser on every file it knows about, unlike htmlFiles it keeps
+// every single usage around
+func Scan(dir string) (*Project, error) {
+	return ScanWith(dir, ScanOptions{})
+}
+
+// ScanWith is Scan limited to the markup files opts wants
+func ScanWith(dir string, opts ScanOptions) (*Project, error) {
+	project := &Project{Root: dir}
+
+	walkDirWg := sync.WaitGroup{}
+	fileStoreWg := sync.WaitGroup{}
+	fileChan := make(chan *SourceFile, 100)
+	sheetChan := make(chan *Stylesheet, 100)
+
+	fileStoreWg.Add(2)
+	go func() {
+		defer fileStoreWg.Done()
+		for file := range fileChan {
+			project.Files = append(project.Files, file)
+		}
+	}()
+	go func() {
+		defer fileStoreWg.Done()
+		for sheet := range sheetChan {
+			project.Stylesheets = append(project.Stylesheets, sheet)
+		}
+	}()
+
+	walkRoot := dir
+	walk := func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			log.Printf("prevent panic by handling failure accessing a path %q: %v\n", path, err)
+			return err
+		}
+		if d.IsDir() {
+			if path != walkRoot && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(d.Name()))
+		parse, isStylesheet := styleParsers[ext]
+		extract, isMarkup := extractors[ext]
+		if !isStylesheet && !isMarkup {
+			return nil
+		}
+		if !isStylesheet && !opts.wants(relPath(dir, path)) {
+			return nil
+		}
+		walkDirWg.Add(1)
+		go func(path string) {
+			defer walkDirWg.Done()
+			src, err := os.ReadFile(path)
+			if err != nil {
+				log.Printf("error reading file %q: %v\n", path, err)
+				return
+			}
+			if isStylesheet {
+				sheet, err := parse(relPath(dir, path), src)
+				if err != nil {
+					log.Printf("error parsing stylesheet %q: %v\n", path, err)
+					return
+				}
+				sheetChan <- sheet
+				return
+			}
+			file, err := extract(relPath(dir, path), src)
+			if err != nil {
+				log.Printf("error getting class names from file %q: %v\n", path, err)
+				return
+			}
+			fileChan <- file
+		}(path)
+		return nil
+	}
+	err := filepath.WalkDir(dir, walk)
+	for _, root := range opts.Roots {
+		if err != nil {
+			break
+		}
+		walkRoot = filepath.Join(dir, root)
+		err = filepath.WalkDir(walkRoot, walk)
+	}
+	walkDirWg.Wait()
+	close(fileChan)
+	close(sheetChan)
+	fileStoreWg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range project.Files {
+		if file.Stylesheet != nil {
+			project.Stylesheets = append(project.Stylesheets, file.Stylesheet)
+		}
+	}
+
+	sort.Slice(project.Files, func(i, j int) bool {
+		return project.Files[i].Path < project.Files[j].Path
+	})
+	sort.Slice(project.Stylesheets, func(i, j int) bool {
+		return project.Stylesheets[i].Path < project.Stylesheets[j].Path
+	})
+	return project, nil
+}
+
+// dependency and VCS directories never contain the project's own markup
+func skipDir(name string) bool {
+	return name == "node_modules" || (strings.HasPrefix(name, ".") && name != ".")
+}
+
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	if rel == "." {
+		// root is the file itself
+		return filepath.Base(path)
+	}
+	return rel
+}
+
+// AbsPath returns the on-disk path of a file reported by the project
+func (p *Project) AbsPath(file string) string {
+	if info, err := os.Stat(p.Root); err == nil && !info.IsDir() {
+		// the project is that single file
+		return p.Root
+	}
+	return filepath.Join(p.Root, file)
+}
+
+// ClassNames returns the sorted set of class names used in the project
+func (p *Project) ClassNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			for _, class := range element.Classes {
+				if !seen[class.Name] {
+					seen[class.Name] = true
+					names = append(names, class.Name)
+				}
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// DefinedClasses returns the set of classes the project's stylesheets define
+// for markup to use
+func (p *Project) DefinedClasses() map[string]bool {
+	defined := make(map[string]bool)
+	for _, sheet := range p.Stylesheets {
+		for _, definition := range sheet.Definitions() {
+			if sheet.IsModule() {
+				// only :global classes of a CSS module can be used in markup
+				_, global := moduleClasses(definition.Selector)
+				if !slices.Contains(global, definition.Class) {
+					continue
+				}
+			}
+			defined[definition.Class] = true
+		}
+	}
+	return defined
+}
+
+// lineIndex holds the byte offset each line starts at
+type lineIndex []int
+
+func newLineIndex(src []byte) lineIndex {
+	lines := lineIndex{0}
+	for i, b := range src {
+		if b == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+func (l lineIndex) position(file string, offset int) Position {
+	line := sort.Search(len(l), func(i int) bool { return l[i] > offset }) - 1
+	return Position{File: file, Line: line + 1, Column: offset - l[line] + 1, Offset: offset}
+}
+
+// extractHTML tokenizes instead of building a tree so that every class keeps
+// its byte offset in the original source, <style> blocks become the file's
+// Stylesheet, style attributes are kept in StyleAttrs and <script> blocks
+// are read like script files
+func extractHTML(path string, src []byte) (*SourceFile, error) {
+	file := &SourceFile{Path: path}
+	lines := newLineIndex(src)
+	z := html.NewTokenizer(bytes.NewReader(src))
+	offset := 0
+	inStyle, inScript := false, false
+	var styleBlocks, scriptBlocks [][2]int
+	for {
+		tt := z.Next()
+		start := offset
+		offset += len(z.Raw())
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() != io.EOF {
+				return nil, z.Err()
+			}
+			if len(styleBlocks) > 0 {
+				// a broken <style> block only costs the page its stylesheet,
+				// its markup still counts
+				if sheet, err := ParseCSS(path, keepBlocks(src, styleBlocks)); err != nil {
+					log.Printf("error parsing the style blocks of %q: %v\n", path, err)
+				} else {
+					sheet.Source = string(src)
+					file.Stylesheet = sheet
+				}
+			}
+			if len(scriptBlocks) > 0 {
+				scanScript(file, string(keepBlocks(src, scriptBlocks)), lines)
+			}
+			return file, nil
+		case html.TextToken:
+			if inStyle {
+				styleBlocks = append(styleBlocks, [2]int{start, offset})
+			} else if inScript {
+				scriptBlocks = append(scriptBlocks, [2]int{start, offset})
+			}
+		case html.EndTagToken:
+			inStyle, inScript = false, false
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tag, attrs := scanTag(src[start:offset])
+			inStyle = tag == "style" && tt == html.StartTagToken
+			inScript = tag == "script" && tt == html.StartTagToken && isJavaScript(src[start:offset], attrs)
+			var element *Element
+			var style *StyleAttr
+			for _, a := range attrs {
+				switch {
+				case a.key == "class" && element == nil:
+					// like the parser, only the first class attribute counts
+					element = &Element{
+						Tag:        tag,
+						Pos:        lines.position(path, start),
+						ValueStart: start + a.start,
+						ValueEnd:   start + a.end,
+					}
+					element.Classes = splitClasses(src, element.ValueStart, element.ValueEnd, lines, path)
+					file.Elements = append(file.Elements, element)
+				case a.key == "style" && style == nil:
+					style = &StyleAttr{
+						Tag:        tag,
+						Pos:        lines.position(path, start),
+						ValueStart: start + a.start,
+						ValueEnd:   start + a.end,
+					}
+					style.Declarations = parseStyleAttr(src, style.ValueStart, style.ValueEnd, lines, path)
+					file.StyleAttrs = append(file.StyleAttrs, style)
+				}
+			}
+			if style != nil && element != nil {
+				for _, class := range element.Classes {
+					style.Classes = append(style.Classes, class.Name)
+				}
+			}
+		}
+	}
+}
+
+// rawAttr is an attribute of a raw start tag, start and end delimit the
+// value relative to the start of the tag
+type rawAttr struct {
+	key        string
+	start, end int
+}
+
+// scanTag reads the tag name and attributes of a raw start tag the same way
+// the html tokenizer does, but keeps track of where each value sits
+func scanTag(raw []byte) (tag string, attrs []rawAttr) {
+	i := 1
+	for i < len(raw) && !isHTMLSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' {
+		i++
+	}
+	tag = strings.ToLower(string(raw[1:i]))
+	for i < len(raw) {
+		for i < len(raw) && (isHTMLSpace(raw[i]) || raw[i] == '/') {
+			i++
+		}
+		if i >= len(raw) || raw[i] == '>' {
+			break
+		}
+		// a leading '=' is part of the attribute name
+		keyStart := i
+		i++
+		for i < len(raw) && !isHTMLSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' && raw[i] != '=' {
+			i++
+		}
+		a := rawAttr{key: strings.ToLower(string(raw[keyStart:i])), start: i, end: i}
+		j := i
+		for j < len(raw) && isHTMLSpace(raw[j]) {
+			j++
+		}
+		if j < len(raw) && raw[j] == '=' {
+			j++
+			for j < len(raw) && isHTMLSpace(raw[j]) {
+				j++
+			}
+			if j < len(raw) && (raw[j] == '"' || raw[j] == '\'') {
+				quote := raw[j]
+				j++
+				a.start = j
+				for j < len(raw) && raw[j] != quote {
+					j++
+				}
+				a.end = j
+				if j < len(raw) {
+					j++
+				}
+			} else {
+				a.start = j
+				for j < len(raw) && !isHTMLSpace(raw[j]) && raw[j] != '>' {
+					j++
+				}
+				a.end = j
+			}
+			i = j
+		}
+		attrs = append(attrs, a)
+	}
+	return tag, attrs
+}
+
+func isHTMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\f' || b == '\r'
+}
+
+// splitClasses splits the attribute value src[start:end] on whitespace,
+// decoding character references in the names but not in the offsets
+func splitClasses(src []byte, start, end int, lines lineIndex, path string) []ClassToken {
+	var classes []ClassToken
+	i := start
+	for i < end {
+		for i < end && isHTMLSpace(src[i]) {
+			i++
+		}
+		tokenStart := i
+		for i < end && !isHTMLSpace(src[i]) {
+			i++
+		}
+		if tokenStart == i {
+			break
+		}
+		name := string(src[tokenStart:i])
+		if strings.Contains(name, "&") {
+			name = html.UnescapeString(name)
+		}
+		classes = append(classes, ClassToken{Name: name, Pos: lines.position(path, tokenStart), End: i})
+	}
+	return classes
+}
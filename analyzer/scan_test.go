@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanPositions(t *testing.T) {
+	sampleHTML := "<div class=\"flex  p-2\">\n  <span id=x class='text-sm p-2'>hi</span>\n  <p class=\"a&amp;b\" class=\"ignored\"></p>\n</div>"
+
+	tempDir, err := os.MkdirTemp("", "testScan")
+	if err != nil {
+		t.Fatalf("failed to create temp directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	err = os.WriteFile(filepath.Join(tempDir, "sample.html"), []byte(sampleHTML), 0644)
+	if err != nil {
+		t.Fatalf("failed to write sample HTML file: %s", err)
+	}
+	// files nobody registered an extractor for are skipped
+	err = os.WriteFile(filepath.Join(tempDir, "notes.txt"), []byte(`class="nope"`), 0644)
+	if err != nil {
+		t.Fatalf("failed to write sample text file: %s", err)
+	}
+
+	project, err := Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %s", err)
+	}
+	if len(project.Files) != 1 {
+		t.Fatalf("Expected 1 file, got %d", len(project.Files))
+	}
+	elements := project.Files[0].Elements
+	if len(elements) != 3 {
+		t.Fatalf("Expected 3 elements, got %d", len(elements))
+	}
+
+	expected := []struct {
+		name         string
+		line, column int
+	}{
+		{"flex", 1, 13}, {"p-2", 1, 19}, {"text-sm", 2, 21}, {"p-2", 2, 29}, {"a&b", 3, 13},
+	}
+	var received []ClassToken
+	for _, element := range elements {
+		received = append(received, element.Classes...)
+	}
+	if len(received) != len(expected) {
+		t.Fatalf("Expected %d classes, got %d", len(expected), len(received))
+	}
+	for i, e := range expected {
+		token := received[i]
+		if token.Name != e.name || token.Pos.Line != e.line || token.Pos.Column != e.column {
+			t.Errorf("Expected %s at %d:%d, got %s at %d:%d", e.name, e.line, e.column, token.Name, token.Pos.Line, token.Pos.Column)
+		}
+		if e.name != "a&b" && sampleHTML[token.Pos.Offset:token.End] != e.name {
+			t.Errorf("Expected offsets to point at %s, got %q", e.name, sampleHTML[token.Pos.Offset:token.End])
+		}
+	}
+	if elements[1].Tag != "span" || sampleHTML[elements[1].ValueStart:elements[1].ValueEnd] != "text-sm p-2" {
+		t.Errorf("Unexpected element %s with value %q", elements[1].Tag, sampleHTML[elements[1].ValueStart:elements[1].ValueEnd])
+	}
+}
+
+func TestScanMatchesHtmlFiles(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current working directory: %s", err)
+	}
+	outputLogPath := filepath.Join(t.TempDir(), "classes.log")
+	err = htmlFiles(filepath.Join(cwd, "example-pages"), outputLogPath)
+	if err != nil {
+		t.Fatalf("htmlFiles failed: %s", err)
+	}
+	project, err := Scan(filepath.Join(cwd, "example-pages"))
+	if err != nil {
+		t.Fatalf("Scan failed: %s", err)
+	}
+
+	logged, err := os.ReadFile(outputLogPath)
+	if err != nil {
+		t.Fatalf("failed to read output log file: %s", err)
+	}
+	var scanned string
+	for _, className := range project.ClassNames() {
+		scanned += className + "\n"
+	}
+	if scanned != string(logged) {
+		t.Errorf("Expected Scan to find the same classes as htmlFiles")
+	}
+}
+
+func TestScanKeepsPagesWithBrokenStyles(t *testing.T) {
+	dir := writeProject(t, map[string]string{"index.html": `<style>.a::after{content:"x}</style><div class="foo bar"></div>`})
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Files) != 1 || len(project.Files[0].Elements) != 1 || len(project.Files[0].Elements[0].Classes) != 2 {
+		t.Fatalf("Expected the page and its classes despite the broken style block, got %+v", project.Files)
+	}
+	if project.Files[0].Stylesheet != nil {
+		t.Errorf("Expected no stylesheet for the broken style block, got %+v", project.Files[0].Stylesheet)
+	}
+}
@@ -0,0 +1,189 @@
+package analyzer
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ModuleReference is a class of a CSS module looked up from script, like
+// styles.primary or styles['is-active'] after importing the module as
+// styles. Module is the module's path relative to the project root when
+// the import is relative and the import as written otherwise, Dynamic
+// lookups like styles[variant] have no Key
+type ModuleReference struct {
+	Module  string   `json:"module"`
+	Key     string   `json:"key,omitempty"`
+	Dynamic bool     `json:"dynamic,omitempty"`
+	Pos     Position `json:"pos"`
+}
+
+const moduleSpecifier = `["']([^"']+\.module\.(?:css|scss|less))["']`
+
+var (
+	// import styles from './Button.module.css', import * as styles from ...
+	moduleImportPattern = regexp.MustCompile(`import\s+(?:\*\s*as\s+)?([A-Za-z_$][\w$]*)\s+from\s*` + moduleSpecifier)
+	// import { primary, active as isActive } from './Button.module.css'
+	moduleNamedImportPattern = regexp.MustCompile(`import\s*\{([^}]*)\}\s*from\s*` + moduleSpecifier)
+	// const styles = require('./Button.module.css')
+	moduleRequirePattern = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*require\(\s*` + moduleSpecifier + `\s*\)`)
+	identifierPattern    = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+)
+
+// extractScript finds the class references of a JavaScript or TypeScript
+// file, it reads the source with regular expressions rather than parsing it
+func extractScript(file string, src []byte) (*SourceFile, error) {
+	result := &SourceFile{Path: file}
+	scanScript(result, string(src), newLineIndex(src))
+	return result, nil
+}
+
+// scanScript adds the CSS module lookups, the JSX class attributes and the
+// DOM class references of text to file, text is the whole file or a copy
+// of it with everything but the scripts blanked out
+func scanScript(file *SourceFile, text string, lines lineIndex) {
+	for _, m := range moduleNamedImportPattern.FindAllStringSubmatchIndex(text, -1) {
+		module := resolveModule(file.Path, text[m[4]:m[5]])
+		for _, name := range identifierNames(text, m[2], m[3]) {
+			file.ModuleRefs = append(file.ModuleRefs, ModuleReference{Module: module, Key: text[name[0]:name[1]], Pos: lines.position(file.Path, name[0])})
+		}
+	}
+	for _, pattern := range []*regexp.Regexp{moduleImportPattern, moduleRequirePattern} {
+		for _, m := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			module := resolveModule(file.Path, text[m[4]:m[5]])
+			file.ModuleRefs = append(file.ModuleRefs, moduleLookups(text, text[m[2]:m[3]], module, file.Path, lines)...)
+		}
+	}
+	jsx := jsxAttributes(text, file.Path, lines)
+	file.Elements = append(file.Elements, jsx...)
+	file.ScriptWords = append(file.ScriptWords, scriptWords(text, jsx)...)
+	slices.Sort(file.ScriptWords)
+	file.ScriptWords = slices.Compact(file.ScriptWords)
+	file.Elements = append(file.Elements, domReferences(text, file.Path, lines)...)
+	sort.SliceStable(file.Elements, func(i, j int) bool {
+		return file.Elements[i].Pos.Offset < file.Elements[j].Pos.Offset
+	})
+}
+
+// scriptWords returns the words of the string literals of text and the
+// classes of the selectors among them, so 'is-open' and ".menu > .item"
+// both count whether a DOM call takes them directly or not. The class
+// attributes of the jsx elements are markup rather than strings and left
+// out
+func scriptWords(text string, jsx []*Element) []string {
+	if len(jsx) > 0 {
+		blanked := []byte(text)
+		for _, element := range jsx {
+			for i := element.ValueStart; i < element.ValueEnd; i++ {
+				blanked[i] = ' '
+			}
+		}
+		text = string(blanked)
+	}
+	var words []string
+	add := func(value string) {
+		for _, field := range strings.Fields(value) {
+			words = append(words, field)
+			for _, span := range selectorClassSpans(field) {
+				words = append(words, span.name)
+			}
+		}
+	}
+	for i := 0; i < len(text); i++ {
+		switch {
+		case strings.HasPrefix(text[i:], "//"):
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return words
+			}
+			i += end + 3
+		case text[i] == '`':
+			var value strings.Builder
+			for i++; i < len(text) && text[i] != '`'; i++ {
+				switch {
+				case text[i] == '\\' && i+1 < len(text):
+					i++
+					value.WriteByte(text[i])
+				case strings.HasPrefix(text[i:], "${"):
+					// a substitution is script again, with strings of its own
+					depth, start := 1, i+2
+					for i = start; i < len(text) && depth > 0; i++ {
+						switch text[i] {
+						case '{':
+							depth++
+						case '}':
+							depth--
+						}
+					}
+					end := i
+					if depth == 0 {
+						end--
+					}
+					words = append(words, scriptWords(text[start:end], nil)...)
+					value.WriteByte(' ')
+					i--
+				default:
+					value.WriteByte(text[i])
+				}
+			}
+			add(value.String())
+		case text[i] == '"' || text[i] == '\'':
+			if literal, next, ok := readStringLiteral(text, i); ok {
+				add(literal.value)
+				i = next - 1
+			}
+		}
+	}
+	return words
+}
+
+// identifierNames returns where the imported names of "{ a, b as c }" are,
+// the name being imported rather than the local alias
+func identifierNames(text string, start, end int) [][2]int {
+	var names [][2]int
+	offset := start
+	for _, specifier := range strings.Split(text[start:end], ",") {
+		if loc := identifierPattern.FindStringIndex(specifier); loc != nil && specifier[loc[0]:loc[1]] != "default" {
+			names = append(names, [2]int{offset + loc[0], offset + loc[1]})
+		}
+		offset += len(specifier) + 1
+	}
+	return names
+}
+
+// moduleLookups finds the member accesses on the binding a module was
+// imported as
+func moduleLookups(text, binding, module, file string, lines lineIndex) []ModuleReference {
+	// a binding right after a quote or a slash is part of a path, like the
+	// styles in "./styles.module.css"
+	lookup := regexp.MustCompile(`(?:^|[^\w$./'"@-])` + regexp.QuoteMeta(binding) +
+		`\s*(?:\??\.\s*([A-Za-z_$][\w$]*)|\[\s*(?:"([^"]*)"|'([^']*)'|` + "`([^`$]*)`" + `)\s*\]|(\[))`)
+	var refs []ModuleReference
+	for _, m := range lookup.FindAllStringSubmatchIndex(text, -1) {
+		for group := 1; group <= 4; group++ {
+			if start := m[2*group]; start >= 0 {
+				refs = append(refs, ModuleReference{Module: module, Key: text[start:m[2*group+1]], Pos: lines.position(file, start)})
+			}
+		}
+		if start := m[10]; start >= 0 {
+			refs = append(refs, ModuleReference{Module: module, Dynamic: true, Pos: lines.position(file, start)})
+		}
+	}
+	return refs
+}
+
+// resolveModule makes a relative import relative to the project root,
+// aliases like "@/styles/x.module.css" are left for the project to match
+func resolveModule(file, specifier string) string {
+	if !strings.HasPrefix(specifier, "./") && !strings.HasPrefix(specifier, "../") {
+		return specifier
+	}
+	return filepath.FromSlash(path.Join(path.Dir(filepath.ToSlash(file)), specifier))
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractScriptModuleRefs(t *testing.T) {
+	src := "import styles from './Button.module.css'\n" +
+		"import { wide, tall as high } from \"../shared/layout.module.scss\"\n" +
+		"const theme = require('@/styles/theme.module.css')\n" +
+		"export function Button({ variant }) {\n" +
+		"  return <button className={`${styles.primary} ${styles['is-active']} ${styles?.big}`}>\n" +
+		"    <span className={styles[variant]} data-x={mystyles.nope} />\n" +
+		"    <i className={theme.icon} />\n" +
+		"  </button>\n" +
+		"}\n"
+	file, err := extractScript(filepath.Join("components", "Button.jsx"), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	button := filepath.Join("components", "Button.module.css")
+	layout := filepath.Join("shared", "layout.module.scss")
+	expected := []struct {
+		module, key string
+		dynamic     bool
+		line, col   int
+	}{
+		{layout, "wide", false, 2, 10},
+		{layout, "tall", false, 2, 16},
+		{button, "primary", false, 5, 39},
+		{button, "is-active", false, 5, 58},
+		{button, "big", false, 5, 81},
+		{button, "", true, 6, 28},
+		{"@/styles/theme.module.css", "icon", false, 7, 25},
+	}
+	if len(file.ModuleRefs) != len(expected) {
+		t.Fatalf("Expected %d module refs, got %+v", len(expected), file.ModuleRefs)
+	}
+	for i, e := range expected {
+		ref := file.ModuleRefs[i]
+		if ref.Module != e.module || ref.Key != e.key || ref.Dynamic != e.dynamic || ref.Pos.Line != e.line || ref.Pos.Column != e.col {
+			t.Errorf("Expected %+v, got %+v", e, ref)
+		}
+	}
+}
+
+func TestExtractJSXClassNames(t *testing.T) {
+	src := "export const Card = () => (\n" +
+		"  <div className=\"p-2 flex\">\n" +
+		"    <span className={'text-sm'} />\n" +
+		"    <b className={`font-bold\n      italic`} />\n" +
+		"    <i className={`icon ${size}`} class='x' />\n" +
+		"  </div>\n" +
+		")\n" +
+		"el.className = 'not-an-attribute'\n"
+	file, err := extractScript("Card.jsx", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		tag     string
+		classes string
+	}{{"div", "p-2 flex"}, {"span", "text-sm"}, {"b", "font-bold italic"}, {"i", "x"}}
+	var elements []*Element
+	for _, element := range file.Elements {
+		if element.Provenance == "" {
+			elements = append(elements, element)
+		}
+	}
+	if len(elements) != len(expected) {
+		t.Fatalf("Expected %d JSX elements, got %+v", len(expected), elements)
+	}
+	for i, e := range expected {
+		var names []string
+		for _, token := range elements[i].Classes {
+			names = append(names, token.Name)
+		}
+		if got := strings.Join(names, " "); elements[i].Tag != e.tag || got != e.classes {
+			t.Errorf("Expected <%s> with %q, got <%s> with %q", e.tag, e.classes, elements[i].Tag, got)
+		}
+	}
+	if pos := elements[2].Classes[1].Pos; pos.Line != 5 || pos.Column != 7 {
+		t.Errorf("Expected italic at 5:7, got %d:%d", pos.Line, pos.Column)
+	}
+}
+
+func TestScriptWords(t *testing.T) {
+	src := `// 'commented'
+const cls = isOpen ? 'open-state' : "closed";
+/* "also commented" */
+const item = ` + "`.menu > .item-${size > 2 ? 'large' : 'small'}`" + `;
+return <div className="jsx-class">{label}</div>;`
+	jsx := jsxAttributes(src, "app.jsx", newLineIndex([]byte(src)))
+	words := scriptWords(src, jsx)
+	slices.Sort(words)
+	words = slices.Compact(words)
+	expected := []string{".item-", ".menu", ">", "closed", "item-", "large", "menu", "open-state", "small"}
+	if !slices.Equal(words, expected) {
+		t.Errorf("Expected %v, got %v", expected, words)
+	}
+}
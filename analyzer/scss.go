@@ -0,0 +1,264 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Extension is an @extend (or a Less :extend) found in a preprocessor
+// stylesheet, whatever Selector matches gets the styles of Target's rules
+type Extension struct {
+	Selector string   `json:"selector"`
+	Target   string   `json:"target"`
+	Pos      Position `json:"pos"`
+}
+
+var (
+	cssCommentPattern = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	// lessMixinPattern matches the selector of a Less mixin definition,
+	// ".bordered(@width: 2px)", which outputs nothing by itself
+	lessMixinPattern = regexp.MustCompile(`^[.#][\w-]+\s*\(`)
+	// guardPattern matches a Less guard, ".dark when (@mode = dark)"
+	guardPattern = regexp.MustCompile(`\s+when\s.*$`)
+	// lessExtendPattern matches ":extend(.a all)" in a Less selector
+	lessExtendPattern = regexp.MustCompile(`:extend\(([^)]*)\)`)
+)
+
+// hiddenAtRules hold rules that only get output where they're used, with a
+// parent selector that isn't known here
+var hiddenAtRules = toSet("mixin", "function")
+
+// ParseSCSS parses an SCSS stylesheet into the same rule tree as ParseCSS,
+// except that the Selectors of nested rules are resolved against their
+// parents, so "&__title" inside ".card" becomes ".card__title". Rules in
+// mixins and functions get no selectors and @extend goes to Extends
+func ParseSCSS(path string, src []byte) (*Stylesheet, error) {
+	return parseNested(path, src)
+}
+
+// ParseLess parses a Less stylesheet the way ParseSCSS does, mixin
+// definitions and guards are understood and :extend counts as an @extend
+func ParseLess(path string, src []byte) (*Stylesheet, error) {
+	return parseNested(path, src)
+}
+
+// parseNested blanks out what the CSS tokenizer can't cope with, "//"
+// comments and #{} or @{} interpolation, keeping every offset where it
+// was, parses the result and resolves the nesting. Preludes and
+// declarations that had interpolation in them are read again from the
+// original source
+func parseNested(path string, src []byte) (*Stylesheet, error) {
+	stripped := blankLineComments(string(src))
+	blanked := blankInterpolation(stripped)
+	sheet, err := ParseCSS(path, []byte(blanked))
+	if err != nil {
+		return nil, err
+	}
+	sheet.Source = string(src)
+
+	restore := func(start, end int) string {
+		return collapseSpace(cssCommentPattern.ReplaceAllString(stripped[start:end], " "))
+	}
+	sheet.Walk(func(rule *CSSRule) {
+		for _, d := range rule.Declarations {
+			if strings.Contains(d.Property+d.Value, "`") {
+				if restored := parseDeclaration(restore(d.Pos.Offset, statementEnd(blanked, d.Pos.Offset)), d.Pos); restored != nil {
+					*d = *restored
+				}
+			}
+		}
+		if !strings.Contains(rule.Prelude, "`") {
+			return
+		}
+		start, end := rule.Start+len(rule.AtRule), statementEnd(blanked, rule.Start)
+		if rule.AtRule != "" {
+			start++
+		}
+		rule.Prelude = restore(start, end)
+		if rule.AtRule == "" {
+			rule.Selectors = splitSelectorList(rule.Prelude)
+		}
+	})
+
+	resolveNesting(sheet, sheet.Rules, nil, false)
+	return sheet, nil
+}
+
+// resolveNesting rewrites the selectors of rules to what they compile to,
+// hidden rules are the ones in mixins that compile to nothing here
+func resolveNesting(sheet *Stylesheet, rules []*CSSRule, parents []string, hidden bool) {
+	for _, rule := range rules {
+		_, isHidden := hiddenAtRules[rule.AtRule]
+		switch {
+		case rule.AtRule == "":
+			if hidden || isLessMixin(rule.Selectors) {
+				rule.Selectors = nil
+				resolveNesting(sheet, rule.Rules, nil, true)
+				continue
+			}
+			var own []string
+			for _, selector := range rule.Selectors {
+				selector = guardPattern.ReplaceAllString(selector, "")
+				var targets []string
+				for _, m := range lessExtendPattern.FindAllStringSubmatch(selector, -1) {
+					targets = append(targets, m[1])
+				}
+				selector = strings.TrimSpace(lessExtendPattern.ReplaceAllString(selector, ""))
+				own = append(own, selector)
+				for _, target := range targets {
+					sheet.addExtensions(nestSelectors(parents, []string{selector}), target, rule.Pos)
+				}
+			}
+			rule.Selectors = nestSelectors(parents, own)
+			for _, d := range rule.Declarations {
+				// Less spells an @extend inside a rule "&:extend(.a);"
+				if m := lessExtendPattern.FindStringSubmatch(d.Property + ":" + d.Value); m != nil && d.Property == "&" {
+					sheet.addExtensions(rule.Selectors, m[1], d.Pos)
+				}
+			}
+			resolveNesting(sheet, rule.Rules, rule.Selectors, false)
+		case rule.AtRule == "keyframes" || strings.HasSuffix(rule.AtRule, "-keyframes"):
+		case hidden || isHidden || strings.HasPrefix(rule.Prelude, ":"):
+			// a Less detached ruleset, "@detached: { ... }", is a mixin too
+			resolveNesting(sheet, rule.Rules, nil, true)
+		case rule.AtRule == "extend":
+			sheet.addExtensions(parents, rule.Prelude, rule.Pos)
+		case rule.AtRule == "at-root" && rule.Prelude != "":
+			// "@at-root .x { }" is the style rule .x at the top level
+			rule.AtRule = ""
+			rule.Selectors = splitSelectorList(rule.Prelude)
+			resolveNesting(sheet, rule.Rules, rule.Selectors, false)
+		case rule.AtRule == "at-root":
+			resolveNesting(sheet, rule.Rules, nil, false)
+		default:
+			// @media, @include with a content block, @if, @each and the like
+			// keep the selector they're in
+			resolveNesting(sheet, rule.Rules, parents, false)
+		}
+	}
+}
+
+// nestSelectors combines every parent with every nested selector, "&" is
+// replaced by the parent and anything else becomes a descendant of it
+func nestSelectors(parents, selectors []string) []string {
+	if len(parents) == 0 {
+		return selectors
+	}
+	var nested []string
+	for _, parent := range parents {
+		for _, selector := range selectors {
+			if strings.Contains(selector, "&") {
+				nested = append(nested, strings.ReplaceAll(selector, "&", parent))
+			} else {
+				nested = append(nested, parent+" "+selector)
+			}
+		}
+	}
+	return nested
+}
+
+func isLessMixin(selectors []string) bool {
+	for _, selector := range selectors {
+		if lessMixinPattern.MatchString(selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// addExtensions records that selectors extend the classes of targets,
+// "!optional" and Less's "all" don't change what is extended and
+// placeholders like %message-shared aren't classes
+func (s *Stylesheet) addExtensions(selectors []string, targets string, pos Position) {
+	for _, class := range selectorClasses(targets) {
+		for _, selector := range selectors {
+			s.Extends = append(s.Extends, Extension{Selector: selector, Target: class, Pos: pos})
+		}
+	}
+}
+
+// blankLineComments replaces "//" comments with spaces, strings, block
+// comments and url() keep theirs
+func blankLineComments(src string) string {
+	b := []byte(src)
+	for i := 0; i < len(b); i++ {
+		switch ch := b[i]; {
+		case ch == '"' || ch == '\'':
+			for i++; i < len(b) && b[i] != ch && b[i] != '\n'; i++ {
+				if b[i] == '\\' {
+					i++
+				}
+			}
+		case ch == '/' && i+1 < len(b) && b[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return string(b)
+			}
+			i += end + 3
+		case ch == '/' && i+1 < len(b) && b[i+1] == '/':
+			if i >= 4 && strings.EqualFold(src[i-4:i], "url(") || i >= 1 && b[i-1] == ':' {
+				continue
+			}
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				b[i] = ' '
+			}
+		}
+	}
+	return string(b)
+}
+
+// blankInterpolation replaces Sass "#{...}" and Less "@{...}" with
+// backticks, their braces would otherwise open blocks. Backticks are fine
+// in CSS strings and never show up in real stylesheets, newlines are kept
+// so positions don't move
+func blankInterpolation(src string) string {
+	b := []byte(src)
+	for i := 0; i+1 < len(b); i++ {
+		if (b[i] != '#' && b[i] != '@') || b[i+1] != '{' {
+			continue
+		}
+		depth, j := 0, i+1
+		for ; j < len(b); j++ {
+			if b[j] == '{' {
+				depth++
+			} else if b[j] == '}' {
+				if depth--; depth == 0 {
+					break
+				}
+			}
+		}
+		for ; i <= j && i < len(b); i++ {
+			if b[i] != '\n' {
+				b[i] = '`'
+			}
+		}
+		i--
+	}
+	return string(b)
+}
+
+// statementEnd returns the offset of the '{', ';' or '}' ending the
+// statement that starts at start
+func statementEnd(src string, start int) int {
+	depth := 0
+	for i := start; i < len(src); i++ {
+		switch src[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '{', ';', '}':
+			if depth <= 0 {
+				return i
+			}
+		case '"', '\'':
+			quote := src[i]
+			for i++; i < len(src) && src[i] != quote; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return len(src)
+}
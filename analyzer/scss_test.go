@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseSCSS(t *testing.T) {
+	src := `// cards, see https://example.com/bem
+$gap: 1rem;
+.card {
+  padding: $gap;
+  &__title { font-weight: bold; }
+  &--featured, &.is-active { border: 1px solid; }
+  .icon { width: 1rem }
+  > .card__body { margin: 0 }
+  @media (min-width: 640px) {
+    &__footer { display: flex }
+  }
+}
+@mixin hidden { .never { display: none } }
+@each $name in home, user {
+  .icon-#{$name} { background: url("/icons/#{$name}.svg"); }
+}
+.btn-primary { @extend .btn; color: blue }
+.btn { padding: .5rem }
+@at-root .rooted { color: red }
+`
+	sheet, err := ParseSCSS("site.scss", []byte(src))
+	if err != nil {
+		t.Fatalf("ParseSCSS failed: %s", err)
+	}
+	if sheet.Source != src {
+		t.Errorf("Expected the original source to be kept")
+	}
+
+	var defined []string
+	lines := make(map[string]int)
+	for _, d := range sheet.Definitions() {
+		defined = append(defined, d.Selector)
+		lines[d.Class] = d.Pos.Line
+	}
+	expected := []string{
+		".card", ".card__title", ".card--featured", ".card.is-active", ".card.is-active",
+		".card .icon", ".card .icon", ".card > .card__body", ".card > .card__body", ".card__footer", ".btn-primary", ".btn", ".rooted",
+	}
+	if !slices.Equal(defined, expected) {
+		t.Errorf("Expected definitions\n%v\ngot\n%v", expected, defined)
+	}
+	if lines["card__title"] != 5 || lines["card__footer"] != 10 || lines["rooted"] != 19 {
+		t.Errorf("Unexpected definition lines %v", lines)
+	}
+
+	each := sheet.Rules[2].Rules[0]
+	if each.Prelude != ".icon-#{$name}" || each.Declarations[0].Value != `url("/icons/#{$name}.svg")` {
+		t.Errorf("Expected interpolation to be kept, got %q and %+v", each.Prelude, each.Declarations[0])
+	}
+	if len(sheet.Extends) != 1 || sheet.Extends[0] != (Extension{Selector: ".btn-primary", Target: "btn", Pos: Position{File: "site.scss", Line: 17, Column: 16, Offset: strings.Index(src, "@extend")}}) {
+		t.Errorf("Unexpected extends %+v", sheet.Extends)
+	}
+}
+
+func TestParseLess(t *testing.T) {
+	src := `@brand: #36c;
+.bordered(@width: 2px) { .inner { border: @width solid } }
+.nav {
+  .bordered();
+  &-item { color: @brand; }
+  &-item-@{size} { color: red }
+  &:extend(.reset all);
+}
+.link:extend(.nav-item) { text-decoration: none }
+.theme when (@mode = dark) { background: black }
+`
+	sheet, err := ParseLess("site.less", []byte(src))
+	if err != nil {
+		t.Fatalf("ParseLess failed: %s", err)
+	}
+	var defined []string
+	for _, d := range sheet.Definitions() {
+		defined = append(defined, d.Selector)
+	}
+	expected := []string{".nav", ".nav-item", ".link", ".theme"}
+	if !slices.Equal(defined, expected) {
+		t.Errorf("Expected definitions %v, got %v", expected, defined)
+	}
+	var extends []string
+	for _, e := range sheet.Extends {
+		extends = append(extends, e.Selector+" -> "+e.Target)
+	}
+	if !slices.Equal(extends, []string{".nav -> reset", ".link -> nav-item"}) {
+		t.Errorf("Unexpected extends %v", extends)
+	}
+}
+
+func TestUnusedClassesWithSCSS(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<div class="card alert-error"><h2 class="card__title">Hi</h2></div>`,
+		"scss/site.scss": `.card {
+  &__title { font-weight: bold }
+  &__old { color: gray }
+}
+.alert { padding: 1rem }
+.alert-error { @extend .alert; color: red }
+`,
+	}
+	dir := writeProject(t, files)
+
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unused := project.UnusedClasses()
+	if len(unused) != 1 || unused[0].Class != "card__old" || unused[0].Pos.Line != 3 {
+		t.Errorf("Expected only card__old on line 3 to be unused, got %+v", unused)
+	}
+	if !project.DefinedClasses()["card__title"] {
+		t.Errorf("Expected card__title to be defined")
+	}
+}
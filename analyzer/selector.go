@@ -0,0 +1,678 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Selector is a compiled selector list that can be matched against parsed
+// HTML. Pseudo-elements and pseudo-classes for states a static page doesn't
+// have, like :hover or :focus, are assumed to be possible and listed in
+// Assumed
+type Selector struct {
+	alternatives []*complexSelector
+	Assumed      []string
+}
+
+// complexSelector is compound selectors joined by combinators, the
+// combinator at i joins compounds i and i+1. Relative selectors in :has()
+// start with a combinator of their own
+type complexSelector struct {
+	compounds   []*compoundSelector
+	combinators []byte
+	leading     byte
+}
+
+type compoundSelector struct {
+	tag     string
+	ids     []string
+	classes []string
+	attrs   []attrSelector
+	pseudos []pseudoClass
+}
+
+// attrSelector is [name], or [name op value] with fold set by the i flag
+type attrSelector struct {
+	name, op, value string
+	fold            bool
+}
+
+// pseudoClass is a pseudo-class that can be evaluated, the nth ones keep
+// their An+B and "of S" and the logical ones their selector list
+type pseudoClass struct {
+	name string
+	list *Selector
+	a, b int
+}
+
+// assumedPseudoClasses depend on the user, the browser or the page's state
+// rather than on the markup, a selector using them could match
+var assumedPseudoClasses = toSet("hover", "focus", "focus-within", "focus-visible", "active", "visited",
+	"target", "target-within", "current", "past", "future", "playing", "paused", "fullscreen", "modal",
+	"picture-in-picture", "placeholder-shown", "autofill", "-webkit-autofill", "valid", "invalid",
+	"user-valid", "user-invalid", "in-range", "out-of-range", "indeterminate", "default", "open",
+	"closed", "popover-open", "defined", "host", "scope", "local-link", "lang", "dir", "host-context",
+	"state", "blank", "-moz-focusring", "-moz-placeholder", "-ms-input-placeholder")
+
+// legacyPseudoElements can be written with a single colon
+var legacyPseudoElements = toSet("before", "after", "first-line", "first-letter")
+
+// CompileSelector parses a selector list
+func CompileSelector(selector string) (*Selector, error) {
+	return compileSelectorList(selector, false)
+}
+
+func compileSelectorList(selector string, relative bool) (*Selector, error) {
+	p := &selectorParser{s: selector, list: &Selector{}}
+	for {
+		complex, err := p.parseComplex(relative)
+		if err != nil {
+			return nil, err
+		}
+		p.list.alternatives = append(p.list.alternatives, complex)
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return p.list, nil
+		}
+		if p.s[p.i] != ',' {
+			return nil, p.errorf("unexpected %q", p.s[p.i])
+		}
+		p.i++
+	}
+}
+
+type selectorParser struct {
+	s    string
+	i    int
+	list *Selector
+}
+
+func (p *selectorParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s at offset %d of %q", fmt.Sprintf(format, args...), p.i, p.s)
+}
+
+func (p *selectorParser) skipSpace() bool {
+	start := p.i
+	for p.i < len(p.s) && isHTMLSpace(p.s[p.i]) {
+		p.i++
+	}
+	return p.i > start
+}
+
+func isCombinator(ch byte) bool {
+	return ch == '>' || ch == '+' || ch == '~'
+}
+
+func (p *selectorParser) parseComplex(relative bool) (*complexSelector, error) {
+	c := &complexSelector{leading: ' '}
+	p.skipSpace()
+	if relative && p.i < len(p.s) && isCombinator(p.s[p.i]) {
+		c.leading = p.s[p.i]
+		p.i++
+		p.skipSpace()
+	}
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		c.compounds = append(c.compounds, compound)
+		sawSpace := p.skipSpace()
+		if p.i >= len(p.s) || p.s[p.i] == ',' {
+			return c, nil
+		}
+		combinator := byte(' ')
+		if isCombinator(p.s[p.i]) {
+			combinator = p.s[p.i]
+			p.i++
+			p.skipSpace()
+		} else if !sawSpace {
+			return nil, p.errorf("unexpected %q", p.s[p.i])
+		}
+		c.combinators = append(c.combinators, combinator)
+	}
+}
+
+func (p *selectorParser) ident() string {
+	name, n := readCSSIdent(p.s[p.i:])
+	p.i += n
+	return name
+}
+
+func (p *selectorParser) parseCompound() (*compoundSelector, error) {
+	c := &compoundSelector{}
+	start := p.i
+	if p.i < len(p.s) && p.s[p.i] == '*' {
+		p.i++
+	} else {
+		c.tag = strings.ToLower(p.ident())
+	}
+	// a namespace prefix, svg|rect or *|a, doesn't matter for HTML
+	if p.i < len(p.s) && p.s[p.i] == '|' {
+		p.i++
+		if p.i < len(p.s) && p.s[p.i] == '*' {
+			p.i++
+			c.tag = ""
+		} else {
+			c.tag = strings.ToLower(p.ident())
+		}
+	}
+	for p.i < len(p.s) {
+		switch p.s[p.i] {
+		case '#':
+			p.i++
+			c.ids = append(c.ids, p.ident())
+		case '.':
+			p.i++
+			name := p.ident()
+			if name == "" {
+				return nil, p.errorf("expected a class name")
+			}
+			c.classes = append(c.classes, name)
+		case '[':
+			attr, err := p.parseAttr()
+			if err != nil {
+				return nil, err
+			}
+			c.attrs = append(c.attrs, attr)
+		case ':':
+			if err := p.parsePseudo(c); err != nil {
+				return nil, err
+			}
+		default:
+			if p.i == start {
+				return nil, p.errorf("expected a selector")
+			}
+			return c, nil
+		}
+	}
+	if p.i == start {
+		return nil, p.errorf("expected a selector")
+	}
+	return c, nil
+}
+
+func (p *selectorParser) parseAttr() (attrSelector, error) {
+	p.i++
+	p.skipSpace()
+	attr := attrSelector{name: strings.ToLower(p.ident())}
+	if p.i < len(p.s) && p.s[p.i] == '|' && (p.i+1 >= len(p.s) || p.s[p.i+1] != '=') {
+		p.i++
+		attr.name = strings.ToLower(p.ident())
+	}
+	if attr.name == "" {
+		return attr, p.errorf("expected an attribute name")
+	}
+	p.skipSpace()
+	if p.i < len(p.s) && p.s[p.i] != ']' {
+		for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+			if strings.HasPrefix(p.s[p.i:], op) {
+				attr.op = op
+				p.i += len(op)
+				break
+			}
+		}
+		if attr.op == "" {
+			return attr, p.errorf("unexpected %q in attribute selector", p.s[p.i])
+		}
+		p.skipSpace()
+		if p.i < len(p.s) && (p.s[p.i] == '"' || p.s[p.i] == '\'') {
+			quote := p.s[p.i]
+			var b strings.Builder
+			for p.i++; p.i < len(p.s) && p.s[p.i] != quote; p.i++ {
+				if p.s[p.i] == '\\' && p.i+1 < len(p.s) {
+					p.i++
+				}
+				b.WriteByte(p.s[p.i])
+			}
+			p.i++
+			attr.value = b.String()
+		} else {
+			attr.value = p.ident()
+		}
+		p.skipSpace()
+		if p.i < len(p.s) && (p.s[p.i] == 'i' || p.s[p.i] == 'I' || p.s[p.i] == 's' || p.s[p.i] == 'S') {
+			attr.fold = p.s[p.i] == 'i' || p.s[p.i] == 'I'
+			p.i++
+			p.skipSpace()
+		}
+	}
+	if p.i >= len(p.s) || p.s[p.i] != ']' {
+		return attr, p.errorf("unclosed attribute selector")
+	}
+	p.i++
+	return attr, nil
+}
+
+func (p *selectorParser) parsePseudo(c *compoundSelector) error {
+	p.i++
+	element := p.i < len(p.s) && p.s[p.i] == ':'
+	if element {
+		p.i++
+	}
+	name := strings.ToLower(p.ident())
+	if name == "" {
+		return p.errorf("expected a pseudo-class name")
+	}
+	var argument string
+	functional := p.i < len(p.s) && p.s[p.i] == '('
+	if functional {
+		inner, end := functionArgument(p.s, p.i)
+		if end >= len(p.s) {
+			return p.errorf("unclosed parenthesis")
+		}
+		argument, p.i = inner, end+1
+	}
+	if _, legacy := legacyPseudoElements[name]; element || legacy {
+		p.assume("::" + name)
+		return nil
+	}
+
+	pseudo := pseudoClass{name: name}
+	switch name {
+	case "not", "is", "where", "matches", "any", "-webkit-any", "-moz-any", "has":
+		if !functional {
+			return p.errorf(":%s needs an argument", name)
+		}
+		list, err := compileSelectorList(argument, name == "has")
+		if err != nil {
+			return err
+		}
+		pseudo.list = list
+		for _, assumed := range list.Assumed {
+			p.assume(assumed)
+		}
+		if name == "not" && len(list.Assumed) > 0 {
+			// :not(:hover) is as possible as :hover, the assumed part would
+			// otherwise match everything and the :not nothing
+			return nil
+		}
+		if name != "not" && name != "has" {
+			pseudo.name = "is"
+		}
+	case "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+		formula := argument
+		if name == "nth-child" || name == "nth-last-child" {
+			if before, of, found := cutFold(argument, " of "); found {
+				list, err := compileSelectorList(of, false)
+				if err != nil {
+					return err
+				}
+				formula, pseudo.list = before, list
+			}
+		}
+		a, b, ok := parseNth(formula)
+		if !ok {
+			return p.errorf("bad An+B %q", formula)
+		}
+		pseudo.a, pseudo.b = a, b
+	case "first-child", "last-child", "first-of-type", "last-of-type":
+		// :last-child is :nth-last-child(1) and so on
+		pseudo.name = "nth-" + strings.TrimPrefix(name, "first-")
+		pseudo.b = 1
+	case "only-child", "only-of-type", "root", "empty", "checked", "disabled", "enabled", "required",
+		"optional", "link", "any-link", "read-only", "read-write":
+	default:
+		if _, assumed := assumedPseudoClasses[name]; !assumed {
+			return p.errorf("unsupported pseudo-class :%s", name)
+		}
+		p.assume(":" + name)
+		return nil
+	}
+	c.pseudos = append(c.pseudos, pseudo)
+	return nil
+}
+
+func (p *selectorParser) assume(name string) {
+	for _, assumed := range p.list.Assumed {
+		if assumed == name {
+			return
+		}
+	}
+	p.list.Assumed = append(p.list.Assumed, name)
+}
+
+func cutFold(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(strings.ToLower(s), sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// parseNth reads the An+B of the nth pseudo-classes
+func parseNth(formula string) (a, b int, ok bool) {
+	formula = strings.ToLower(strings.Join(strings.Fields(formula), ""))
+	switch formula {
+	case "odd":
+		return 2, 1, true
+	case "even":
+		return 2, 0, true
+	}
+	coefficient, offset, hasN := strings.Cut(formula, "n")
+	if !hasN {
+		b, err := strconv.Atoi(formula)
+		return 0, b, err == nil
+	}
+	switch coefficient {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		var err error
+		if a, err = strconv.Atoi(coefficient); err != nil {
+			return 0, 0, false
+		}
+	}
+	if offset != "" {
+		var err error
+		if b, err = strconv.Atoi(strings.TrimPrefix(offset, "+")); err != nil {
+			return 0, 0, false
+		}
+	}
+	return a, b, true
+}
+
+// Match reports whether the element matches one of the selectors
+func (s *Selector) Match(n *html.Node) bool {
+	return s.matchScoped(n, nil)
+}
+
+func (s *Selector) matchScoped(n, scope *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	for _, c := range s.alternatives {
+		if c.matchAt(len(c.compounds)-1, n, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAt matches right to left, the compound at i against n and what's
+// left of it against n's ancestors or siblings. A relative selector's
+// first compound has to sit where its leading combinator says from scope
+func (c *complexSelector) matchAt(i int, n, scope *html.Node) bool {
+	if !c.compounds[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return scope == nil || related(c.leading, n, scope)
+	}
+	switch c.combinators[i-1] {
+	case '>':
+		parent := parentElement(n)
+		return parent != nil && c.matchAt(i-1, parent, scope)
+	case '+':
+		previous := previousElement(n)
+		return previous != nil && c.matchAt(i-1, previous, scope)
+	case '~':
+		for previous := previousElement(n); previous != nil; previous = previousElement(previous) {
+			if c.matchAt(i-1, previous, scope) {
+				return true
+			}
+		}
+	default:
+		for parent := parentElement(n); parent != nil; parent = parentElement(parent) {
+			if c.matchAt(i-1, parent, scope) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// related reports whether n is where combinator says from scope
+func related(combinator byte, n, scope *html.Node) bool {
+	switch combinator {
+	case '>':
+		return parentElement(n) == scope
+	case '+':
+		return previousElement(n) == scope
+	case '~':
+		for previous := previousElement(n); previous != nil; previous = previousElement(previous) {
+			if previous == scope {
+				return true
+			}
+		}
+	default:
+		for parent := parentElement(n); parent != nil; parent = parentElement(parent) {
+			if parent == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parentElement(n *html.Node) *html.Node {
+	if n.Parent != nil && n.Parent.Type == html.ElementNode {
+		return n.Parent
+	}
+	return nil
+}
+
+func previousElement(n *html.Node) *html.Node {
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func nextElement(n *html.Node) *html.Node {
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func attribute(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func (c *compoundSelector) match(n *html.Node) bool {
+	if c.tag != "" && !strings.EqualFold(n.Data, c.tag) {
+		return false
+	}
+	for _, id := range c.ids {
+		if value, _ := attribute(n, "id"); value != id {
+			return false
+		}
+	}
+	if len(c.classes) > 0 {
+		value, _ := attribute(n, "class")
+		classes := strings.Fields(value)
+		for _, class := range c.classes {
+			found := false
+			for _, have := range classes {
+				if have == class {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	for _, attr := range c.attrs {
+		if !attr.match(n) {
+			return false
+		}
+	}
+	for _, pseudo := range c.pseudos {
+		if !pseudo.match(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a attrSelector) match(n *html.Node) bool {
+	value, ok := attribute(n, a.name)
+	if !ok {
+		return false
+	}
+	want := a.value
+	if a.fold {
+		value, want = strings.ToLower(value), strings.ToLower(want)
+	}
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return value == want
+	case "~=":
+		for _, field := range strings.Fields(value) {
+			if field == want {
+				return true
+			}
+		}
+		return false
+	case "|=":
+		return value == want || strings.HasPrefix(value, want+"-")
+	case "^=":
+		return want != "" && strings.HasPrefix(value, want)
+	case "$=":
+		return want != "" && strings.HasSuffix(value, want)
+	default:
+		return want != "" && strings.Contains(value, want)
+	}
+}
+
+func (p pseudoClass) match(n *html.Node) bool {
+	switch p.name {
+	case "not":
+		return !p.list.Match(n)
+	case "is":
+		return p.list.Match(n)
+	case "has":
+		// the candidates of :has(+ .a .b) go as far as the sibling's subtree,
+		// searching under the parent covers every combinator
+		root := n.Parent
+		if root == nil {
+			root = n
+		}
+		return findElement(root, func(candidate *html.Node) bool {
+			return candidate != n && p.list.matchScoped(candidate, n)
+		}) != nil
+	case "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+		return nthMatches(p.a, p.b, p.position(n))
+	case "only-child":
+		return previousElement(n) == nil && nextElement(n) == nil
+	case "only-of-type":
+		return pseudoClass{name: "nth-of-type", b: 1}.match(n) && pseudoClass{name: "nth-last-of-type", b: 1}.match(n)
+	case "root":
+		return n.Parent != nil && n.Parent.Type == html.DocumentNode
+	case "empty":
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode || child.Type == html.TextNode {
+				return false
+			}
+		}
+		return true
+	case "checked":
+		_, checked := attribute(n, "checked")
+		_, selected := attribute(n, "selected")
+		return checked || selected
+	case "disabled", "enabled":
+		_, disabled := attribute(n, "disabled")
+		if p.name == "disabled" {
+			return disabled
+		}
+		return !disabled && isFormElement(n)
+	case "required", "optional":
+		_, required := attribute(n, "required")
+		if p.name == "required" {
+			return required
+		}
+		return !required && isFormElement(n)
+	case "read-only", "read-write":
+		_, readonly := attribute(n, "readonly")
+		writable := (n.Data == "input" || n.Data == "textarea") && !readonly
+		if editable, ok := attribute(n, "contenteditable"); ok && editable != "false" {
+			writable = true
+		}
+		return writable == (p.name == "read-write")
+	case "link", "any-link":
+		_, href := attribute(n, "href")
+		return href && (n.Data == "a" || n.Data == "area" || n.Data == "link")
+	}
+	return false
+}
+
+func isFormElement(n *html.Node) bool {
+	switch n.Data {
+	case "input", "select", "textarea", "button", "fieldset", "optgroup", "option":
+		return true
+	}
+	return false
+}
+
+// position is n's 1-based index among the siblings the pseudo-class counts,
+// from the end for the nth-last ones
+func (p pseudoClass) position(n *html.Node) int {
+	last := strings.HasPrefix(p.name, "nth-last-")
+	ofType := strings.HasSuffix(p.name, "-of-type")
+	counts := func(s *html.Node) bool {
+		switch {
+		case ofType:
+			return s.Data == n.Data
+		case p.list != nil:
+			return p.list.Match(s)
+		}
+		return true
+	}
+	if p.list != nil && !p.list.Match(n) {
+		return 0
+	}
+	index := 1
+	sibling := previousElement
+	if last {
+		sibling = nextElement
+	}
+	for s := sibling(n); s != nil; s = sibling(s) {
+		if counts(s) {
+			index++
+		}
+	}
+	return index
+}
+
+// nthMatches reports whether index is a*k+b for some k >= 0
+func nthMatches(a, b, index int) bool {
+	if index <= 0 {
+		return false
+	}
+	if a == 0 {
+		return index == b
+	}
+	return (index-b)%a == 0 && (index-b)/a >= 0
+}
+
+// findElement returns the first element under root, in document order,
+// that match accepts
+func findElement(root *html.Node, match func(*html.Node) bool) *html.Node {
+	for child := root.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && match(child) {
+			return child
+		}
+		if found := findElement(child, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+const selectorPage = `<!DOCTYPE html>
+<html><body>
+<nav id="main" class="nav">
+  <ul>
+    <li class="item" id="first"><a href="/">Home</a></li>
+    <li class="item active" id="second"><a href="/docs" lang="en-US">Docs</a></li>
+    <li class="item" id="third"><span></span></li>
+    <li class="item disabled" id="fourth" data-state="Closed"></li>
+  </ul>
+</nav>
+<form id="form"><input id="name" required><input id="box" type="checkbox" checked disabled></form>
+<p id="empty"></p><p id="after">Text</p>
+</body></html>`
+
+func TestSelectorMatch(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(selectorPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]string{
+		`.nav > .item.active`:                     nil,
+		`.nav .item.active`:                       {"second"},
+		`ul > li:nth-child(2n+1)`:                 {"first", "third"},
+		`li:nth-child(odd of .item)`:              {"first", "third"},
+		`li:nth-last-child(1)`:                    {"fourth"},
+		`li:first-child, li:last-of-type`:         {"first", "fourth"},
+		`.item:not(.active, .disabled)`:           {"first", "third"},
+		`:is(#first, #third) + li`:                {"second", "fourth"},
+		`#first ~ .disabled`:                      {"fourth"},
+		`li:has(> a[href^="/d"])`:                 {"second"},
+		`li:has(span)`:                            {"third"},
+		`nav:has(+ form)`:                         {"main"},
+		`[data-state="closed" i]`:                 {"fourth"},
+		`[data-state="closed"]`:                   nil,
+		`a[lang|=en]`:                             nil,
+		`a[href$="docs"]`:                         nil,
+		`input:required, input:checked`:           {"name", "box"},
+		`input:enabled`:                           {"name"},
+		`p:empty`:                                 {"empty"},
+		`p:not(:empty)`:                           {"after"},
+		`.item:hover::before`:                     {"first", "second", "third", "fourth"},
+		`html:root > body > form > :only-of-type`: nil,
+	}
+	for selector, expected := range tests {
+		compiled, err := CompileSelector(selector)
+		if err != nil {
+			t.Errorf("%s: %s", selector, err)
+			continue
+		}
+		var ids []string
+		findElement(doc, func(n *html.Node) bool {
+			if id, _ := attribute(n, "id"); id != "" && compiled.Match(n) {
+				ids = append(ids, id)
+			}
+			return false
+		})
+		if !slices.Equal(ids, expected) {
+			t.Errorf("%s: expected %v, got %v", selector, expected, ids)
+		}
+	}
+}
+
+func TestCompileSelector(t *testing.T) {
+	compiled, err := CompileSelector(`.btn:hover::after, .btn:not(:focus-visible)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(compiled.Assumed, []string{":hover", "::after", ":focus-visible"}) {
+		t.Errorf("Unexpected assumed states %v", compiled.Assumed)
+	}
+	for _, bad := range []string{`.a >`, `.a[href`, `.a:unknown-thing`, `li:nth-child(x)`, `& .b`} {
+		if _, err := CompileSelector(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// shorthand is a utility two others on the same element add up to
+type shorthand struct {
+	root  string
+	parts [2]string
+	// except lists values the shorthand doesn't have, there's no size-screen
+	except []string
+}
+
+// shorthands are tried in order until none applies, so the sides come
+// together into axes before the axes come together
+var shorthands = []shorthand{
+	{root: "mx", parts: [2]string{"ml", "mr"}},
+	{root: "my", parts: [2]string{"mt", "mb"}},
+	{root: "m", parts: [2]string{"mx", "my"}},
+	{root: "px", parts: [2]string{"pl", "pr"}},
+	{root: "py", parts: [2]string{"pt", "pb"}},
+	{root: "p", parts: [2]string{"px", "py"}},
+	{root: "scroll-mx", parts: [2]string{"scroll-ml", "scroll-mr"}},
+	{root: "scroll-my", parts: [2]string{"scroll-mt", "scroll-mb"}},
+	{root: "scroll-m", parts: [2]string{"scroll-mx", "scroll-my"}},
+	{root: "scroll-px", parts: [2]string{"scroll-pl", "scroll-pr"}},
+	{root: "scroll-py", parts: [2]string{"scroll-pt", "scroll-pb"}},
+	{root: "scroll-p", parts: [2]string{"scroll-px", "scroll-py"}},
+	{root: "inset-x", parts: [2]string{"left", "right"}},
+	{root: "inset-y", parts: [2]string{"top", "bottom"}},
+	{root: "inset", parts: [2]string{"inset-x", "inset-y"}},
+	{root: "size", parts: [2]string{"w", "h"}, except: []string{"screen"}},
+	{root: "gap", parts: [2]string{"gap-x", "gap-y"}},
+	{root: "overflow", parts: [2]string{"overflow-x", "overflow-y"}},
+	{root: "overscroll", parts: [2]string{"overscroll-x", "overscroll-y"}},
+	{root: "scale", parts: [2]string{"scale-x", "scale-y"}},
+	{root: "border-x", parts: [2]string{"border-l", "border-r"}},
+	{root: "border-y", parts: [2]string{"border-t", "border-b"}},
+	{root: "border", parts: [2]string{"border-x", "border-y"}},
+	{root: "rounded-t", parts: [2]string{"rounded-tl", "rounded-tr"}},
+	{root: "rounded-b", parts: [2]string{"rounded-bl", "rounded-br"}},
+	{root: "rounded-l", parts: [2]string{"rounded-tl", "rounded-bl"}},
+	{root: "rounded-r", parts: [2]string{"rounded-tr", "rounded-br"}},
+	{root: "rounded", parts: [2]string{"rounded-t", "rounded-b"}},
+	{root: "rounded", parts: [2]string{"rounded-l", "rounded-r"}},
+}
+
+// shorthandClass is a class of an element, or the shorthand some of them
+// add up to
+type shorthandClass struct {
+	class      Class
+	raw        string
+	properties []string
+	// variants, lead, base and trail are raw split up by splitRaw
+	variants, lead, base, trail string
+	// tokens are the indexes of the classes in the attribute it stands for
+	tokens []int
+}
+
+// scope is what two utilities need to share to affect one another
+func (c *shorthandClass) scope() string {
+	return fmt.Sprintf("%s|%t", strings.Join(c.class.Variants, ":"), c.class.Important)
+}
+
+// value is what two utilities need to share to add up to a shorthand
+func (c *shorthandClass) value() string {
+	return fmt.Sprintf("%s|%t|%s|%s|%s", c.scope(), c.class.Negative, c.class.Value, c.class.Arbitrary, c.class.Modifier)
+}
+
+// withRoot returns the raw class with its root swapped for root, keeping
+// its variants, important mark, prefix and value as written
+func (c *shorthandClass) withRoot(root string) string {
+	i := strings.Index(c.base, c.class.Utility)
+	if i < 0 {
+		return ""
+	}
+	return c.variants + c.lead + c.base[:i] + root + c.base[i+len(c.class.Utility):] + c.trail
+}
+
+// Shorthand is a group of utilities of one element that one shorthand can
+// replace
+type Shorthand struct {
+	Element *Element
+	// Tokens are the indexes of the classes it replaces, in attribute order
+	Tokens      []int
+	Replacement string
+}
+
+// elementShorthands returns the groups of classes of element that add up to
+// a shorthand. A group is left alone when another utility under the same
+// variants sets one of its properties, px-2 pl-4 pr-4 stays as it is since
+// px-4 would then clash with px-2 instead of overriding it
+func elementShorthands(element *Element, parser *ClassParser, catalog *Catalog) []Shorthand {
+	if !rewritable(element) {
+		return nil
+	}
+	var classes []*shorthandClass
+	for i, token := range element.Classes {
+		class := parser.Parse(token.Name)
+		if !class.Known || class.Property != "" || (catalog != nil && catalog.customUtility(class)) {
+			continue
+		}
+		c := &shorthandClass{class: class, raw: token.Name, properties: classProperties(class), tokens: []int{i}}
+		c.variants, c.lead, c.base, c.trail = parser.splitRaw(token.Name)
+		classes = append(classes, c)
+	}
+
+	for merged := true; merged; {
+		merged = false
+		for _, s := range shorthands {
+			for i, a := range classes {
+				j := slices.IndexFunc(classes, func(b *shorthandClass) bool {
+					return a.class.Utility == s.parts[0] && b.class.Utility == s.parts[1] && a.value() == b.value()
+				})
+				if j < 0 || slices.Contains(s.except, a.class.Value) || overridden(classes, i, j) {
+					continue
+				}
+				b := classes[j]
+				raw := a.withRoot(s.root)
+				if raw == "" {
+					continue
+				}
+				if b.tokens[0] < a.tokens[0] {
+					raw = b.withRoot(s.root)
+					if raw == "" {
+						continue
+					}
+				}
+				class := a.class
+				class.Utility, class.Raw = s.root, raw
+				combined := &shorthandClass{
+					class:      class,
+					raw:        raw,
+					properties: append(slices.Clone(a.properties), b.properties...),
+					tokens:     append(slices.Clone(a.tokens), b.tokens...),
+				}
+				combined.variants, combined.lead, combined.base, combined.trail = parser.splitRaw(raw)
+				sort.Ints(combined.tokens)
+				classes[i] = combined
+				classes = slices.Delete(classes, j, j+1)
+				merged = true
+				break
+			}
+		}
+	}
+
+	var found []Shorthand
+	for _, c := range classes {
+		if len(c.tokens) > 1 {
+			found = append(found, Shorthand{Element: element, Tokens: c.tokens, Replacement: c.raw})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Tokens[0] < found[j].Tokens[0] })
+	return found
+}
+
+// overridden reports whether a class other than classes[i] and classes[j]
+// sets one of their properties under the same variants
+func overridden(classes []*shorthandClass, i, j int) bool {
+	for k, other := range classes {
+		if k == i || k == j || other.scope() != classes[i].scope() {
+			continue
+		}
+		for _, property := range other.properties {
+			if slices.Contains(classes[i].properties, property) || slices.Contains(classes[j].properties, property) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Shorthands returns a finding for every group of utilities on one element
+// that a shorthand can replace, like mx-2 my-2 for m-2 or w-8 h-8 for
+// size-8, at the first of them. Utilities only add up under the same
+// variants and the same important mark
+func Shorthands(p *Project, parser *ClassParser, catalog *Catalog) []Finding {
+	var findings []Finding
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			for _, s := range elementShorthands(element, parser, catalog) {
+				names := make([]string, len(s.Tokens))
+				for i, t := range s.Tokens {
+					names[i] = element.Classes[t].Name
+				}
+				first := element.Classes[s.Tokens[0]]
+				findings = append(findings, Finding{
+					Rule:        "shorthand",
+					Pos:         first.Pos,
+					Class:       first.Name,
+					Message:     fmt.Sprintf("%s can be written as %s", strings.Join(names, " "), s.Replacement),
+					Suggestions: []string{s.Replacement},
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// ShorthandEdits returns the edits replacing the groups of utilities of
+// file with their shorthands. The shorthand takes the place of the first
+// class of the group and the rest go, keeping the formatting of the
+// attribute
+func ShorthandEdits(file *SourceFile, src []byte, parser *ClassParser, catalog *Catalog) []Edit {
+	var edits []Edit
+	for _, element := range file.Elements {
+		if element.ValueEnd > len(src) {
+			continue
+		}
+		remove := make(map[int]bool)
+		for _, s := range elementShorthands(element, parser, catalog) {
+			first := element.Classes[s.Tokens[0]]
+			edits = append(edits, Edit{Start: first.Pos.Offset, End: first.End, Text: s.Replacement})
+			for _, t := range s.Tokens[1:] {
+				remove[element.Classes[t].Pos.Offset] = true
+			}
+		}
+		edits = append(edits, removeTokens(src, element, remove)...)
+	}
+	return edits
+}
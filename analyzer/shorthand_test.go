@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+func TestShorthands(t *testing.T) {
+	page := `<div class="mx-2 flex my-2"></div>
+<div class="pt-4 pb-4 pl-4 pr-4"></div>
+<img class="w-8 h-8 md:w-4 hover:md:h-4">
+<div class="rounded-tl-lg rounded-tr-lg !mt-1 !mb-1 mb-1 -ml-2 -mr-2"></div>
+<div class="px-2 pl-4 pr-4 md:pl-1 md:pr-1"></div>
+<ul class="
+    top-0
+    bottom-0 left-0
+    right-0
+"></ul>
+<div class="w-screen h-screen"></div>
+<div class="w-[10px] h-[10px] mt-2 mb-3"></div>
+`
+	dir := writeProject(t, map[string]string{"index.html": page})
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := Shorthands(project, DefaultParser, NewCatalog())
+	expected := []struct {
+		line, column int
+		message      string
+	}{
+		{1, 13, "mx-2 my-2 can be written as m-2"},
+		{2, 13, "pt-4 pb-4 pl-4 pr-4 can be written as p-4"},
+		{3, 13, "w-8 h-8 can be written as size-8"},
+		{4, 13, "rounded-tl-lg rounded-tr-lg can be written as rounded-t-lg"},
+		{4, 41, "!mt-1 !mb-1 can be written as !my-1"},
+		{4, 58, "-ml-2 -mr-2 can be written as -mx-2"},
+		{5, 28, "md:pl-1 md:pr-1 can be written as md:px-1"},
+		{7, 5, "top-0 bottom-0 left-0 right-0 can be written as inset-0"},
+		{12, 13, "w-[10px] h-[10px] can be written as size-[10px]"},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %v", len(expected), findings)
+	}
+	for i, e := range expected {
+		if f := findings[i]; f.Pos.Line != e.line || f.Pos.Column != e.column || f.Message != e.message || f.Rule != "shorthand" {
+			t.Errorf("Expected %q at %d:%d, got %v", e.message, e.line, e.column, f)
+		}
+	}
+
+	fixed, err := ApplyEdits([]byte(page), ShorthandEdits(project.Files[0], []byte(page), DefaultParser, NewCatalog()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<div class="m-2 flex"></div>
+<div class="p-4"></div>
+<img class="size-8 md:w-4 hover:md:h-4">
+<div class="rounded-t-lg !my-1 mb-1 -mx-2"></div>
+<div class="px-2 pl-4 pr-4 md:px-1"></div>
+<ul class="
+    inset-0
+"></ul>
+<div class="w-screen h-screen"></div>
+<div class="size-[10px] mt-2 mb-3"></div>
+`
+	if string(fixed) != want {
+		t.Errorf("Expected the fixed page to be\n%s\ngot\n%s", want, fixed)
+	}
+}
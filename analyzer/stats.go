@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ClassStats is how much and how widely a single class is used
+type ClassStats struct {
+	Class       string `json:"class"`
+	Occurrences int    `json:"occurrences"`
+	Files       int    `json:"files"`
+	Elements    int    `json:"elements"`
+	FirstFile   string `json:"firstFile"`
+	LastFile    string `json:"lastFile"`
+}
+
+// HistogramBucket counts the classes whose occurrences fall in [Min, Max],
+// a Max of 0 means the bucket is open-ended
+type HistogramBucket struct {
+	Label   string `json:"label"`
+	Min     int    `json:"min"`
+	Max     int    `json:"max"`
+	Classes int    `json:"classes"`
+}
+
+// Stats holds the per-class statistics of a project, Classes is sorted by
+// occurrences (most used first) and then by name
+type Stats struct {
+	Files            int          `json:"files"`
+	Elements         int          `json:"elements"`
+	TotalOccurrences int          `json:"totalOccurrences"`
+	Classes          []ClassStats `json:"classes"`
+}
+
+var histogramBuckets = []HistogramBucket{
+	{Label: "1", Min: 1, Max: 1},
+	{Label: "2", Min: 2, Max: 2},
+	{Label: "3-4", Min: 3, Max: 4},
+	{Label: "5-9", Min: 5, Max: 9},
+	{Label: "10-19", Min: 10, Max: 19},
+	{Label: "20-49", Min: 20, Max: 49},
+	{Label: "50-99", Min: 50, Max: 99},
+	{Label: "100+", Min: 100},
+}
+
+// ComputeStats counts every class usage of the project instead of throwing
+// the counts away like the class log does
+func ComputeStats(p *Project) *Stats {
+	stats := &Stats{Files: len(p.Files)}
+	byClass := make(map[string]*ClassStats)
+	for _, file := range p.Files {
+		// files come sorted by path, the last file to see a class is LastFile
+		seenInFile := make(map[string]bool)
+		for _, element := range file.Elements {
+			// script references aren't elements, and are counted as used
+			// rather than as usages
+			if element.Provenance == ProvenanceJS {
+				continue
+			}
+			stats.Elements++
+			seenInElement := make(map[string]bool)
+			for _, token := range element.Classes {
+				stats.TotalOccurrences++
+				cs, exists := byClass[token.Name]
+				if !exists {
+					cs = &ClassStats{Class: token.Name, FirstFile: file.Path}
+					byClass[token.Name] = cs
+				}
+				cs.Occurrences++
+				if !seenInElement[token.Name] {
+					seenInElement[token.Name] = true
+					cs.Elements++
+				}
+				if !seenInFile[token.Name] {
+					seenInFile[token.Name] = true
+					cs.Files++
+					cs.LastFile = file.Path
+				}
+			}
+		}
+	}
+
+	for _, cs := range byClass {
+		stats.Classes = append(stats.Classes, *cs)
+	}
+	sort.Slice(stats.Classes, func(i, j int) bool {
+		a, b := stats.Classes[i], stats.Classes[j]
+		if a.Occurrences != b.Occurrences {
+			return a.Occurrences > b.Occurrences
+		}
+		return a.Class < b.Class
+	})
+	return stats
+}
+
+// MostUsed returns the n classes with the most occurrences, none when n is negative
+func (s *Stats) MostUsed(n int) []ClassStats {
+	return s.Classes[:max(0, min(n, len(s.Classes)))]
+}
+
+// LeastUsed returns the n classes with the fewest occurrences, least used first
+func (s *Stats) LeastUsed(n int) []ClassStats {
+	var least []ClassStats
+	for i := len(s.Classes) - 1; i >= 0 && len(least) < n; i-- {
+		least = append(least, s.Classes[i])
+	}
+	return least
+}
+
+// Singletons returns the classes used exactly once in the whole project
+func (s *Stats) Singletons() []ClassStats {
+	var singletons []ClassStats
+	for _, cs := range s.Classes {
+		if cs.Occurrences == 1 {
+			singletons = append(singletons, cs)
+		}
+	}
+	sort.Slice(singletons, func(i, j int) bool { return singletons[i].Class < singletons[j].Class })
+	return singletons
+}
+
+// Histogram buckets the classes by how often they occur
+func (s *Stats) Histogram() []HistogramBucket {
+	buckets := make([]HistogramBucket, len(histogramBuckets))
+	copy(buckets, histogramBuckets)
+	for _, cs := range s.Classes {
+		for i := range buckets {
+			if cs.Occurrences >= buckets[i].Min && (buckets[i].Max == 0 || cs.Occurrences <= buckets[i].Max) {
+				buckets[i].Classes++
+				break
+			}
+		}
+	}
+	return buckets
+}
+
+// WriteReport writes a human readable report listing the top n most and
+// least used classes, the singletons and the histogram
+func (s *Stats) WriteReport(w io.Writer, n int) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d classes, %d occurrences on %d elements in %d files\n",
+		len(s.Classes), s.TotalOccurrences, s.Elements, s.Files)
+
+	writeTable := func(title string, classes []ClassStats) {
+		fmt.Fprintf(&b, "\n%s\n", title)
+		if len(classes) == 0 {
+			b.WriteString("  (none)\n")
+			return
+		}
+		fmt.Fprintf(&b, "  %-40s %8s %6s %8s  %s\n", "class", "uses", "files", "elements", "first / last file")
+		for _, cs := range classes {
+			fmt.Fprintf(&b, "  %-40s %8d %6d %8d  %s / %s\n",
+				cs.Class, cs.Occurrences, cs.Files, cs.Elements, cs.FirstFile, cs.LastFile)
+		}
+	}
+	writeTable(fmt.Sprintf("Top %d most used classes", n), s.MostUsed(n))
+	writeTable(fmt.Sprintf("Top %d least used classes", n), s.LeastUsed(n))
+	writeTable(fmt.Sprintf("Singletons (%d classes used exactly once)", len(s.Singletons())), s.Singletons())
+
+	b.WriteString("\nHistogram (classes by number of uses)\n")
+	writeHistogram(&b, s.Histogram())
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeHistogram draws the buckets as text bars
+func writeHistogram(b *strings.Builder, buckets []HistogramBucket) {
+	labels := make([]string, len(buckets))
+	counts := make([]int, len(buckets))
+	for i, bucket := range buckets {
+		labels[i], counts[i] = bucket.Label, bucket.Classes
+	}
+	writeBars(b, labels, counts)
+}
+
+// writeBars draws text bars scaled so the largest count is 40 wide
+func writeBars(b *strings.Builder, labels []string, counts []int) {
+	const width = 40
+	largest := 0
+	for _, count := range counts {
+		largest = max(largest, count)
+	}
+	for i, count := range counts {
+		bar := 0
+		if largest > 0 {
+			bar = count * width / largest
+		}
+		if bar == 0 && count > 0 {
+			bar = 1
+		}
+		fmt.Fprintf(b, "  %8s | %-*s %d\n", labels[i], width, strings.Repeat("#", bar), count)
+	}
+}
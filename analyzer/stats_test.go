@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	files := map[string]string{
+		"a.html":     `<div class="flex p-2"><span class="flex text-sm"></span></div><script>document.querySelector('.flex').classList.add('p-2', 'is-open')</script>`,
+		"b/c.html":   `<div class="flex flex rounded"></div>`,
+		"b/d/e.html": `<p class="p-2"></p>`,
+	}
+	tempDir := writeProject(t, files)
+
+	project, err := Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %s", err)
+	}
+	stats := ComputeStats(project)
+
+	expected := map[string]ClassStats{
+		"flex":    {Class: "flex", Occurrences: 4, Files: 2, Elements: 3, FirstFile: "a.html", LastFile: filepath.Join("b", "c.html")},
+		"p-2":     {Class: "p-2", Occurrences: 2, Files: 2, Elements: 2, FirstFile: "a.html", LastFile: filepath.Join("b", "d", "e.html")},
+		"text-sm": {Class: "text-sm", Occurrences: 1, Files: 1, Elements: 1, FirstFile: "a.html", LastFile: "a.html"},
+		"rounded": {Class: "rounded", Occurrences: 1, Files: 1, Elements: 1, FirstFile: filepath.Join("b", "c.html"), LastFile: filepath.Join("b", "c.html")},
+	}
+	if len(stats.Classes) != len(expected) {
+		t.Fatalf("Expected %d classes, got %d", len(expected), len(stats.Classes))
+	}
+	for _, cs := range stats.Classes {
+		if cs != expected[cs.Class] {
+			t.Errorf("Expected %+v, got %+v", expected[cs.Class], cs)
+		}
+	}
+
+	if most := stats.MostUsed(1); len(most) != 1 || most[0].Class != "flex" {
+		t.Errorf("Expected flex to be the most used class, got %v", most)
+	}
+	if least := stats.LeastUsed(1); len(least) != 1 || least[0].Class != "text-sm" {
+		t.Errorf("Expected text-sm to be the least used class, got %v", least)
+	}
+	if most, least := stats.MostUsed(-1), stats.LeastUsed(-1); len(most) != 0 || len(least) != 0 {
+		t.Errorf("Expected no classes for a negative count, got %v and %v", most, least)
+	}
+	singletons := stats.Singletons()
+	if len(singletons) != 2 || singletons[0].Class != "rounded" || singletons[1].Class != "text-sm" {
+		t.Errorf("Expected rounded and text-sm to be singletons, got %v", singletons)
+	}
+	histogram := stats.Histogram()
+	if histogram[0].Classes != 2 || histogram[1].Classes != 1 || histogram[2].Classes != 1 {
+		t.Errorf("Unexpected histogram %v", histogram)
+	}
+
+	var report strings.Builder
+	if err := stats.WriteReport(&report, 3); err != nil {
+		t.Fatalf("WriteReport failed: %s", err)
+	}
+	if !strings.Contains(report.String(), "4 classes, 8 occurrences on 4 elements in 3 files") {
+		t.Errorf("Unexpected report header:\n%s", report.String())
+	}
+}
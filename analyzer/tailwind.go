@@ -0,0 +1,315 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Class is a class name split into the parts Tailwind reads out of it, for
+// "md:hover:!-mt-[3px]" that's the variants md and hover, the important
+// flag, the negative sign, the utility mt and the arbitrary value 3px
+type Class struct {
+	Raw       string   `json:"raw"`
+	Variants  []string `json:"variants,omitempty"`
+	Important bool     `json:"important,omitempty"`
+	Negative  bool     `json:"negative,omitempty"`
+	// Utility is the root of the utility, "bg" for "bg-red-500" and the whole
+	// name for static utilities like "flex" or "sr-only"
+	Utility string `json:"utility"`
+	Value   string `json:"value,omitempty"`
+	// Arbitrary holds the contents of a bracketed value like "40rem" for
+	// "ml-[40rem]", or the value of an arbitrary property
+	Arbitrary string `json:"arbitrary,omitempty"`
+	// Property is set for arbitrary properties like "[mask-type:luminance]"
+	Property string `json:"property,omitempty"`
+	// Modifier is what follows a top-level slash, "50" for "bg-black/50"
+	Modifier string `json:"modifier,omitempty"`
+	// Known reports whether Utility is a root Tailwind knows about
+	Known bool `json:"known"`
+}
+
+// Base returns the class without its variants, important flag and prefix
+func (c Class) Base() string {
+	var b strings.Builder
+	if c.Negative {
+		b.WriteByte('-')
+	}
+	if c.Property != "" {
+		fmt.Fprintf(&b, "[%s:%s]", c.Property, c.Arbitrary)
+	} else {
+		b.WriteString(c.Utility)
+		if c.Value != "" {
+			b.WriteString("-" + c.Value)
+		} else if c.Arbitrary != "" {
+			b.WriteString("-[" + c.Arbitrary + "]")
+		}
+	}
+	if c.Modifier != "" {
+		b.WriteString("/" + c.Modifier)
+	}
+	return b.String()
+}
+
+// ClassParser splits class names, Prefix and Separator mirror the options of
+// the same name in tailwind.config.js
+type ClassParser struct {
+	Prefix    string
+	Separator string
+}
+
+// DefaultParser parses classes for a Tailwind setup without a prefix
+var DefaultParser = &ClassParser{Separator: ":"}
+
+// ParseClass splits a class with the default parser
+func ParseClass(raw string) Class {
+	return DefaultParser.Parse(raw)
+}
+
+// Parse splits raw into its parts, classes that don't look like any known
+// utility come back with Known set to false and the whole base as Utility
+func (cp *ClassParser) Parse(raw string) Class {
+	c := Class{Raw: raw}
+	separator := cp.Separator
+	if separator == "" {
+		separator = ":"
+	}
+	parts := splitTopLevel(raw, separator)
+	c.Variants = parts[:len(parts)-1]
+	base := parts[len(parts)-1]
+
+	// v3 puts the important flag in front, v4 at the end
+	if strings.HasPrefix(base, "!") {
+		c.Important = true
+		base = base[1:]
+	} else if strings.HasSuffix(base, "!") {
+		c.Important = true
+		base = base[:len(base)-1]
+	}
+	if strings.HasPrefix(base, "-") {
+		c.Negative = true
+		base = base[1:]
+	}
+	if cp.Prefix != "" {
+		if !strings.HasPrefix(base, cp.Prefix) {
+			c.Utility = base
+			return c
+		}
+		base = strings.TrimPrefix(base, cp.Prefix)
+	}
+
+	// arbitrary properties are the only classes that start with a bracket
+	if strings.HasPrefix(base, "[") && strings.HasSuffix(base, "]") {
+		property, value, ok := strings.Cut(base[1:len(base)-1], ":")
+		if ok && property != "" {
+			c.Property, c.Arbitrary, c.Known = property, value, true
+			return c
+		}
+	}
+
+	base, c.Modifier = splitModifier(base)
+	c.Utility, c.Value, c.Known = splitRoot(base)
+	if isDigits(c.Value) && isDigits(c.Modifier) {
+		// fractions like "w-1/2" or "-left-1/4" are values, not modifiers
+		c.Value, c.Modifier = c.Value+"/"+c.Modifier, ""
+	}
+	if c.Known && strings.HasPrefix(c.Value, "[") && strings.HasSuffix(c.Value, "]") {
+		c.Arbitrary, c.Value = c.Value[1:len(c.Value)-1], ""
+	} else if c.Known && strings.HasPrefix(c.Value, "(") && strings.HasSuffix(c.Value, ")") {
+		// v4 shorthand for bg-[var(--brand)]
+		c.Arbitrary, c.Value = "var("+c.Value[1:len(c.Value)-1]+")", ""
+	}
+	return c
+}
+
+// splitRaw splits raw as written into its variants with their separators,
+// the important mark in front, the base and the important mark at the end,
+// so a rewrite of the base can put the rest back around it
+func (cp *ClassParser) splitRaw(raw string) (variants, lead, base, trail string) {
+	separator := cp.Separator
+	if separator == "" {
+		separator = ":"
+	}
+	parts := splitTopLevel(raw, separator)
+	n := len(raw) - len(parts[len(parts)-1])
+	variants, base = raw[:n], raw[n:]
+	if rest, ok := strings.CutPrefix(base, "!"); ok {
+		lead, base = "!", rest
+	} else if rest, ok := strings.CutSuffix(base, "!"); ok {
+		base, trail = rest, "!"
+	}
+	return variants, lead, base, trail
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside brackets,
+// parentheses and quotes, which arbitrary values and variants use a lot
+func splitTopLevel(s, sep string) []string {
+	var parts []string
+	depth := 0
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '[' || ch == '(':
+			depth++
+		case ch == ']' || ch == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// splitModifier cuts a top-level "/modifier" off the base
+func splitModifier(base string) (string, string) {
+	parts := splitTopLevel(base, "/")
+	if len(parts) != 2 || parts[1] == "" {
+		return base, ""
+	}
+	return parts[0], parts[1]
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// splitRoot finds the longest root of the catalog the base starts with,
+// roots end at a dash so "grid-cols-3" is grid-cols and 3, never grid and cols-3
+func splitRoot(base string) (root, value string, known bool) {
+	if _, ok := utilities[base]; ok {
+		return base, "", true
+	}
+	limit := strings.IndexAny(base, "[(")
+	if limit < 0 {
+		limit = len(base)
+	}
+	for i := limit - 1; i > 0; i-- {
+		if base[i] != '-' {
+			continue
+		}
+		if _, ok := utilities[base[:i]]; ok && i+1 < len(base) {
+			return base[:i], base[i+1:], true
+		}
+	}
+	return base, "", false
+}
+
+// Rollup counts how often a variant or utility root is used and by how many
+// distinct classes
+type Rollup struct {
+	Name        string `json:"name"`
+	Occurrences int    `json:"occurrences"`
+	Classes     int    `json:"classes"`
+}
+
+// Rollups groups the usages of a project by variant and by utility root
+type Rollups struct {
+	Variants  []Rollup `json:"variants"`
+	Utilities []Rollup `json:"utilities"`
+	// Unknown counts the classes that aren't Tailwind utilities
+	Unknown Rollup `json:"unknown"`
+}
+
+// ComputeRollups parses every class of the project once and adds its usages
+// to the variants and utility root it's made of
+func ComputeRollups(p *Project, parser *ClassParser) *Rollups {
+	counts := make(map[string]int)
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			if element.Provenance == ProvenanceJS {
+				continue
+			}
+			for _, token := range element.Classes {
+				counts[token.Name]++
+			}
+		}
+	}
+
+	rollups := &Rollups{Unknown: Rollup{Name: "(unknown)"}}
+	variants := make(map[string]*Rollup)
+	utilities := make(map[string]*Rollup)
+	add := func(m map[string]*Rollup, name string, occurrences int) {
+		r, exists := m[name]
+		if !exists {
+			r = &Rollup{Name: name}
+			m[name] = r
+		}
+		r.Occurrences += occurrences
+		r.Classes++
+	}
+	for name, occurrences := range counts {
+		c := parser.Parse(name)
+		for _, variant := range c.Variants {
+			add(variants, variant, occurrences)
+		}
+		switch {
+		case !c.Known:
+			rollups.Unknown.Occurrences += occurrences
+			rollups.Unknown.Classes++
+		case c.Property != "":
+			add(utilities, "["+c.Property+"]", occurrences)
+		default:
+			add(utilities, c.Utility, occurrences)
+		}
+	}
+
+	rollups.Variants = sortedRollups(variants)
+	rollups.Utilities = sortedRollups(utilities)
+	return rollups
+}
+
+func sortedRollups(m map[string]*Rollup) []Rollup {
+	var sorted []Rollup
+	for _, r := range m {
+		sorted = append(sorted, *r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Occurrences != sorted[j].Occurrences {
+			return sorted[i].Occurrences > sorted[j].Occurrences
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// WriteReport lists the variant and utility rollups, at most n of each
+func (r *Rollups) WriteReport(w io.Writer, n int) error {
+	var b strings.Builder
+	writeTable := func(title string, rollups []Rollup) {
+		fmt.Fprintf(&b, "\n%s\n", title)
+		if len(rollups) == 0 {
+			b.WriteString("  (none)\n")
+			return
+		}
+		fmt.Fprintf(&b, "  %-40s %8s %8s\n", "name", "uses", "classes")
+		for _, rollup := range rollups[:max(0, min(n, len(rollups)))] {
+			fmt.Fprintf(&b, "  %-40s %8d %8d\n", rollup.Name, rollup.Occurrences, rollup.Classes)
+		}
+	}
+	writeTable(fmt.Sprintf("Top %d variants (of %d)", n, len(r.Variants)), r.Variants)
+	writeTable(fmt.Sprintf("Top %d utilities (of %d)", n, len(r.Utilities)), r.Utilities)
+	fmt.Fprintf(&b, "\nNon-utility classes: %d, used %d times\n", r.Unknown.Classes, r.Unknown.Occurrences)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
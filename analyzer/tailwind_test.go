@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseClass(t *testing.T) {
+	tests := []Class{
+		{Raw: "flex", Utility: "flex", Known: true},
+		{Raw: "flex-col", Utility: "flex", Value: "col", Known: true},
+		{Raw: "hover:bg-blue-700", Variants: []string{"hover"}, Utility: "bg", Value: "blue-700", Known: true},
+		{Raw: "disabled:bg-gray-500", Variants: []string{"disabled"}, Utility: "bg", Value: "gray-500", Known: true},
+		{Raw: "md:hover:dark:text-sm", Variants: []string{"md", "hover", "dark"}, Utility: "text", Value: "sm", Known: true},
+		{Raw: "-ml-[40rem]", Negative: true, Utility: "ml", Arbitrary: "40rem", Known: true},
+		{Raw: "-left-1/4", Negative: true, Utility: "left", Value: "1/4", Known: true},
+		{Raw: "-mb-px", Negative: true, Utility: "mb", Value: "px", Known: true},
+		{Raw: "grid-cols-3", Utility: "grid-cols", Value: "3", Known: true},
+		{Raw: "bg-red-500/50", Utility: "bg", Value: "red-500", Modifier: "50", Known: true},
+		{Raw: "bg-black/[0.35]", Utility: "bg", Value: "black", Modifier: "[0.35]", Known: true},
+		{Raw: "text-2xl/7", Utility: "text", Value: "2xl", Modifier: "7", Known: true},
+		{Raw: "md:!font-bold", Variants: []string{"md"}, Important: true, Utility: "font", Value: "bold", Known: true},
+		{Raw: "font-bold!", Important: true, Utility: "font", Value: "bold", Known: true},
+		{Raw: "[mask-type:luminance]", Property: "mask-type", Arbitrary: "luminance", Known: true},
+		{Raw: "[&>*:hover]:p-4", Variants: []string{"[&>*:hover]"}, Utility: "p", Value: "4", Known: true},
+		{Raw: "data-[state=open]:w-[calc(100%-2rem)]", Variants: []string{"data-[state=open]"}, Utility: "w", Arbitrary: "calc(100%-2rem)", Known: true},
+		{Raw: "bg-(--brand)", Utility: "bg", Arbitrary: "var(--brand)", Known: true},
+		{Raw: "sr-only", Utility: "sr-only", Known: true},
+		{Raw: "btn-primary", Utility: "btn-primary"},
+	}
+	for _, expected := range tests {
+		received := ParseClass(expected.Raw)
+		if !slices.Equal(received.Variants, expected.Variants) {
+			t.Errorf("%s: expected variants %v, got %v", expected.Raw, expected.Variants, received.Variants)
+		}
+		if received.Important != expected.Important || received.Negative != expected.Negative ||
+			received.Utility != expected.Utility || received.Value != expected.Value ||
+			received.Arbitrary != expected.Arbitrary || received.Property != expected.Property ||
+			received.Modifier != expected.Modifier || received.Known != expected.Known {
+			t.Errorf("%s: expected %+v, got %+v", expected.Raw, expected, received)
+		}
+	}
+}
+
+func TestParseClassPrefixAndSeparator(t *testing.T) {
+	parser := &ClassParser{Prefix: "tw-", Separator: "_"}
+
+	c := parser.Parse("md_hover_!-tw-mt-2")
+	if !slices.Equal(c.Variants, []string{"md", "hover"}) || !c.Important || !c.Negative || c.Utility != "mt" || c.Value != "2" {
+		t.Errorf("Unexpected parse %+v", c)
+	}
+	if c.Base() != "-mt-2" {
+		t.Errorf("Expected base -mt-2, got %s", c.Base())
+	}
+	if c := parser.Parse("mt-2"); c.Known {
+		t.Errorf("Expected classes without the prefix to be unknown, got %+v", c)
+	}
+}
+
+func TestComputeRollups(t *testing.T) {
+	sampleHTML := `<div class="p-2 hover:p-4 md:hover:bg-red-500 btn"><p class="p-2 md:flex"></p></div><script>el.classList.add("hover:p-4", "grid")</script>`
+	tempDir := writeProject(t, map[string]string{"sample.html": sampleHTML})
+	project, err := Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %s", err)
+	}
+	rollups := ComputeRollups(project, DefaultParser)
+
+	expectedVariants := []Rollup{{"hover", 2, 2}, {"md", 2, 2}}
+	if !slices.Equal(rollups.Variants, expectedVariants) {
+		t.Errorf("Expected variants %v, got %v", expectedVariants, rollups.Variants)
+	}
+	expectedUtilities := []Rollup{{"p", 3, 2}, {"bg", 1, 1}, {"flex", 1, 1}}
+	if !slices.Equal(rollups.Utilities, expectedUtilities) {
+		t.Errorf("Expected utilities %v, got %v", expectedUtilities, rollups.Utilities)
+	}
+	if rollups.Unknown.Classes != 1 || rollups.Unknown.Occurrences != 1 {
+		t.Errorf("Expected btn to be the only unknown class, got %+v", rollups.Unknown)
+	}
+
+	var report strings.Builder
+	if err := rollups.WriteReport(&report, -1); err != nil {
+		t.Fatalf("WriteReport failed: %s", err)
+	}
+	if strings.Contains(report.String(), "hover") {
+		t.Errorf("Expected no rollups listed for a negative count, got\n%s", report.String())
+	}
+}
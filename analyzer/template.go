@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"bytes"
+)
+
+// templateSyntax is how a template language sets its code apart from the
+// markup around it
+type templateSyntax int
+
+const (
+	// mustacheSyntax is the {{ }} of Vue, Handlebars, Mustache and Go
+	// templates, Handlebars' raw {{{ }}} included
+	mustacheSyntax templateSyntax = iota
+	// braceSyntax is the { } of Svelte, which nests like JavaScript
+	braceSyntax
+)
+
+func init() {
+	for _, ext := range []string{".vue", ".hbs", ".handlebars", ".mustache", ".tmpl", ".gohtml"} {
+		RegisterExtractor(ext, templateExtractor(mustacheSyntax))
+	}
+	RegisterExtractor(".svelte", templateExtractor(braceSyntax))
+}
+
+// templateExtractor reads templates like HTML pages. Their code is hidden
+// from the HTML tokenizer so that a > or a quote in it doesn't end a tag
+// or an attribute, and the classes with code in them, like btn-{{ size }},
+// keep it in their names so the commands rewriting classes leave them be.
+// <script> and <style> blocks are read as they are
+func templateExtractor(syntax templateSyntax) Extractor {
+	return func(path string, src []byte) (*SourceFile, error) {
+		spans := templateSpans(src, syntax)
+		if len(spans) == 0 {
+			return extractHTML(path, src)
+		}
+		masked := append([]byte(nil), src...)
+		for _, span := range spans {
+			for i := span[0]; i < span[1]; i++ {
+				// newlines stay so the positions don't move
+				if masked[i] != '\n' {
+					masked[i] = '_'
+				}
+			}
+		}
+		file, err := extractHTML(path, masked)
+		if err != nil {
+			return nil, err
+		}
+		for _, element := range file.Elements {
+			for i, token := range element.Classes {
+				if overlapsSpan(spans, token.Pos.Offset, token.End) {
+					element.Classes[i].Name = string(src[token.Pos.Offset:token.End])
+				}
+			}
+		}
+		if file.Stylesheet != nil {
+			file.Stylesheet.Source = string(src)
+		}
+		return file, nil
+	}
+}
+
+// templateSpans returns where the template code of src is, outside of
+// <script> and <style> blocks. Code that isn't closed runs to the end
+func templateSpans(src []byte, syntax templateSyntax) [][2]int {
+	var spans [][2]int
+	text := string(src)
+	for i := 0; i < len(src); {
+		if end, ok := rawTextEnd(src, i); ok {
+			i = end
+			continue
+		}
+		end := -1
+		switch {
+		case syntax == mustacheSyntax && bytes.HasPrefix(src[i:], []byte("{{")):
+			closing := []byte("}}")
+			if bytes.HasPrefix(src[i:], []byte("{{{")) {
+				closing = []byte("}}}")
+			}
+			if n := bytes.Index(src[i+2:], closing); n >= 0 {
+				end = i + 2 + n + len(closing)
+			}
+		case syntax == braceSyntax && src[i] == '{':
+			end = matchBracket(text, i)
+		default:
+			i++
+			continue
+		}
+		if end < 0 {
+			end = len(src)
+		}
+		spans = append(spans, [2]int{i, end})
+		i = end
+	}
+	return spans
+}
+
+// rawTextEnd returns the offset after the <script> or <style> block
+// starting at src[i], whose contents aren't markup
+func rawTextEnd(src []byte, i int) (int, bool) {
+	for _, tag := range []string{"script", "style"} {
+		open := len(tag) + 1
+		if len(src) <= i+open || src[i] != '<' || !bytes.EqualFold(src[i+1:i+open], []byte(tag)) {
+			continue
+		}
+		if next := src[i+open]; next != '>' && next != '/' && !isHTMLSpace(next) {
+			continue
+		}
+		closing := []byte("</" + tag)
+		for j := i + open; j+len(closing) <= len(src); j++ {
+			if bytes.EqualFold(src[j:j+len(closing)], closing) {
+				return j + len(closing), true
+			}
+		}
+		return len(src), true
+	}
+	return 0, false
+}
+
+func overlapsSpan(spans [][2]int, start, end int) bool {
+	for _, span := range spans {
+		if start < span[1] && span[0] < end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTemplateExtractors(t *testing.T) {
+	files := map[string]string{
+		"Card.vue": `<template>
+  <div class="p-4 card" :class="{ active: isOn }">{{ count > 1 ? 'many' : "one" }}</div>
+</template>
+<script setup>
+const state = { open: true }
+</script>
+<style scoped>
+.card { color: red }
+</style>`,
+		"button.hbs": `<a class="btn btn-{{size}} {{#if on}}active{{/if}}" title="{{t "a>b"}}">Go</a>`,
+		"Toggle.svelte": `<button class="px-4 {on ? 'on' : ''}" on:click={() => n > 1}>{#if on}<b class="font-bold">y</b>{/if}</button>
+<style>.on { color: red }</style>`,
+		"page.tmpl": `{{define "page"}}<p class="{{.Class}} text-sm">{{.Text}}</p>{{end}}`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Card.vue":      "p-4 card",
+		"button.hbs":    "btn btn-{{size}} {{#if on}}active{{/if}}",
+		"Toggle.svelte": "px-4 {on ? 'on' : ''} | font-bold",
+		"page.tmpl":     "{{.Class}} text-sm",
+	}
+	if len(project.Files) != len(expected) {
+		t.Fatalf("Expected %d files, got %d", len(expected), len(project.Files))
+	}
+	for _, file := range project.Files {
+		var elements []string
+		for _, element := range file.Elements {
+			var names []string
+			for _, token := range element.Classes {
+				names = append(names, token.Name)
+				if src := files[file.Path]; src[token.Pos.Offset:token.End] != token.Name {
+					t.Errorf("%s: expected the offsets to point at %s, got %q", file.Path, token.Name, src[token.Pos.Offset:token.End])
+				}
+			}
+			elements = append(elements, strings.Join(names, " "))
+		}
+		if got := strings.Join(elements, " | "); got != expected[file.Path] {
+			t.Errorf("%s: expected the classes %q, got %q", file.Path, expected[file.Path], got)
+		}
+	}
+
+	var sheets []string
+	for _, sheet := range project.Stylesheets {
+		sheets = append(sheets, sheet.Path)
+	}
+	slices.Sort(sheets)
+	if !slices.Equal(sheets, []string{"Card.vue", "Toggle.svelte"}) {
+		t.Errorf("Expected the style blocks of the components, got %v", sheets)
+	}
+
+	// only the attribute without template code can be put in order
+	findings := ClassOrder(project, DefaultParser, NewCatalog())
+	if len(findings) != 1 || findings[0].Pos.File != "Card.vue" {
+		t.Errorf("Expected one order finding in Card.vue, got %v", findings)
+	}
+}
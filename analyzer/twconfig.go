@@ -0,0 +1,667 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TailwindConfig is what can be read out of a tailwind.config file without
+// running it, anything that needs Node to evaluate ends up in Warnings
+type TailwindConfig struct {
+	Path string `json:"path"`
+	// Content are the globs Tailwind scans for classes, relative to the
+	// config file, negated globs end up in Exclude
+	Content   []string `json:"content"`
+	Exclude   []string `json:"exclude,omitempty"`
+	Prefix    string   `json:"prefix,omitempty"`
+	Separator string   `json:"separator,omitempty"`
+	// Important is "true", "false" or the selector important is scoped to
+	Important string          `json:"important,omitempty"`
+	Safelist  []SafelistEntry `json:"safelist,omitempty"`
+	// Theme holds the keys of theme and theme.extend by theme key, nested
+	// keys are flattened the way Tailwind names them ("brand-light")
+	Theme map[string][]string `json:"theme,omitempty"`
+	// Roots, Utilities, Variants and Legacy only come from v4 entry
+	// stylesheets: directories @source adds outside the project, the names
+	// of @utility and @custom-variant and the file @config points at
+	Roots     []string `json:"roots,omitempty"`
+	Utilities []string `json:"utilities,omitempty"`
+	Variants  []string `json:"variants,omitempty"`
+	Legacy    string   `json:"legacy,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// SafelistEntry is either an exact class or a pattern of classes that must
+// be kept, optionally combined with variants
+type SafelistEntry struct {
+	Class    string         `json:"class,omitempty"`
+	Pattern  *regexp.Regexp `json:"-"`
+	Variants []string       `json:"variants,omitempty"`
+}
+
+// Matches reports whether class is safelisted by the entry, separator is
+// the one between variants and the utility, ":" when empty
+func (s SafelistEntry) Matches(class, separator string) bool {
+	if s.Pattern == nil {
+		return class == s.Class
+	}
+	if separator == "" {
+		separator = ":"
+	}
+	// patterns match the utility, variants only the ones listed with it
+	if i := strings.LastIndex(class, separator); i >= 0 {
+		variant := class[:i]
+		if !slices.Contains(s.Variants, variant) {
+			return false
+		}
+		class = class[i+len(separator):]
+	}
+	return s.Pattern.MatchString(class)
+}
+
+var tailwindConfigNames = []string{"tailwind.config.js", "tailwind.config.cjs", "tailwind.config.mjs", "tailwind.config.ts"}
+
+// LoadTailwindConfig reads the Tailwind configuration of the project in dir,
+// a v4 entry stylesheet wins over a tailwind.config file, it returns nil
+// without an error when there's neither
+func LoadTailwindConfig(dir string) (*TailwindConfig, error) {
+	entry, err := findTailwindEntry(dir)
+	if err != nil {
+		return nil, err
+	}
+	if entry != "" {
+		src, err := os.ReadFile(entry)
+		if err != nil {
+			return nil, err
+		}
+		sheet, err := ParseCSS(relPath(dir, entry), src)
+		if err != nil {
+			return nil, err
+		}
+		config := ParseTailwindCSS(sheet)
+		if config.Legacy != "" {
+			legacy := filepath.Join(dir, filepath.FromSlash(config.Legacy))
+			src, err := os.ReadFile(legacy)
+			if err != nil {
+				return nil, err
+			}
+			config.merge(ParseTailwindConfig(legacy, src))
+		}
+		return config, nil
+	}
+
+	for _, name := range tailwindConfigNames {
+		path := filepath.Join(dir, name)
+		src, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return ParseTailwindConfig(path, src), nil
+	}
+	return nil, nil
+}
+
+// ParseTailwindConfig statically evaluates the object literal exported by a
+// tailwind.config file, it never fails but collects warnings instead
+func ParseTailwindConfig(path string, src []byte) *TailwindConfig {
+	config := &TailwindConfig{Path: path, Theme: make(map[string][]string)}
+	p := &jsParser{src: string(src), lines: newLineIndex(src)}
+	p.warn = func(line int, format string, args ...any) { config.warn(path, line, format, args...) }
+	root := p.exportedObject()
+	if root == nil {
+		config.warn(path, 1, "can't find the exported config object")
+		return config
+	}
+	if root.kind != jsObject {
+		config.warn(path, root.line, "can't evaluate the exported config %q statically", root.raw)
+		return config
+	}
+
+	if content := root.field("content"); content != nil {
+		files := content
+		if content.kind == jsObject {
+			files = content.field("files")
+		}
+		if files == nil || files.kind != jsArray {
+			config.warn(path, content.line, "can't evaluate content %q statically", content.raw)
+		} else {
+			for _, item := range files.items {
+				switch {
+				case item.kind != jsString:
+					config.warn(path, item.line, "can't evaluate content entry %q statically", item.raw)
+				case strings.HasPrefix(item.str, "!"):
+					config.Exclude = append(config.Exclude, cleanGlob(item.str[1:]))
+				default:
+					config.Content = append(config.Content, cleanGlob(item.str))
+				}
+			}
+		}
+	}
+
+	config.Prefix = config.stringField(root, "prefix")
+	config.Separator = config.stringField(root, "separator")
+	if important := root.field("important"); important != nil {
+		switch important.kind {
+		case jsBool, jsString:
+			config.Important = important.str
+		default:
+			config.warn(path, important.line, "can't evaluate important %q statically", important.raw)
+		}
+	}
+
+	if safelist := root.field("safelist"); safelist != nil {
+		if safelist.kind != jsArray {
+			config.warn(path, safelist.line, "can't evaluate safelist %q statically", safelist.raw)
+		}
+		for _, item := range safelist.items {
+			config.addSafelistEntry(item)
+		}
+	}
+
+	if theme := root.field("theme"); theme != nil {
+		config.readTheme(theme)
+		if extend := theme.field("extend"); extend != nil {
+			config.readTheme(extend)
+		}
+	}
+	return config
+}
+
+func (tc *TailwindConfig) warn(path string, line int, format string, args ...any) {
+	tc.Warnings = append(tc.Warnings, fmt.Sprintf("%s:%d: %s", path, line, fmt.Sprintf(format, args...)))
+}
+
+func (tc *TailwindConfig) stringField(object *jsValue, key string) string {
+	value := object.field(key)
+	if value == nil {
+		return ""
+	}
+	if value.kind != jsString {
+		tc.warn(tc.Path, value.line, "can't evaluate %s %q statically", key, value.raw)
+		return ""
+	}
+	return value.str
+}
+
+func (tc *TailwindConfig) addSafelistEntry(item *jsValue) {
+	switch item.kind {
+	case jsString:
+		tc.Safelist = append(tc.Safelist, SafelistEntry{Class: item.str})
+		return
+	case jsObject:
+		pattern := item.field("pattern")
+		if pattern != nil && pattern.kind == jsRegexp {
+			re, err := regexp.Compile(pattern.str)
+			if err != nil {
+				tc.warn(tc.Path, pattern.line, "can't compile safelist pattern %s: %s", pattern.raw, err)
+				return
+			}
+			entry := SafelistEntry{Pattern: re}
+			if variants := item.field("variants"); variants != nil {
+				for _, v := range variants.items {
+					if v.kind == jsString {
+						entry.Variants = append(entry.Variants, v.str)
+					}
+				}
+			}
+			tc.Safelist = append(tc.Safelist, entry)
+			return
+		}
+	}
+	tc.warn(tc.Path, item.line, "can't evaluate safelist entry %q statically", item.raw)
+}
+
+// readTheme collects the keys of every theme section, functions like
+// theme: ({ theme }) => ... can't be followed
+func (tc *TailwindConfig) readTheme(theme *jsValue) {
+	if theme.kind != jsObject {
+		tc.warn(tc.Path, theme.line, "can't evaluate theme %q statically", theme.raw)
+		return
+	}
+	for _, key := range theme.keys {
+		if key == "extend" {
+			continue
+		}
+		section := theme.fields[key]
+		if section.kind != jsObject {
+			tc.warn(tc.Path, section.line, "can't evaluate theme.%s %q statically", key, section.raw)
+			continue
+		}
+		tc.Theme[key] = append(tc.Theme[key], flattenThemeKeys("", section)...)
+	}
+}
+
+// flattenThemeKeys names nested theme values the way utilities use them,
+// { brand: { DEFAULT, light } } is "brand" and "brand-light"
+func flattenThemeKeys(prefix string, object *jsValue) []string {
+	var keys []string
+	for _, key := range object.keys {
+		name := key
+		if prefix != "" {
+			name = prefix + "-" + key
+			if key == "DEFAULT" {
+				name = prefix
+			}
+		} else if key == "DEFAULT" {
+			continue
+		}
+		if value := object.fields[key]; value.kind == jsObject {
+			keys = append(keys, flattenThemeKeys(name, value)...)
+			continue
+		}
+		keys = append(keys, name)
+	}
+	return keys
+}
+
+func cleanGlob(glob string) string {
+	return filepath.ToSlash(filepath.Clean(filepath.FromSlash(glob)))
+}
+
+// Parser returns a class parser using the config's prefix and separator
+func (tc *TailwindConfig) Parser() *ClassParser {
+	if tc == nil {
+		return DefaultParser
+	}
+	parser := &ClassParser{Prefix: tc.Prefix, Separator: tc.Separator}
+	if parser.Separator == "" {
+		parser.Separator = ":"
+	}
+	return parser
+}
+
+// ExtendCatalog adds the theme keys and custom utilities and variants of
+// the config to the catalog
+func (tc *TailwindConfig) ExtendCatalog(c *Catalog) {
+	if tc == nil {
+		return
+	}
+	for _, utility := range tc.Utilities {
+		c.AddUtility(utility)
+	}
+	for _, variant := range tc.Variants {
+		c.AddVariant(variant)
+	}
+	keys := make([]string, 0, len(tc.Theme))
+	for key := range tc.Theme {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c.Extend(key, tc.Theme[key]...)
+	}
+}
+
+// Safelisted reports whether the config's safelist keeps class
+func (tc *TailwindConfig) Safelisted(class string) bool {
+	if tc == nil {
+		return false
+	}
+	for _, entry := range tc.Safelist {
+		if entry.Matches(class, tc.Separator) {
+			return true
+		}
+	}
+	return false
+}
+
+type jsKind int
+
+const (
+	jsUnknown jsKind = iota
+	jsObject
+	jsArray
+	jsString
+	jsNumber
+	jsBool
+	jsRegexp
+)
+
+// jsValue is a value of a JavaScript object literal, anything that isn't a
+// literal is jsUnknown and only keeps its source text
+type jsValue struct {
+	kind   jsKind
+	str    string // strings, numbers, booleans and the source of regexps
+	keys   []string
+	fields map[string]*jsValue
+	items  []*jsValue
+	raw    string
+	line   int
+}
+
+func (v *jsValue) field(key string) *jsValue {
+	if v == nil || v.kind != jsObject {
+		return nil
+	}
+	return v.fields[key]
+}
+
+// jsParser reads JavaScript and TypeScript object literals, it knows just
+// enough of the language to skip over what it can't evaluate
+type jsParser struct {
+	src   string
+	lines lineIndex
+	i     int
+	// warn is told about what the parser drops, like spreads
+	warn func(line int, format string, args ...any)
+}
+
+var (
+	exportPattern   = regexp.MustCompile(`(?:module\.exports\s*=|export\s+default)\s*`)
+	defineConfigRe  = regexp.MustCompile(`^[A-Za-z_$][\w$]*\s*\(`)
+	identifierRegex = regexp.MustCompile(`^[A-Za-z_$][\w$]*`)
+)
+
+// exportedObject finds the value of module.exports or export default,
+// following a defineConfig() call or a variable holding the config
+func (p *jsParser) exportedObject() *jsValue {
+	match := exportPattern.FindStringIndex(p.src)
+	if match == nil {
+		return nil
+	}
+	p.i = match[1]
+	if call := defineConfigRe.FindString(p.src[p.i:]); call != "" {
+		p.i += len(call)
+	}
+	p.skipSpace()
+	if name := identifierRegex.FindString(p.src[p.i:]); name != "" && p.peekAfter(name) != '(' {
+		declaration := regexp.MustCompile(`(?:const|let|var)\s+` + regexp.QuoteMeta(name) + `\s*(?::[^=]+)?=\s*`)
+		if m := declaration.FindStringIndex(p.src); m != nil {
+			p.i = m[1]
+		}
+	}
+	return p.parseValue()
+}
+
+func (p *jsParser) peekAfter(word string) byte {
+	j := p.i + len(word)
+	for j < len(p.src) && isJSSpace(p.src[j]) {
+		j++
+	}
+	if j < len(p.src) {
+		return p.src[j]
+	}
+	return 0
+}
+
+func isJSSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+func (p *jsParser) skipSpace() {
+	for p.i < len(p.src) {
+		switch {
+		case isJSSpace(p.src[p.i]):
+			p.i++
+		case strings.HasPrefix(p.src[p.i:], "//"):
+			for p.i < len(p.src) && p.src[p.i] != '\n' {
+				p.i++
+			}
+		case strings.HasPrefix(p.src[p.i:], "/*"):
+			end := strings.Index(p.src[p.i+2:], "*/")
+			if end < 0 {
+				p.i = len(p.src)
+			} else {
+				p.i += end + 4
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsParser) parseValue() *jsValue {
+	p.skipSpace()
+	start := p.i
+	v := &jsValue{line: p.lines.position("", start).Line}
+	if p.i >= len(p.src) {
+		return v
+	}
+	switch ch := p.src[p.i]; {
+	case ch == '{':
+		p.parseObject(v)
+	case ch == '[':
+		p.parseArray(v)
+	case ch == '"' || ch == '\'' || ch == '`':
+		str, ok := p.parseString()
+		if ok {
+			v.kind, v.str = jsString, str
+		}
+	case ch == '/':
+		p.parseRegexp(v)
+	case ch == '-' || ch == '.' || (ch >= '0' && ch <= '9'):
+		for p.i < len(p.src) && strings.IndexByte("+-.0123456789abcdefxABCDEFX_", p.src[p.i]) >= 0 {
+			p.i++
+		}
+		v.kind, v.str = jsNumber, p.src[start:p.i]
+	default:
+		if word := identifierRegex.FindString(p.src[p.i:]); (word == "true" || word == "false") && !p.continuesExpression(word) {
+			p.i += len(word)
+			v.kind, v.str = jsBool, word
+		}
+	}
+	if v.kind != jsUnknown {
+		// "as const", "satisfies Config" and friends don't change the value
+		p.skipSpace()
+		if !p.atValueEnd() {
+			v.kind = jsUnknown
+		}
+	}
+	if v.kind == jsUnknown {
+		p.skipExpression()
+	}
+	v.raw = strings.TrimSpace(p.src[start:p.i])
+	return v
+}
+
+// continuesExpression tells apart a literal from the start of an expression
+// like true && x
+func (p *jsParser) continuesExpression(word string) bool {
+	next := p.peekAfter(word)
+	return next != ',' && next != '}' && next != ']' && next != ';' && next != 0 && next != ')'
+}
+
+func (p *jsParser) atValueEnd() bool {
+	if p.i >= len(p.src) {
+		return true
+	}
+	switch p.src[p.i] {
+	case ',', '}', ']', ';', ')':
+		return true
+	}
+	rest := p.src[p.i:]
+	return strings.HasPrefix(rest, "satisfies ") || strings.HasPrefix(rest, "as ")
+}
+
+// skipExpression moves past an expression it can't evaluate, up to the next
+// top-level comma or closing bracket
+func (p *jsParser) skipExpression() {
+	depth := 0
+	// a '/' after an operator or an opening bracket starts a regexp, after
+	// a name, a number or a closing bracket it divides
+	var prev byte
+	for p.i < len(p.src) {
+		p.skipSpace()
+		if p.i >= len(p.src) {
+			return
+		}
+		ch := p.src[p.i]
+		if ch == '/' && (prev == 0 || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", prev) >= 0) {
+			p.parseRegexp(&jsValue{})
+			prev = '/'
+			continue
+		}
+		prev = ch
+		switch ch {
+		case '"', '\'', '`':
+			p.parseString()
+			continue
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			if depth == 0 {
+				return
+			}
+			depth--
+		case ',', ';':
+			if depth == 0 {
+				return
+			}
+		}
+		p.i++
+	}
+}
+
+func (p *jsParser) parseObject(v *jsValue) {
+	v.kind, v.fields = jsObject, make(map[string]*jsValue)
+	p.i++
+	for start := -1; ; {
+		p.skipSpace()
+		if p.i >= len(p.src) || p.i == start {
+			p.fail(v)
+			return
+		}
+		start = p.i
+		switch p.src[p.i] {
+		case '}':
+			p.i++
+			return
+		case ',':
+			p.i++
+			continue
+		}
+
+		var key string
+		switch ch := p.src[p.i]; {
+		case ch == '"' || ch == '\'' || ch == '`':
+			key, _ = p.parseString()
+		case ch == '[' || strings.HasPrefix(p.src[p.i:], "..."):
+			// computed keys and spreads can't be evaluated
+			start, what := p.i, "computed key"
+			if ch == '.' {
+				what = "spread"
+			}
+			p.skipExpression()
+			if p.warn != nil {
+				p.warn(p.lines.position("", start).Line, "can't evaluate %s %q statically, it is left out", what, strings.TrimSpace(p.src[start:p.i]))
+			}
+			continue
+		default:
+			// identifiers and numeric keys like 500
+			start := p.i
+			for p.i < len(p.src) && strings.IndexByte(" \t\r\n:,}(", p.src[p.i]) < 0 {
+				p.i++
+			}
+			key = p.src[start:p.i]
+		}
+		p.skipSpace()
+		if p.i < len(p.src) && p.src[p.i] == ':' {
+			p.i++
+			v.keys = append(v.keys, key)
+			v.fields[key] = p.parseValue()
+			continue
+		}
+		// shorthand properties and methods
+		p.skipExpression()
+	}
+}
+
+func (p *jsParser) parseArray(v *jsValue) {
+	v.kind = jsArray
+	p.i++
+	for start := -1; ; {
+		p.skipSpace()
+		if p.i >= len(p.src) || p.i == start {
+			p.fail(v)
+			return
+		}
+		start = p.i
+		switch p.src[p.i] {
+		case ']':
+			p.i++
+			return
+		case ',':
+			p.i++
+			continue
+		}
+		v.items = append(v.items, p.parseValue())
+	}
+}
+
+// fail gives up on v when the parser is lost, like at a stray ) it can't
+// move past, the rest of the source can't be read statically either
+func (p *jsParser) fail(v *jsValue) {
+	v.kind = jsUnknown
+	p.i = len(p.src)
+}
+
+// parseString reads a quoted string, template literals with substitutions
+// aren't static and come back not ok
+func (p *jsParser) parseString() (string, bool) {
+	quote := p.src[p.i]
+	p.i++
+	var b strings.Builder
+	ok := true
+	for p.i < len(p.src) && p.src[p.i] != quote {
+		ch := p.src[p.i]
+		if ch == '\\' && p.i+1 < len(p.src) {
+			p.i++
+			switch p.src[p.i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(p.src[p.i])
+			}
+			p.i++
+			continue
+		}
+		if quote == '`' && strings.HasPrefix(p.src[p.i:], "${") {
+			ok = false
+		}
+		b.WriteByte(ch)
+		p.i++
+	}
+	p.i++
+	return b.String(), ok
+}
+
+func (p *jsParser) parseRegexp(v *jsValue) {
+	p.i++
+	start := p.i
+	inClass := false
+	for p.i < len(p.src) && p.src[p.i] != '\n' {
+		ch := p.src[p.i]
+		if ch == '\\' {
+			p.i += 2
+			continue
+		}
+		if ch == '[' {
+			inClass = true
+		} else if ch == ']' {
+			inClass = false
+		} else if ch == '/' && !inClass {
+			break
+		}
+		p.i++
+	}
+	if p.i >= len(p.src) || p.src[p.i] != '/' {
+		return
+	}
+	v.kind, v.str = jsRegexp, p.src[start:p.i]
+	p.i++
+	flags := identifierRegex.FindString(p.src[p.i:])
+	p.i += len(flags)
+	if strings.Contains(flags, "i") {
+		v.str = "(?i)" + v.str
+	}
+}
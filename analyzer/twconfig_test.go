@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testTailwindConfig = `/** @type {import('tailwindcss').Config} */
+const defaultTheme = require('tailwindcss/defaultTheme')
+
+module.exports = {
+  content: ['./src/**/*.{html,js}', "!./src/legacy/**"],
+  prefix: 'tw-',
+  separator: '_',
+  important: '#app',
+  safelist: [
+    'bg-red-500',
+    { pattern: /bg-(red|green)-(100|200)/, variants: ['hover'] },
+  ],
+  theme: {
+    screens: { tablet: '640px', desktop: '1024px' },
+    extend: {
+      colors: {
+        brand: { DEFAULT: '#0af', light: '#5cf', 500: '#09e' },
+        'accent-2': 'rgb(0 0 0)',
+      },
+      fontFamily: { sans: ['Inter', ...defaultTheme.fontFamily.sans] },
+      spacing: ({ theme }) => theme('width'),
+    },
+  },
+  plugins: [require('@tailwindcss/forms')],
+}
+`
+
+func TestParseTailwindConfig(t *testing.T) {
+	config := ParseTailwindConfig("tailwind.config.js", []byte(testTailwindConfig))
+
+	if !slices.Equal(config.Content, []string{"src/**/*.{html,js}"}) {
+		t.Errorf("Expected content src/**/*.{html,js}, got %v", config.Content)
+	}
+	if !slices.Equal(config.Exclude, []string{"src/legacy/**"}) {
+		t.Errorf("Expected exclude src/legacy/**, got %v", config.Exclude)
+	}
+	if config.Prefix != "tw-" || config.Separator != "_" || config.Important != "#app" {
+		t.Errorf("Expected prefix tw-, separator _ and important #app, got %q, %q and %q", config.Prefix, config.Separator, config.Important)
+	}
+	if !slices.Equal(config.Theme["colors"], []string{"brand", "brand-light", "brand-500", "accent-2"}) {
+		t.Errorf("Expected flattened brand colors, got %v", config.Theme["colors"])
+	}
+	if !slices.Equal(config.Theme["screens"], []string{"tablet", "desktop"}) {
+		t.Errorf("Expected screens tablet and desktop, got %v", config.Theme["screens"])
+	}
+	if !slices.Equal(config.Theme["fontFamily"], []string{"sans"}) {
+		t.Errorf("Expected font family sans, got %v", config.Theme["fontFamily"])
+	}
+
+	for class, want := range map[string]bool{"bg-red-500": true, "bg-green-200": true, "hover_bg-red-100": true, "bg-blue-100": false, "focus_bg-red-100": false} {
+		if got := config.Safelisted(class); got != want {
+			t.Errorf("Expected Safelisted(%q) to be %v, got %v", class, want, got)
+		}
+	}
+
+	// the theme function can't be evaluated, plugins aren't read at all
+	if len(config.Warnings) != 1 || !contains(config.Warnings, "tailwind.config.js:21: can't evaluate theme.spacing \"({ theme }) => theme('width')\" statically") {
+		t.Errorf("Expected a warning about theme.spacing, got %q", config.Warnings)
+	}
+}
+
+func TestParseTailwindConfigSpreads(t *testing.T) {
+	src := `const colors = require('./colors')
+
+module.exports = {
+  theme: {
+    extend: {
+      colors: {
+        ...colors,
+        brand: '#0af',
+        [accent]: '#f0a',
+      },
+    },
+  },
+}
+`
+	config := ParseTailwindConfig("tailwind.config.js", []byte(src))
+	if !slices.Equal(config.Theme["colors"], []string{"brand"}) {
+		t.Errorf("Expected the brand color, got %v", config.Theme["colors"])
+	}
+	expected := []string{
+		`tailwind.config.js:7: can't evaluate spread "...colors" statically, it is left out`,
+		`tailwind.config.js:9: can't evaluate computed key "[accent]: '#f0a'" statically, it is left out`,
+	}
+	if !slices.Equal(config.Warnings, expected) {
+		t.Errorf("Expected the warnings %q, got %q", expected, config.Warnings)
+	}
+}
+
+func TestParseTailwindConfigStrayBrackets(t *testing.T) {
+	src := `module.exports = {
+  content: [require('path').join(__dirname, 'src/**/*.html').replace(/\)/g, '')],
+  prefix: 'tw-',
+}
+`
+	config := ParseTailwindConfig("tailwind.config.js", []byte(src))
+	if config.Prefix != "tw-" {
+		t.Errorf("Expected the prefix tw- after a regexp with a ), got %q", config.Prefix)
+	}
+	if len(config.Warnings) != 1 || !strings.Contains(config.Warnings[0], "can't evaluate content entry") {
+		t.Errorf("Expected a warning about the content entry, got %q", config.Warnings)
+	}
+
+	// the parser can't move past a stray ) or }, it gives up instead of
+	// looping forever
+	for _, stray := range []string{")", "}"} {
+		src := "module.exports = {\n  content: ['a.html', foo" + stray + "],\n  prefix: 'tw-',\n}\n"
+		config := ParseTailwindConfig("tailwind.config.js", []byte(src))
+		if len(config.Warnings) != 1 || !strings.Contains(config.Warnings[0], "can't evaluate the exported config") {
+			t.Errorf("%s: expected a warning about the config, got %q", stray, config.Warnings)
+		}
+	}
+}
+
+func TestParseTailwindConfigTypeScript(t *testing.T) {
+	src := `import type { Config } from 'tailwindcss'
+
+const config: Config = {
+  content: { files: ["./app/**/*.tsx"], relative: true },
+  prefix: process.env.PREFIX,
+} satisfies Config
+
+export default config
+`
+	config := ParseTailwindConfig("tailwind.config.ts", []byte(src))
+	if !slices.Equal(config.Content, []string{"app/**/*.tsx"}) {
+		t.Errorf("Expected content app/**/*.tsx, got %v", config.Content)
+	}
+	if len(config.Warnings) != 1 || !contains(config.Warnings, `tailwind.config.ts:5: can't evaluate prefix "process.env.PREFIX" statically`) {
+		t.Errorf("Expected a warning about the prefix, got %q", config.Warnings)
+	}
+}
+
+func TestTailwindConfigDrivesScanAndValidation(t *testing.T) {
+	files := map[string]string{
+		"tailwind.config.js": testTailwindConfig,
+		"src/index.html":     `<div class="tw-bg-brand-light tablet_tw-flex tw-bg-nope"></div>`,
+		"src/legacy/a.html":  `<div class="old"></div>`,
+		"other/b.html":       `<div class="other"></div>`,
+	}
+	dir := writeProject(t, files)
+
+	config, err := LoadTailwindConfig(dir)
+	if err != nil || config == nil {
+		t.Fatalf("Expected the config to load, got %v", err)
+	}
+	project, err := ScanWith(dir, ScanOptions{Include: config.Content, Exclude: config.Exclude})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Files) != 1 || project.Files[0].Path != filepath.Join("src", "index.html") {
+		t.Fatalf("Expected only src/index.html to be scanned, got %d files", len(project.Files))
+	}
+
+	catalog := NewCatalog()
+	config.ExtendCatalog(catalog)
+	validator := &Validator{Parser: config.Parser(), Catalog: catalog}
+	findings := validator.Validate(project)
+	if len(findings) != 1 || findings[0].Class != "tw-bg-nope" {
+		t.Errorf("Expected only tw-bg-nope to be flagged, got %v", findings)
+	}
+}
@@ -0,0 +1,228 @@
+package analyzer
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// themeNamespaces maps the v4 @theme variable namespaces onto the v3 theme
+// keys the catalog knows, longer namespaces come first so --font-weight-*
+// doesn't end up in fontFamily
+var themeNamespaces = []struct{ prefix, key string }{
+	{"--color-", "colors"},
+	{"--spacing-", "spacing"},
+	{"--breakpoint-", "screens"},
+	{"--container-", "maxWidth"},
+	{"--font-weight-", "fontWeight"},
+	{"--font-", "fontFamily"},
+	{"--text-", "fontSize"},
+	{"--tracking-", "letterSpacing"},
+	{"--leading-", "lineHeight"},
+	{"--radius-", "borderRadius"},
+	{"--inset-shadow-", "insetShadow"},
+	{"--drop-shadow-", "dropShadow"},
+	{"--shadow-", "boxShadow"},
+	{"--blur-", "blur"},
+	{"--perspective-", "perspective"},
+	{"--aspect-", "aspectRatio"},
+	{"--ease-", "transitionTimingFunction"},
+	{"--animate-", "animation"},
+}
+
+var (
+	tailwindImportPattern = regexp.MustCompile(`@import\s+(?:url\()?["']tailwindcss["']`)
+	sourceFunctionPattern = regexp.MustCompile(`source\(\s*(none|"[^"]*"|'[^']*')\s*\)`)
+	prefixFunctionPattern = regexp.MustCompile(`prefix\(\s*([\w-]+)\s*\)`)
+)
+
+// findTailwindEntry returns the first stylesheet under dir that imports
+// tailwindcss, or an empty string
+func findTailwindEntry(dir string) (string, error) {
+	var entry string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.ToLower(filepath.Ext(path)) != ".css" {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if tailwindImportPattern.Match(src) {
+			entry = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return entry, err
+}
+
+// ParseTailwindCSS reads the configuration of a Tailwind v4 entry
+// stylesheet, the one with @import "tailwindcss", sheet.Path is relative to
+// the project root and @source paths are resolved against it
+func ParseTailwindCSS(sheet *Stylesheet) *TailwindConfig {
+	config := &TailwindConfig{Path: sheet.Path, Theme: make(map[string][]string)}
+	base := path.Dir(filepath.ToSlash(sheet.Path))
+	autoDetect := true
+	var sources []string
+
+	for _, rule := range sheet.Rules {
+		switch rule.AtRule {
+		case "import":
+			if !tailwindImportPattern.MatchString("@import " + rule.Prelude) {
+				continue
+			}
+			if m := sourceFunctionPattern.FindStringSubmatch(rule.Prelude); m != nil {
+				autoDetect = false
+				if m[1] != "none" {
+					sources = append(sources, path.Join(base, unquoteCSS(m[1])))
+				}
+			}
+			if m := prefixFunctionPattern.FindStringSubmatch(rule.Prelude); m != nil {
+				// v4 prefixes look like a variant in front of everything, "tw:flex"
+				config.Variants = append(config.Variants, m[1])
+			}
+		case "source":
+			config.readSource(rule, base, &sources)
+		case "theme":
+			config.readThemeBlock(rule)
+		case "utility":
+			if name := strings.TrimSpace(rule.Prelude); name != "" {
+				config.Utilities = append(config.Utilities, name)
+			}
+		case "custom-variant", "variant":
+			// a top-level @variant with a selector is the early v4 spelling
+			if name, _, _ := strings.Cut(rule.Prelude, " "); name != "" && (rule.AtRule == "custom-variant" || strings.Contains(rule.Prelude, "(")) {
+				config.Variants = append(config.Variants, name)
+			}
+		case "config":
+			config.Legacy = path.Join(base, unquoteCSS(rule.Prelude))
+		case "plugin":
+			config.warn(sheet.Path, rule.Pos.Line, "can't evaluate @plugin %s statically, classes it adds will look unknown", rule.Prelude)
+		}
+	}
+
+	for _, source := range sources {
+		glob := sourceGlob(source)
+		if !autoDetect {
+			config.Content = append(config.Content, glob)
+		}
+		// automatic detection covers the project but not what's outside of
+		// it or in ignored directories like node_modules
+		if outsideScan(source) {
+			config.Roots = append(config.Roots, globBase(glob))
+		}
+	}
+	return config
+}
+
+func (tc *TailwindConfig) readSource(rule *CSSRule, base string, sources *[]string) {
+	prelude, negated := strings.CutPrefix(rule.Prelude, "not ")
+	prelude = strings.TrimSpace(prelude)
+	if inline, ok := strings.CutPrefix(prelude, "inline("); ok {
+		if negated {
+			return
+		}
+		for _, candidate := range strings.Fields(unquoteCSS(strings.TrimSuffix(inline, ")"))) {
+			for _, class := range expandBraces(candidate) {
+				tc.Safelist = append(tc.Safelist, SafelistEntry{Class: class})
+			}
+		}
+		return
+	}
+	source := path.Join(base, unquoteCSS(prelude))
+	if negated {
+		tc.Exclude = append(tc.Exclude, sourceGlob(source))
+		return
+	}
+	*sources = append(*sources, source)
+}
+
+// readThemeBlock maps the variables of an @theme block onto theme keys,
+// resets like --color-*: initial and sub-properties like
+// --text-xl--line-height aren't values of their own
+func (tc *TailwindConfig) readThemeBlock(rule *CSSRule) {
+	for _, d := range rule.Declarations {
+		for _, ns := range themeNamespaces {
+			name, ok := strings.CutPrefix(d.Property, ns.prefix)
+			if !ok {
+				continue
+			}
+			if name != "" && !strings.Contains(name, "*") && !strings.Contains(name, "--") {
+				tc.Theme[ns.key] = append(tc.Theme[ns.key], name)
+			}
+			break
+		}
+	}
+}
+
+func unquoteCSS(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// sourceGlob turns an @source path into a glob, directories match
+// everything below them
+func sourceGlob(source string) string {
+	if strings.ContainsAny(source, "*?{[") || path.Ext(source) != "" {
+		return source
+	}
+	return strings.TrimSuffix(source, "/") + "/**"
+}
+
+// globBase is the directory part of a glob before the first pattern
+func globBase(glob string) string {
+	segments := strings.Split(glob, "/")
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, "*?{[") {
+			return path.Join(segments[:i]...)
+		}
+	}
+	return path.Dir(glob)
+}
+
+// outsideScan reports whether a path relative to the root is somewhere a
+// plain scan of the root doesn't look
+func outsideScan(source string) bool {
+	for _, segment := range strings.Split(source, "/") {
+		if segment == ".." || skipDir(segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// merge adds what a legacy JavaScript config pulled in with @config
+// contributes, its content globs are left out since v4 only adds them to
+// the sources and automatic detection already covers the project
+func (tc *TailwindConfig) merge(other *TailwindConfig) {
+	if tc.Prefix == "" {
+		tc.Prefix = other.Prefix
+	}
+	if tc.Separator == "" {
+		tc.Separator = other.Separator
+	}
+	if tc.Important == "" {
+		tc.Important = other.Important
+	}
+	tc.Safelist = append(tc.Safelist, other.Safelist...)
+	for key, values := range other.Theme {
+		tc.Theme[key] = append(tc.Theme[key], values...)
+	}
+	tc.Warnings = append(tc.Warnings, other.Warnings...)
+}
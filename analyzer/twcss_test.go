@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const testTailwindEntry = `@import "tailwindcss" prefix(tw);
+@source "../../shared/templates";
+@source "../node_modules/@acme/ui";
+@source not "./legacy";
+@source inline("underline {hover:,}bg-red-500");
+@plugin "@tailwindcss/typography";
+
+@theme {
+  --color-*: initial;
+  --color-brand: oklch(0.72 0.11 178);
+  --color-brand-dark: #064e3b;
+  --breakpoint-3xl: 120rem;
+  --font-display: "Satoshi", sans-serif;
+  --font-weight-heavy: 900;
+  --text-huge: 5rem;
+  --text-huge--line-height: 1;
+}
+
+@utility content-auto {
+  content-visibility: auto;
+}
+
+@utility tab-* {
+  tab-size: --value(integer);
+}
+
+@custom-variant theme-midnight (&:where([data-theme="midnight"] *));
+`
+
+func TestParseTailwindCSS(t *testing.T) {
+	sheet, err := ParseCSS("app/app.css", []byte(testTailwindEntry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := ParseTailwindCSS(sheet)
+
+	if len(config.Content) != 0 {
+		t.Errorf("Expected automatic detection to leave content empty, got %v", config.Content)
+	}
+	if !slices.Equal(config.Roots, []string{"../shared/templates", "node_modules/@acme/ui"}) {
+		t.Errorf("Expected the sources outside the scan to be roots, got %v", config.Roots)
+	}
+	if !slices.Equal(config.Exclude, []string{"app/legacy/**"}) {
+		t.Errorf("Expected app/legacy/** to be excluded, got %v", config.Exclude)
+	}
+	if !config.Safelisted("hover:bg-red-500") || !config.Safelisted("bg-red-500") || !config.Safelisted("underline") {
+		t.Errorf("Expected the inline sources to be safelisted, got %v", config.Safelist)
+	}
+	if !slices.Equal(config.Theme["colors"], []string{"brand", "brand-dark"}) {
+		t.Errorf("Expected colors brand and brand-dark, got %v", config.Theme["colors"])
+	}
+	if !slices.Equal(config.Theme["fontSize"], []string{"huge"}) || !slices.Equal(config.Theme["fontWeight"], []string{"heavy"}) {
+		t.Errorf("Expected font size huge and weight heavy, got %v and %v", config.Theme["fontSize"], config.Theme["fontWeight"])
+	}
+	if !slices.Equal(config.Utilities, []string{"content-auto", "tab-*"}) {
+		t.Errorf("Expected the custom utilities, got %v", config.Utilities)
+	}
+	if !slices.Equal(config.Variants, []string{"tw", "theme-midnight"}) {
+		t.Errorf("Expected the prefix and the custom variant, got %v", config.Variants)
+	}
+	if len(config.Warnings) != 1 || !contains(config.Warnings, `app/app.css:6: can't evaluate @plugin "@tailwindcss/typography" statically, classes it adds will look unknown`) {
+		t.Errorf("Expected a warning about the plugin, got %q", config.Warnings)
+	}
+
+	catalog := NewCatalog()
+	config.ExtendCatalog(catalog)
+	validator := &Validator{Parser: DefaultParser, Catalog: catalog}
+	for class, valid := range map[string]bool{
+		"tw:bg-brand":                    true,
+		"tw:3xl:font-heavy":              true,
+		"tw:text-huge":                   true,
+		"tw:content-auto":                true,
+		"tw:hover:tab-4":                 true,
+		"tw:theme-midnight:font-display": true,
+		"tw:bg-brand-light":              false,
+		"tw:tab":                         false,
+	} {
+		if problem, _ := validator.Check(class); (problem == "") != valid {
+			t.Errorf("Expected %q valid to be %v, got problem %q", class, valid, problem)
+		}
+	}
+}
+
+func TestLoadTailwindConfigSourceNone(t *testing.T) {
+	files := map[string]string{
+		"src/app.css":        `@import "tailwindcss" source(none); @source "../pages"; @config "../tailwind.config.js";`,
+		"tailwind.config.js": `module.exports = { content: ["./nothing/**"], theme: { extend: { colors: { brand: "#000" } } } }`,
+		"pages/index.html":   `<div class="bg-brand"></div>`,
+		"other/index.html":   `<div class="other"></div>`,
+	}
+	dir := writeProject(t, files)
+
+	config, err := LoadTailwindConfig(dir)
+	if err != nil || config == nil {
+		t.Fatalf("Expected the entry stylesheet to load, got %v", err)
+	}
+	if !slices.Equal(config.Content, []string{"pages/**"}) {
+		t.Errorf("Expected only pages/** as content, got %v", config.Content)
+	}
+	if !slices.Equal(config.Theme["colors"], []string{"brand"}) {
+		t.Errorf("Expected the @config theme to be merged, got %v", config.Theme)
+	}
+	project, err := ScanWith(dir, ScanOptions{Include: config.Content, Exclude: config.Exclude, Roots: config.Roots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(project.Files) != 1 || project.Files[0].Path != filepath.Join("pages", "index.html") {
+		t.Errorf("Expected only pages/index.html to be scanned, got %d files", len(project.Files))
+	}
+}
@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// UndefinedClass is a class used in markup that nothing defines: no project
+// stylesheet, no utility and no allowlist entry, Usages are in file order
+type UndefinedClass struct {
+	Class       string     `json:"class"`
+	Problem     string     `json:"problem"`
+	Suggestions []string   `json:"suggestions,omitempty"`
+	Usages      []Position `json:"usages"`
+}
+
+// Undefined groups the findings of Validate by class and adds the lookups
+// of CSS module keys that don't exist, classes sorted by name
+func (v *Validator) Undefined(p *Project) []UndefinedClass {
+	byClass := make(map[string]*UndefinedClass)
+	var names []string
+	for _, f := range v.Validate(p) {
+		undefined, exists := byClass[f.Class]
+		if !exists {
+			problem, suggestions := v.Check(f.Class)
+			undefined = &UndefinedClass{Class: f.Class, Problem: problem, Suggestions: suggestions}
+			byClass[f.Class] = undefined
+			names = append(names, f.Class)
+		}
+		undefined.Usages = append(undefined.Usages, f.Pos)
+	}
+	undefined := make([]UndefinedClass, 0, len(names))
+	for _, name := range names {
+		undefined = append(undefined, *byClass[name])
+	}
+	undefined = append(undefined, p.MissingModuleKeys()...)
+	sort.SliceStable(undefined, func(i, j int) bool {
+		return undefined[i].Class < undefined[j].Class
+	})
+	return undefined
+}
+
+// WriteUndefined prints every undefined class followed by its usages
+func WriteUndefined(w io.Writer, undefined []UndefinedClass) error {
+	var b strings.Builder
+	usages := 0
+	for _, u := range undefined {
+		usages += len(u.Usages)
+		fmt.Fprintf(&b, "%s (%d usages): %s", u.Class, len(u.Usages), u.Problem)
+		if len(u.Suggestions) > 0 {
+			b.WriteString(", did you mean " + strings.Join(u.Suggestions, " or ") + "?")
+		}
+		b.WriteByte('\n')
+		for _, pos := range u.Usages {
+			fmt.Fprintf(&b, "  %s\n", pos)
+		}
+	}
+	fmt.Fprintf(&b, "%d undefined classes, used %d times\n", len(undefined), usages)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
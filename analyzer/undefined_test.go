@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUndefined(t *testing.T) {
+	files := map[string]string{
+		"a.html":   `<div class="card js-open flex bg-slat-500"></div>` + "\n" + `<p class="lead bg-slat-500"></p>`,
+		"b.html":   `<span class="lead"></span>`,
+		"site.css": `.card { padding: 1rem }`,
+	}
+	dir := writeProject(t, files)
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator := &Validator{
+		Parser:  DefaultParser,
+		Catalog: NewCatalog(),
+		Defined: project.DefinedClasses(),
+		Config:  &Config{Allowlist: []string{"js-*"}},
+	}
+	undefined := validator.Undefined(project)
+	if len(undefined) != 2 || undefined[0].Class != "bg-slat-500" || undefined[1].Class != "lead" {
+		t.Fatalf("Expected bg-slat-500 and lead to be undefined, got %+v", undefined)
+	}
+	if len(undefined[0].Usages) != 2 || undefined[0].Usages[1].Line != 2 || len(undefined[1].Usages) != 2 {
+		t.Errorf("Expected every usage to be listed, got %+v", undefined)
+	}
+
+	var b strings.Builder
+	if err := WriteUndefined(&b, undefined); err != nil {
+		t.Fatal(err)
+	}
+	expected := `bg-slat-500 (2 usages): unknown value for utility "bg", did you mean bg-slate-500 or bg-slate-100 or bg-slate-200?
+  a.html:1:31
+  a.html:2:16
+lead (2 usages): unknown utility
+  a.html:2:11
+  b.html:1:14
+2 undefined classes, used 4 times
+`
+	if b.String() != expected {
+		t.Errorf("Expected report\n%s\ngot\n%s", expected, b.String())
+	}
+}
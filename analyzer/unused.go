@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// UsedClasses returns the set of classes the project's markup references,
+// plus the ones an @extend pulls in for a selector that can match
+func (p *Project) UsedClasses() map[string]bool {
+	used := make(map[string]bool)
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			for _, class := range element.Classes {
+				used[class.Name] = true
+			}
+		}
+	}
+	p.extendUsed(used)
+	return used
+}
+
+// extendUsed marks the targets of extensions whose selector can match as
+// used, until there's nothing left to add since extensions chain
+func (p *Project) extendUsed(used map[string]bool) {
+	matcher := &Purger{Used: used}
+	for changed := true; changed; {
+		changed = false
+		for _, sheet := range p.Stylesheets {
+			for _, extension := range sheet.Extends {
+				if !used[extension.Target] && matcher.satisfiable(extension.Selector) {
+					used[extension.Target] = true
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// UnusedClasses returns every class definition in the project's stylesheets
+// whose class isn't referenced anywhere, a selector like ".card .old" is
+// reported for "old" even when "card" is used. The classes of CSS modules
+// are used by looking them up in script rather than by markup
+func (p *Project) UnusedClasses() []ClassDefinition {
+	used := p.UsedClasses()
+	modules, _ := p.cssModules()
+	var unused []ClassDefinition
+	for _, sheet := range p.Stylesheets {
+		if sheet.IsModule() {
+			unused = append(unused, modules[sheet.Path].unused(used)...)
+			continue
+		}
+		for _, definition := range sheet.Definitions() {
+			if !used[definition.Class] {
+				unused = append(unused, definition)
+			}
+		}
+	}
+	return unused
+}
+
+// WriteUnused prints one unused definition per line, compiler style, and
+// how many distinct classes that is
+func WriteUnused(w io.Writer, unused []ClassDefinition) error {
+	var b strings.Builder
+	classes := make(map[string]bool)
+	for _, d := range unused {
+		classes[d.Class] = true
+		fmt.Fprintf(&b, "%s: unused class %q in selector %s\n", d.Pos, d.Class, d.Selector)
+	}
+	fmt.Fprintf(&b, "%d unused classes in %d selectors\n", len(classes), len(unused))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnusedClasses(t *testing.T) {
+	files := map[string]string{
+		"index.html": `<div class="card"><h2 class="card__title">Hi</h2></div>`,
+		"css/site.css": `.card { padding: 1rem }
+.card .card__title, .card .card__old { font-weight: bold }
+@media print {
+  .print-only { display: block }
+}
+@keyframes fade { from { opacity: 0 } }
+`,
+	}
+	dir := writeProject(t, files)
+
+	project, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unused := project.UnusedClasses()
+	if len(unused) != 2 {
+		t.Fatalf("Expected 2 unused definitions, got %+v", unused)
+	}
+	if unused[0].Class != "card__old" || unused[0].Selector != ".card .card__old" || unused[0].Pos.Line != 2 {
+		t.Errorf("Unexpected first unused definition %+v", unused[0])
+	}
+	if unused[1].Class != "print-only" || unused[1].Pos.Line != 4 {
+		t.Errorf("Unexpected second unused definition %+v", unused[1])
+	}
+
+	var b strings.Builder
+	if err := WriteUnused(&b, unused); err != nil {
+		t.Fatal(err)
+	}
+	expected := filepath.Join("css", "site.css") + `:2:1: unused class "card__old" in selector .card .card__old
+` + filepath.Join("css", "site.css") + `:4:3: unused class "print-only" in selector .print-only
+2 unused classes in 2 selectors
+`
+	if b.String() != expected {
+		t.Errorf("Expected report\n%s\ngot\n%s", expected, b.String())
+	}
+}
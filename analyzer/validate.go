@@ -0,0 +1,260 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Finding is a problem found at a position in the project
+type Finding struct {
+	Rule        string   `json:"rule"`
+	Pos         Position `json:"pos"`
+	Class       string   `json:"class,omitempty"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+	// Provenance is the one of the element, ProvenanceJS for script
+	Provenance string `json:"provenance,omitempty"`
+}
+
+func (f Finding) String() string {
+	rule := f.Rule
+	if f.Provenance != "" {
+		rule += ", " + f.Provenance
+	}
+	s := fmt.Sprintf("%s: %s (%s)", f.Pos, f.Message, rule)
+	if len(f.Suggestions) > 0 {
+		s += ", did you mean " + strings.Join(f.Suggestions, " or ") + "?"
+	}
+	return s
+}
+
+// WriteFindings prints one finding per line, compiler style
+func WriteFindings(w io.Writer, findings []Finding) error {
+	var b strings.Builder
+	for _, f := range findings {
+		b.WriteString(f.String() + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Validator checks classes against the utility catalog, classes in Defined
+// come from the project's own stylesheets and are always valid, and so are
+// the ones the config allowlists
+type Validator struct {
+	Parser  *ClassParser
+	Catalog *Catalog
+	Defined map[string]bool
+	Config  *Config
+}
+
+// maxSuggestionDistance is how many edits a suggestion may be away
+const maxSuggestionDistance = 2
+
+// Check returns why raw isn't a valid class, or an empty string if it is,
+// along with up to three close valid classes
+func (v *Validator) Check(raw string) (problem string, suggestions []string) {
+	if v.Defined[raw] || v.Config.Allowed(raw) {
+		return "", nil
+	}
+	class := v.Parser.Parse(raw)
+	for i, variant := range class.Variants {
+		if !v.Catalog.validVariant(variant) {
+			return fmt.Sprintf("unknown variant %q", variant), v.suggestVariant(class, i)
+		}
+	}
+	problem = v.Catalog.checkUtility(class)
+	if problem == "" {
+		return "", nil
+	}
+	if class.Known {
+		problem = fmt.Sprintf("%s for utility %q", problem, class.Utility)
+	}
+	return problem, v.suggestUtility(raw, class)
+}
+
+// Validate checks every class usage of the project, each usage of an
+// invalid class gets its own finding
+func (v *Validator) Validate(p *Project) []Finding {
+	type result struct {
+		problem     string
+		suggestions []string
+	}
+	checked := make(map[string]result)
+	var findings []Finding
+	for _, file := range p.Files {
+		for _, element := range file.Elements {
+			for _, token := range element.Classes {
+				r, exists := checked[token.Name]
+				if !exists {
+					r.problem, r.suggestions = v.Check(token.Name)
+					checked[token.Name] = r
+				}
+				if r.problem == "" {
+					continue
+				}
+				findings = append(findings, Finding{
+					Rule:        "unknown-class",
+					Pos:         token.Pos,
+					Class:       token.Name,
+					Message:     fmt.Sprintf("unknown class %q: %s", token.Name, r.problem),
+					Suggestions: r.suggestions,
+					Provenance:  element.Provenance,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// splitForSuggestions cuts raw around the root and value so suggestions
+// only swap that part, lead holds the variants, flags and prefix and trail
+// the modifier and a trailing important flag
+func (v *Validator) splitForSuggestions(raw string, class Class) (lead, body, trail string) {
+	separator := v.Parser.Separator
+	if separator == "" {
+		separator = ":"
+	}
+	parts := splitTopLevel(raw, separator)
+	start := len(raw) - len(parts[len(parts)-1])
+	end := len(raw)
+	if class.Important && strings.HasSuffix(raw, "!") {
+		end--
+	}
+	if class.Modifier != "" {
+		end -= len(class.Modifier) + 1
+	}
+	if start < end && raw[start] == '!' {
+		start++
+	}
+	if start < end && raw[start] == '-' {
+		start++
+	}
+	if v.Parser.Prefix != "" && strings.HasPrefix(raw[start:], v.Parser.Prefix) {
+		start += len(v.Parser.Prefix)
+	}
+	if start > end {
+		return raw, "", ""
+	}
+	return raw[:start], raw[start:end], raw[end:]
+}
+
+// suggestUtility looks for valid classes a few edits away, either by fixing
+// the value ("bg-slate-9500") or the root ("tex-white")
+func (v *Validator) suggestUtility(raw string, class Class) []string {
+	lead, body, trail := v.splitForSuggestions(raw, class)
+
+	var candidates []string
+	if class.Known && class.Property == "" {
+		candidates = append(candidates, class.Utility)
+		for _, value := range v.Catalog.candidateValues(class.Utility) {
+			candidates = append(candidates, class.Utility+"-"+value)
+		}
+	} else if !class.Known {
+		// try every root in place of the start of the body
+		for root := range utilities {
+			for i := 1; i <= len(body); i++ {
+				if i < len(body) && body[i] != '-' {
+					continue
+				}
+				if levenshtein(body[:i], root) <= maxSuggestionDistance {
+					candidates = append(candidates, root+body[i:])
+				}
+			}
+		}
+	}
+
+	var scored []scoredSuggestion
+	for _, candidate := range candidates {
+		distance := levenshtein(body, candidate)
+		if distance == 0 || distance > maxSuggestionDistance {
+			continue
+		}
+		suggestion := lead + candidate + trail
+		if v.checkWithoutSuggestions(suggestion) != "" {
+			continue
+		}
+		scored = append(scored, scoredSuggestion{suggestion, distance})
+	}
+	return bestSuggestions(scored)
+}
+
+// checkWithoutSuggestions validates without looking for suggestions
+func (v *Validator) checkWithoutSuggestions(raw string) string {
+	class := v.Parser.Parse(raw)
+	for _, variant := range class.Variants {
+		if !v.Catalog.validVariant(variant) {
+			return "unknown variant"
+		}
+	}
+	return v.Catalog.checkUtility(class)
+}
+
+// suggestVariant replaces the i-th variant with close built-in variants
+func (v *Validator) suggestVariant(class Class, i int) []string {
+	separator := v.Parser.Separator
+	if separator == "" {
+		separator = ":"
+	}
+	parts := splitTopLevel(class.Raw, separator)
+	var scored []scoredSuggestion
+	for variant := range variants {
+		distance := levenshtein(class.Variants[i], variant)
+		if distance > maxSuggestionDistance {
+			continue
+		}
+		fixed := slices.Clone(parts)
+		fixed[i] = variant
+		scored = append(scored, scoredSuggestion{strings.Join(fixed, separator), distance})
+	}
+	return bestSuggestions(scored)
+}
+
+type scoredSuggestion struct {
+	class    string
+	distance int
+}
+
+// bestSuggestions keeps the three closest suggestions, ties sorted by name
+func bestSuggestions(scored []scoredSuggestion) []string {
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].distance != scored[j].distance {
+			return scored[i].distance < scored[j].distance
+		}
+		return scored[i].class < scored[j].class
+	})
+	var suggestions []string
+	for _, s := range scored {
+		if len(suggestions) == 3 {
+			break
+		}
+		if len(suggestions) == 0 || suggestions[len(suggestions)-1] != s.class {
+			suggestions = append(suggestions, s.class)
+		}
+	}
+	return suggestions
+}
+
+// levenshtein is the edit distance between two strings
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
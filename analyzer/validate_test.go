@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidatorCheck(t *testing.T) {
+	catalog := NewCatalog()
+	catalog.Extend("colors", "brand", "brand-dark")
+	catalog.Extend("screens", "3xl")
+	validator := &Validator{Parser: DefaultParser, Catalog: catalog}
+
+	validClasses := []string{
+		"bg-slate-950", "hover:bg-blue-700", "disabled:cursor-not-allowed", "-ml-[40rem]", "-left-1/4",
+		"md:hover:dark:text-sm", "bg-black/50", "text-2xl/7", "p-13", "mt-2.5", "group-hover/item:flex",
+		"data-[state=open]:hidden", "[mask-type:luminance]", "bg-brand", "3xl:flex", "max-3xl:block",
+		"supports-[backdrop-filter]:bg-white/60", "*:p-2", "@md:grid-cols-2", "font-bold!", "!font-bold",
+	}
+	for _, class := range validClasses {
+		if problem, _ := validator.Check(class); problem != "" {
+			t.Errorf("Expected %s to be valid, got %s", class, problem)
+		}
+	}
+
+	invalidClasses := []struct {
+		class       string
+		suggestions []string
+	}{
+		{"bg-slate-9500", []string{"bg-slate-500", "bg-slate-950"}},
+		{"tex-white", []string{"text-white"}},
+		{"hover:tex-white", []string{"hover:text-white"}},
+		{"hovr:bg-red-500", []string{"hover:bg-red-500"}},
+		{"-p-4", nil},
+		{"flex-sideways", nil},
+		{"bg-brandd", []string{"bg-brand"}},
+		{"block/50", nil},
+	}
+	for _, ic := range invalidClasses {
+		problem, suggestions := validator.Check(ic.class)
+		if problem == "" {
+			t.Errorf("Expected %s to be invalid", ic.class)
+			continue
+		}
+		for _, s := range ic.suggestions {
+			if !slices.Contains(suggestions, s) {
+				t.Errorf("Expected %s to be suggested for %s, got %v", s, ic.class, suggestions)
+			}
+		}
+	}
+}
+
+func TestValidateSkipsClassesDefinedInCSS(t *testing.T) {
+	sampleHTML := "<div class=\"card tex-white\">\n<p class=\"card__title p-2\"></p></div>"
+	sampleCSS := ".card { color: red }\n.card__title:hover, .other { color: blue }"
+	tempDir := writeProject(t, map[string]string{"sample.html": sampleHTML, "sample.css": sampleCSS})
+	project, err := Scan(tempDir)
+	if err != nil {
+		t.Fatalf("Scan failed: %s", err)
+	}
+
+	validator := &Validator{Parser: DefaultParser, Catalog: NewCatalog(), Defined: project.DefinedClasses()}
+	findings := validator.Validate(project)
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got %d: %v", len(findings), findings)
+	}
+	f := findings[0]
+	if f.Class != "tex-white" || f.Pos.Line != 1 || f.Pos.Column != 18 || !slices.Contains(f.Suggestions, "text-white") {
+		t.Errorf("Unexpected finding %s", f)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// bootstrapMarker lists the files -write rewrote, a path a line. Their new
+// classes can be valid Bootstrap again, mt-3 became mt-4 which another pass
+// would make mt-6, so they're left out of the report and of later rewrites
+const bootstrapMarker = ".bootstrap-migrated"
+
+func runBootstrap(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("bootstrap", flag.ContinueOnError)
+	write := fs.Bool("write", false, "rewrite the Bootstrap classes with exact Tailwind counterparts in place, once per file")
+	asJSON := fs.Bool("json", false, "print the effort per page and the findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	markerPath := filepath.Join(ws.project.Root, bootstrapMarker)
+	var done []string
+	if src, err := os.ReadFile(markerPath); err == nil {
+		for _, line := range strings.Split(string(src), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				done = append(done, line)
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	skipped := len(ws.project.Files)
+	ws.project.Files = slices.DeleteFunc(ws.project.Files, func(file *analyzer.SourceFile) bool {
+		return slices.Contains(done, filepath.ToSlash(file.Path))
+	})
+	skipped -= len(ws.project.Files)
+	migration := analyzer.TranslateBootstrapProject(ws.project)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(migration); err != nil {
+			return err
+		}
+	} else {
+		if err := migration.WriteReport(stdout); err != nil {
+			return err
+		}
+		if len(migration.Todo) > 0 {
+			fmt.Fprintln(stdout)
+		}
+		if err := analyzer.WriteFindings(stdout, migration.Todo); err != nil {
+			return err
+		}
+		if skipped > 0 {
+			fmt.Fprintf(stdout, "\nleft out %d files rewritten before, listed in %s\n", skipped, bootstrapMarker)
+		}
+	}
+	if !*write {
+		return nil
+	}
+
+	rewritten, files := 0, 0
+	for _, file := range ws.project.Files {
+		path := ws.project.AbsPath(file.Path)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		edits, renamed := analyzer.BootstrapEdits(file, src)
+		if len(edits) == 0 {
+			continue
+		}
+		if err := rewriteFile(path, src, edits); err != nil {
+			return fmt.Errorf("%s: %w", file.Path, err)
+		}
+		rewritten += len(renamed)
+		files++
+		done = append(done, filepath.ToSlash(file.Path))
+	}
+	if files > 0 {
+		slices.Sort(done)
+		if err := os.WriteFile(markerPath, []byte(strings.Join(slices.Compact(done), "\n")+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+	if !*asJSON {
+		fmt.Fprintf(stdout, "\nrewrote %d Bootstrap classes in %d files\n", rewritten, files)
+	}
+	return nil
+}
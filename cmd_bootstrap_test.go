@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBootstrapWriteOnce(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "index html.html")
+	if err := os.WriteFile(page, []byte(`<div class="d-flex rounded-lg mt-3 p-4 card shadow-md"></div>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// rounded-lg and shadow-md are Tailwind's, not responsive Bootstrap
+	want := `<div class="flex rounded-lg mt-4 p-6 card shadow-md"></div>`
+	for run := 1; run <= 2; run++ {
+		if err := runBootstrap([]string{"-write", dir}, io.Discard); err != nil {
+			t.Fatalf("run %d: %s", run, err)
+		}
+		// mt-4 and p-6 are valid Bootstrap again, the second run mustn't
+		// take them for Bootstrap's
+		got, err := os.ReadFile(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("run %d: expected %s, got %s", run, want, got)
+		}
+	}
+	marker, err := os.ReadFile(filepath.Join(dir, bootstrapMarker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(marker) != "index html.html\n" {
+		t.Errorf("Expected the marker to list the page, got %q", marker)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// specificityFlag reads a specificity written a,b,c
+type specificityFlag struct {
+	value analyzer.Specificity
+	set   bool
+}
+
+func (s *specificityFlag) String() string {
+	return fmt.Sprintf("%d,%d,%d", s.value[0], s.value[1], s.value[2])
+}
+
+func (s *specificityFlag) Set(v string) error {
+	parts := strings.Split(v, ",")
+	if len(parts) != 3 {
+		return fmt.Errorf("expected ids,classes,types like 0,3,0, got %q", v)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 {
+			return fmt.Errorf("expected ids,classes,types like 0,3,0, got %q", v)
+		}
+		s.value[i] = n
+	}
+	s.set = true
+	return nil
+}
+
+func runComplexity(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("complexity", flag.ContinueOnError)
+	top := fs.Int("top", 10, "how many of the most specific selectors to list")
+	asJSON := fs.Bool("json", false, "print the full report as JSON")
+	var maxSpecificity specificityFlag
+	fs.Var(&maxSpecificity, "max-specificity", "flag selectors more specific than ids,classes,types, e.g. 0,3,0")
+	maxDepth := fs.Int("max-depth", 0, "flag selectors chaining more compound selectors than this")
+	maxIDs := fs.Int("max-ids", -1, "flag selectors with more ID selectors than this, 0 bans them")
+	maxImportant := fs.Int("max-important", -1, "flag the !important declarations of a stylesheet past this many")
+	noQualified := fs.Bool("no-qualified-types", false, "flag type selectors qualifying a class or ID, like div.card")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *top < 0 {
+		return errors.New("-top can't be negative")
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	// flags override the limits of the config file
+	limits := ws.config.Complexity
+	if maxSpecificity.set {
+		limits.MaxSpecificity = maxSpecificity.value
+	}
+	if *maxDepth > 0 {
+		limits.MaxDepth = *maxDepth
+	}
+	if *maxIDs >= 0 {
+		limits.MaxIDs = maxIDs
+	}
+	if *maxImportant >= 0 {
+		limits.MaxImportant = maxImportant
+	}
+	limits.NoQualifiedTypes = limits.NoQualifiedTypes || *noQualified
+
+	complexity := analyzer.ComputeComplexity(ws.project)
+	findings := complexity.Lint(limits)
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(struct {
+			*analyzer.Complexity
+			Worst                []analyzer.SelectorComplexity `json:"worst"`
+			SpecificityHistogram []analyzer.Distribution       `json:"specificityHistogram"`
+			DepthHistogram       []analyzer.Distribution       `json:"depthHistogram"`
+			Findings             []analyzer.Finding            `json:"findings"`
+		}{complexity, complexity.Worst(*top), complexity.SpecificityHistogram(), complexity.DepthHistogram(), findings}); err != nil {
+			return err
+		}
+	} else {
+		if err := complexity.WriteReport(stdout, *top); err != nil {
+			return err
+		}
+		if len(findings) > 0 {
+			fmt.Fprintln(stdout)
+			if err := analyzer.WriteFindings(stdout, findings); err != nil {
+				return err
+			}
+		}
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("%d selectors or declarations over the complexity limits", len(findings))
+	}
+	return nil
+}
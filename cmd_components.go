@@ -0,0 +1,115 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runComponents(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("components", flag.ContinueOnError)
+	options := analyzer.DefaultComponentOptions
+	fs.IntVar(&options.MinOccurrences, "min", options.MinOccurrences, "how many elements have to share the classes")
+	fs.IntVar(&options.MinClasses, "classes", options.MinClasses, "how many classes they have to share")
+	fs.IntVar(&options.MaxDifference, "diff", options.MaxDifference, "how many classes a list can have or lack and still count as the same, 0 for identical lists only")
+	top := fs.Int("top", 0, "only keep the best N candidates, 0 keeps them all")
+	css := fs.String("css", "", "write the @apply rules of the candidates to this stylesheet")
+	write := fs.Bool("write", false, "replace the classes with the component classes in place, needs -css")
+	force := fs.Bool("force", false, "overwrite the -css stylesheet when it already exists")
+	asJSON := fs.Bool("json", false, "print the candidates as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *write && *css == "" {
+		return errors.New("-write needs -css, the stylesheet defining the component classes")
+	}
+	if *css != "" && !*force {
+		// the stylesheet may be one of the project's, it's only replaced on request
+		if _, err := os.Stat(*css); err == nil {
+			return fmt.Errorf("%s already exists, pass -force to overwrite it", *css)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	candidates := analyzer.ComponentCandidates(ws.project, ws.parser, ws.catalog, options)
+	if *top > 0 && len(candidates) > *top {
+		candidates = candidates[:*top]
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(candidates); err != nil {
+			return err
+		}
+	} else if err := analyzer.WriteComponentReport(stdout, candidates); err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if *css != "" {
+		var rules []string
+		for _, candidate := range candidates {
+			rules = append(rules, candidate.CSS)
+		}
+		src := "@layer components {\n" + indent(strings.Join(rules, "\n")) + "}\n"
+		if err := os.MkdirAll(filepath.Dir(*css), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(*css, []byte(src), 0644); err != nil {
+			return err
+		}
+	}
+	if !*write {
+		return nil
+	}
+
+	files, elements := 0, 0
+	for _, file := range ws.project.Files {
+		path := ws.project.AbsPath(file.Path)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		edits := analyzer.ComponentEdits(file, src, candidates)
+		if len(edits) == 0 {
+			continue
+		}
+		if err := rewriteFile(path, src, edits); err != nil {
+			return fmt.Errorf("%s: %w", file.Path, err)
+		}
+		files++
+	}
+	for _, candidate := range candidates {
+		elements += candidate.Occurrences
+	}
+	if !*asJSON {
+		fmt.Fprintf(stdout, "\nused %d component classes on %d elements in %d files, defined in %s\n", len(candidates), elements, files, *css)
+	}
+	return nil
+}
+
+// indent indents every line of s by two spaces
+func indent(s string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			b.WriteString("  ")
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComponentsKeepsExistingStylesheet(t *testing.T) {
+	dir := t.TempDir()
+	button := `<button class="px-4 py-2 rounded bg-blue-600 text-white">Go</button>`
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(strings.Repeat(button+"\n", 3)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	css := filepath.Join(dir, "app.css")
+	if err := os.WriteFile(css, []byte(".app { color: red }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runComponents([]string{"-css", css, dir}, io.Discard); err == nil {
+		t.Errorf("Expected an existing stylesheet to be refused")
+	}
+	if got, _ := os.ReadFile(css); string(got) != ".app { color: red }\n" {
+		t.Errorf("Expected the stylesheet to stay as it was, got %q", got)
+	}
+
+	if err := runComponents([]string{"-css", css, "-force", dir}, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(css); !strings.Contains(string(got), "@layer components {") {
+		t.Errorf("Expected -force to write the components, got %q", got)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+func runConflicts(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("conflicts", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	findings := analyzer.Conflicts(ws.project, ws.parser, ws.catalog)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			return err
+		}
+	} else if err := analyzer.WriteFindings(stdout, findings); err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("%d elements with conflicting utilities", len(findings))
+	}
+	return nil
+}
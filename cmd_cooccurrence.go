@@ -0,0 +1,44 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+)
+
+func runCooccurrence(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("cooccurrence", flag.ContinueOnError)
+	options := analyzer.DefaultCooccurrenceOptions
+	fs.IntVar(&options.MinSupport, "min-support", options.MinSupport, "how many elements a class or group has to be on")
+	fs.Float64Var(&options.MinConfidence, "min-confidence", options.MinConfidence, "the least share of the elements with a group's most common class that have the whole group")
+	fs.Float64Var(&options.MinLift, "min-lift", options.MinLift, "how many times more often than by chance the classes have to be together")
+	fs.IntVar(&options.MaxSize, "max-size", options.MaxSize, "the most classes a group can have")
+	fs.Float64Var(&options.ClusterConfidence, "cluster", options.ClusterConfidence, "the least confidence every two classes of a cluster need")
+	top := fs.Int("top", 20, "how many pairs and groups to list, 0 lists them all")
+	asJSON := fs.Bool("json", false, "print the matrix, pairs, groups and clusters as JSON")
+	asDOT := fs.Bool("dot", false, "print the pairs and clusters as a Graphviz graph")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *asJSON && *asDOT {
+		return errors.New("-json and -dot can't be used together")
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	cooccurrence := analyzer.AnalyzeCooccurrence(ws.project, options)
+
+	switch {
+	case *asJSON:
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(cooccurrence)
+	case *asDOT:
+		return cooccurrence.WriteDOT(stdout)
+	}
+	return cooccurrence.WriteReport(stdout, *top)
+}
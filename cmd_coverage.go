@@ -0,0 +1,33 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"io"
+)
+
+func runCoverage(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	unmatched := fs.Bool("unmatched", false, "only list the rules that matched nothing")
+	asJSON := fs.Bool("json", false, "print the coverage as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	coverage, err := analyzer.ComputeCoverage(ws.project)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(coverage)
+	}
+	return coverage.WriteReport(stdout, *unmatched)
+}
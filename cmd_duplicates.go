@@ -0,0 +1,62 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func runDuplicates(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("duplicates", flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "remove the repeated classes from the files, keeping their formatting")
+	asJSON := fs.Bool("json", false, "print the findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	findings := analyzer.DuplicateClasses(ws.project)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			return err
+		}
+	} else if err := analyzer.WriteFindings(stdout, findings); err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	if !*fix {
+		return fmt.Errorf("%d repeated classes", len(findings))
+	}
+
+	files := 0
+	for _, file := range ws.project.Files {
+		path := ws.project.AbsPath(file.Path)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		edits := analyzer.DuplicateEdits(file, src)
+		if len(edits) == 0 {
+			continue
+		}
+		if err := rewriteFile(path, src, edits); err != nil {
+			return fmt.Errorf("%s: %w", file.Path, err)
+		}
+		files++
+	}
+	if !*asJSON {
+		fmt.Fprintf(stdout, "removed %d repeated classes from %d files\n", len(findings), files)
+	}
+	return nil
+}
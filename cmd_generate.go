@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+func runGenerate(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	output := fs.String("o", "", "write the CSS to this file and print the report instead")
+	classList := fs.String("classes", "", "read the classes from a file with one class per line, like classes.log, instead of scanning")
+	asJSON := fs.Bool("json", false, "print the rules and unsupported classes as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var ws *workspace
+	var classes []string
+	var err error
+	if *classList != "" {
+		if ws, err = configureWorkspace(dirArg(fs)); err != nil {
+			return err
+		}
+		if classes, err = readClassList(*classList); err != nil {
+			return err
+		}
+	} else {
+		if ws, err = openWorkspace(dirArg(fs)); err != nil {
+			return err
+		}
+		classes = ws.project.ClassNames()
+	}
+
+	generator := &analyzer.Generator{Parser: ws.parser, Catalog: ws.catalog}
+	if ws.tailwind != nil {
+		generator.Important = ws.tailwind.Important
+		for _, entry := range ws.tailwind.Safelist {
+			if entry.Class != "" {
+				classes = append(classes, entry.Class)
+			}
+		}
+	}
+	css := generator.Generate(classes)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(css)
+	}
+	if *output == "" {
+		if len(css.Unsupported) > 0 {
+			log.Printf("%d classes have no generated CSS, run with -o to see which\n", len(css.Unsupported))
+		}
+		return css.WriteCSS(stdout)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := css.WriteCSS(f); err != nil {
+		return err
+	}
+	return css.WriteReport(stdout)
+}
+
+// readClassList reads one class per line, blank lines are skipped
+func readClassList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var classes []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if class := strings.TrimSpace(scanner.Text()); class != "" {
+			classes = append(classes, class)
+		}
+	}
+	return classes, scanner.Err()
+}
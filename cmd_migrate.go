@@ -0,0 +1,59 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func runMigrate(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	write := fs.Bool("write", false, "rewrite the classes the safe rules cover in place")
+	asJSON := fs.Bool("json", false, "print the changes as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	changes := analyzer.MigrateV4(ws.project, ws.parser)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(changes); err != nil {
+			return err
+		}
+	} else if err := analyzer.WriteMigrationReport(stdout, changes); err != nil {
+		return err
+	}
+	if !*write {
+		return nil
+	}
+
+	files := 0
+	for _, file := range ws.project.Files {
+		path := ws.project.AbsPath(file.Path)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		edits := analyzer.MigrationEdits(file, src, ws.parser)
+		if len(edits) == 0 {
+			continue
+		}
+		if err := rewriteFile(path, src, edits); err != nil {
+			return fmt.Errorf("%s: %w", file.Path, err)
+		}
+		files++
+	}
+	if !*asJSON {
+		fmt.Fprintf(stdout, "\nrewrote the safe changes in %d files\n", files)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runMinify(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("minify", flag.ContinueOnError)
+	output := fs.String("o", "", "write the minified markup, stylesheets and manifest to this directory, keeping their relative paths")
+	manifest := fs.String("manifest", "", "where to write the manifest of short names (default DIR/class-map.json)")
+	allowJS := fs.Bool("allow-js", false, "minify the classes scripts use too, rewriting their string literals")
+	keep := fs.String("keep", "", "comma separated globs of classes to keep as they are")
+	asJSON := fs.Bool("json", false, "print the manifest as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	options := analyzer.MinifyOptions{AllowJS: *allowJS}
+	if *keep != "" {
+		options.Keep = strings.Split(*keep, ",")
+	}
+	classMap := analyzer.MinifyClasses(ws.project, ws.config, options)
+
+	// every file goes to the output, changed or not, so DIR is the whole
+	// project rather than the part of it minification touched
+	write := func(rel string, src []byte, edits []analyzer.Edit) error {
+		if *output == "" {
+			return nil
+		}
+		minified, err := analyzer.ApplyEdits(src, edits)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		path := filepath.Join(*output, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, minified, 0644)
+	}
+	saved := 0
+	written := make(map[string]bool)
+	for _, file := range ws.project.Files {
+		written[file.Path] = true
+		src, err := os.ReadFile(ws.project.AbsPath(file.Path))
+		if err != nil {
+			return err
+		}
+		edits := classMap.MarkupEdits(file)
+		saved += analyzer.EditsSaved(edits)
+		if err := write(file.Path, src, edits); err != nil {
+			return err
+		}
+	}
+	for _, sheet := range ws.project.Stylesheets {
+		// like purge, SCSS and Less sources and CSS modules stay as they are,
+		// the <style> blocks of markup went out with it
+		if written[sheet.Path] {
+			continue
+		}
+		var edits []analyzer.Edit
+		if filepath.Ext(sheet.Path) == ".css" && !sheet.IsModule() {
+			edits = classMap.StylesheetEdits(sheet)
+		}
+		saved += analyzer.EditsSaved(edits)
+		if err := write(sheet.Path, []byte(sheet.Source), edits); err != nil {
+			return err
+		}
+	}
+
+	if *output != "" || *manifest != "" {
+		path := *manifest
+		if path == "" {
+			path = filepath.Join(*output, "class-map.json")
+		}
+		src, err := json.MarshalIndent(classMap, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, append(src, '\n'), 0644); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(classMap)
+	}
+	if err := classMap.WriteReport(stdout, saved); err != nil {
+		return err
+	}
+	if *output == "" {
+		fmt.Fprintln(stdout, "\nnothing written, pass -o DIR to write the minified files and their manifest")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func runOrder(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("order", flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "sort the classes of the attributes in place, keeping their whitespace")
+	asJSON := fs.Bool("json", false, "print the findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	findings := analyzer.ClassOrder(ws.project, ws.parser, ws.catalog)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			return err
+		}
+	} else if err := analyzer.WriteFindings(stdout, findings); err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	if !*fix {
+		return fmt.Errorf("%d class attributes out of order", len(findings))
+	}
+
+	files := 0
+	for _, file := range ws.project.Files {
+		path := ws.project.AbsPath(file.Path)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		edits := analyzer.OrderEdits(file, src, ws.parser, ws.catalog)
+		if len(edits) == 0 {
+			continue
+		}
+		if err := rewriteFile(path, src, edits); err != nil {
+			return fmt.Errorf("%s: %w", file.Path, err)
+		}
+		files++
+	}
+	if !*asJSON {
+		fmt.Fprintf(stdout, "sorted %d class attributes in %d files\n", len(findings), files)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func runPurge(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	output := fs.String("o", "", "write the purged stylesheets to this directory, keeping their relative paths")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	purger, err := analyzer.NewPurger(ws.project.UsedClasses(), ws.config.Purge)
+	if err != nil {
+		return err
+	}
+	purger.Keep = ws.tailwind.Safelisted
+
+	var results []*analyzer.PurgeResult
+	for _, sheet := range ws.project.Stylesheets {
+		// SCSS and Less selectors are resolved, rewriting their source with
+		// them would flatten the nesting, purge the compiled CSS instead
+		if filepath.Ext(sheet.Path) != ".css" {
+			continue
+		}
+		result := purger.Purge(sheet)
+		results = append(results, result)
+		if *output == "" {
+			continue
+		}
+		path := filepath.Join(*output, sheet.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(result.CSS), 0644); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+	if err := analyzer.WritePurgeReport(stdout, results); err != nil {
+		return err
+	}
+	if *output == "" {
+		fmt.Fprintln(stdout, "\nnothing written, pass -o DIR to write the purged stylesheets")
+	}
+	return nil
+}
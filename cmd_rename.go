@@ -0,0 +1,85 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func runRename(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("rename", flag.ContinueOnError)
+	mapping := fs.String("map", "", "JSON file of rename rules, [{\"from\": \"btn-primary\", \"to\": \"btn btn-brand\"}, ...]")
+	dryRun := fs.Bool("dry-run", false, "print the changes as a diff instead of writing them")
+	asJSON := fs.Bool("json", false, "print the renamed classes as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *mapping == "" {
+		return errors.New("missing -map, the JSON file of rename rules")
+	}
+	rules, err := analyzer.LoadRenameRules(*mapping)
+	if err != nil {
+		return err
+	}
+	renamer, err := analyzer.NewRenamer(rules)
+	if err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	type fileRenames struct {
+		File    string             `json:"file"`
+		Renamed []analyzer.Renamed `json:"renamed"`
+	}
+	var changed []fileRenames
+	total := 0
+	for _, file := range ws.project.Files {
+		path := ws.project.AbsPath(file.Path)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		edits, renamed := analyzer.RenameEdits(file, src, renamer, ws.parser)
+		if len(renamed) == 0 {
+			continue
+		}
+		changed = append(changed, fileRenames{file.Path, renamed})
+		total += len(renamed)
+		if *dryRun {
+			if !*asJSON {
+				if err := analyzer.WriteDiff(stdout, file.Path, src, edits); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := rewriteFile(path, src, edits); err != nil {
+			return fmt.Errorf("%s: %w", file.Path, err)
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(changed)
+	}
+	if *dryRun && total > 0 {
+		fmt.Fprintln(stdout)
+	}
+	for _, c := range changed {
+		fmt.Fprintf(stdout, "%s: %d classes\n", c.File, len(c.Renamed))
+	}
+	verb := "renamed"
+	if *dryRun {
+		verb = "would rename"
+	}
+	fmt.Fprintf(stdout, "%s %d classes in %d files\n", verb, total, len(changed))
+	return nil
+}
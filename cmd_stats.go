@@ -0,0 +1,45 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+)
+
+func runStats(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	top := fs.Int("top", 10, "how many of the most and least used classes to list")
+	asJSON := fs.Bool("json", false, "print the full statistics as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *top < 0 {
+		return errors.New("-top can't be negative")
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	stats := analyzer.ComputeStats(ws.project)
+	rollups := analyzer.ComputeRollups(ws.project, ws.parser)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			*analyzer.Stats
+			MostUsed   []analyzer.ClassStats      `json:"mostUsed"`
+			LeastUsed  []analyzer.ClassStats      `json:"leastUsed"`
+			Singletons []analyzer.ClassStats      `json:"singletons"`
+			Histogram  []analyzer.HistogramBucket `json:"histogram"`
+			Rollups    *analyzer.Rollups          `json:"rollups"`
+		}{stats, stats.MostUsed(*top), stats.LeastUsed(*top), stats.Singletons(), stats.Histogram(), rollups})
+	}
+	if err := stats.WriteReport(stdout, *top); err != nil {
+		return err
+	}
+	return rollups.WriteReport(stdout, *top)
+}
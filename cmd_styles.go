@@ -0,0 +1,34 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+)
+
+func runStyles(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("styles", flag.ContinueOnError)
+	top := fs.Int("top", 10, "how many of the most common properties to list")
+	asJSON := fs.Bool("json", false, "print the inventory as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *top < 0 {
+		return errors.New("-top can't be negative")
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	inventory := analyzer.ComputeInlineStyles(ws.project)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(inventory)
+	}
+	return inventory.WriteReport(stdout, *top)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+func runUndefined(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("undefined", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the undefined classes as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	undefined := ws.validator().Undefined(ws.project)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(undefined); err != nil {
+			return err
+		}
+	} else if err := analyzer.WriteUndefined(stdout, undefined); err != nil {
+		return err
+	}
+	if len(undefined) > 0 {
+		return fmt.Errorf("%d undefined classes", len(undefined))
+	}
+	return nil
+}
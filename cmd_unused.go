@@ -0,0 +1,37 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+func runUnused(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("unused", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the unused definitions as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	unused := ws.project.UnusedClasses()
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(unused); err != nil {
+			return err
+		}
+	} else if err := analyzer.WriteUnused(stdout, unused); err != nil {
+		return err
+	}
+	if len(unused) > 0 {
+		return fmt.Errorf("%d unused class definitions", len(unused))
+	}
+	return nil
+}
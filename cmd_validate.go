@@ -0,0 +1,59 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// extendFlags collects repeated -extend key=value1,value2 flags
+type extendFlags map[string][]string
+
+func (e extendFlags) String() string {
+	return fmt.Sprint(map[string][]string(e))
+}
+
+func (e extendFlags) Set(s string) error {
+	key, values, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value1,value2, got %q", s)
+	}
+	e[key] = append(e[key], strings.Split(values, ",")...)
+	return nil
+}
+
+func runValidate(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	extend := extendFlags{}
+	fs.Var(extend, "extend", "extra theme values as key=value1,value2, e.g. colors=brand,brand-dark (repeatable)")
+	asJSON := fs.Bool("json", false, "print the findings as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ws, err := openWorkspace(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	for key, values := range extend {
+		ws.catalog.Extend(key, values...)
+	}
+	findings := ws.validator().Validate(ws.project)
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			return err
+		}
+	} else if err := analyzer.WriteFindings(stdout, findings); err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("%d unknown class usages", len(findings))
+	}
+	return nil
+}
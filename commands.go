@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// command is a CLI subcommand, run gets the arguments after the command name
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"stats", "per-class frequency and file-spread statistics", runStats},
+	{"validate", "flag classes that aren't Tailwind utilities or defined in project CSS", runValidate},
+	{"unused", "report classes project CSS defines but no markup uses", runUnused},
+	{"undefined", "report classes used in markup but defined nowhere, grouped by class", runUndefined},
+	{"purge", "drop the CSS rules no markup can match, PurgeCSS style", runPurge},
+	{"generate", "generate Tailwind-compatible CSS for exactly the classes used", runGenerate},
+	{"styles", "inventory of inline style attributes per element", runStyles},
+	{"coverage", "match every CSS rule against the HTML pages, rule by rule", runCoverage},
+	{"conflicts", "flag elements with two utilities setting the same property, like p-2 p-4", runConflicts},
+	{"duplicates", "flag classes repeated within one class attribute, -fix removes them", runDuplicates},
+	{"order", "flag class attributes not in canonical Tailwind order, -fix sorts them", runOrder},
+	{"shorthands", "flag utilities adding up to a shorthand like mx-2 my-2, -fix merges them", runShorthands},
+	{"rename", "rename or replace classes across the project by a mapping file, -dry-run shows a diff", runRename},
+	{"migrate", "report the classes Tailwind v4 renamed or changed, -write rewrites the safe ones", runMigrate},
+	{"bootstrap", "estimate moving off Bootstrap per page, -write rewrites the classes with exact Tailwind counterparts", runBootstrap},
+	{"minify", "give classes short names in markup and CSS, writing them and a manifest to -o DIR", runMinify},
+	{"components", "find class lists repeated across elements and propose @apply components, -write uses them", runComponents},
+	{"cooccurrence", "find the classes that go together on elements and cluster them into patterns, as JSON or Graphviz DOT", runCooccurrence},
+	{"complexity", "selector specificity and depth report, with optional limits to lint", runComplexity},
+}
+
+// runCommand dispatches to the subcommand named by args[0] and returns the
+// process exit code, without arguments main starts the web server instead
+func runCommand(args []string, stdout, stderr io.Writer) int {
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(args[1:], stdout)
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", cmd.name, err)
+			return 1
+		}
+		return 0
+	}
+
+	if args[0] != "help" && args[0] != "-h" && args[0] != "--help" {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+	}
+	fmt.Fprintln(stderr, "usage: css-class-analyzer <command> [flags] [dir]")
+	fmt.Fprintln(stderr, "\nwithout a command the web server is started\n\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(stderr, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		return 0
+	}
+	return 2
+}
+
+// dirArg returns the directory a command should work on, defaulting to the
+// current one
+func dirArg(fs *flag.FlagSet) string {
+	if fs.NArg() > 0 {
+		return fs.Arg(0)
+	}
+	return "."
+}
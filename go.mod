@@ -2,19 +2,21 @@ module css-class-analyzer
 
 go 1.21.3
 
-require golang.org/x/net v0.22.0
+require (
+	github.com/gofiber/fiber/v2 v2.52.2
+	github.com/google/uuid v1.5.0
+	github.com/gorilla/css v1.0.0
+	github.com/microcosm-cc/bluemonday v1.0.26
+	golang.org/x/net v0.22.0
+)
 
 require (
 	github.com/andybalholm/brotli v1.0.5 // indirect
 	github.com/aymerick/douceur v0.2.0 // indirect
-	github.com/gofiber/fiber/v2 v2.52.2 // indirect
-	github.com/google/uuid v1.5.0 // indirect
-	github.com/gorilla/css v1.0.0 // indirect
 	github.com/klauspost/compress v1.17.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mattn/go-runewidth v0.0.15 // indirect
-	github.com/microcosm-cc/bluemonday v1.0.26 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	github.com/valyala/fasthttp v1.51.0 // indirect
@@ -16,7 +16,9 @@ import (
 	"github.com/microcosm-cc/bluemonday"
 )
 
-func init() {
+// cleanDirs wipes the inputs and outputs directories the web server uses,
+// the CLI commands never touch them
+func cleanDirs() {
 	// clean wipe the inputs and outputs directories
 	err := os.RemoveAll("./inputs")
 	if err != nil {
@@ -41,6 +43,15 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:], os.Stdout, os.Stderr))
+	}
+	serve()
+}
+
+func serve() {
+	cleanDirs()
+
 	app := fiber.New()
 
 	app.Use(cors.New(cors.Config{
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"log"
+	"os"
+)
+
+// workspace is what most commands start from: the scanned project and the
+// parser and catalog matching its Tailwind setup
+type workspace struct {
+	project  *analyzer.Project
+	tailwind *analyzer.TailwindConfig
+	parser   *analyzer.ClassParser
+	catalog  *analyzer.Catalog
+	config   *analyzer.Config
+}
+
+// openWorkspace reads the Tailwind config in dir if there is one, v3 file or
+// v4 entry stylesheet, and scans the files its sources point at, warnings
+// about config that can't be evaluated statically are logged and otherwise
+// ignored
+func openWorkspace(dir string) (*workspace, error) {
+	ws, err := configureWorkspace(dir)
+	if err != nil {
+		return nil, err
+	}
+	opts := analyzer.ScanOptions{}
+	if ws.tailwind != nil {
+		opts.Include, opts.Exclude, opts.Roots = ws.tailwind.Content, ws.tailwind.Exclude, ws.tailwind.Roots
+	}
+	ws.project, err = analyzer.ScanWith(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
+
+// configureWorkspace is openWorkspace without the scan, for commands that
+// get their classes from somewhere else
+func configureWorkspace(dir string) (*workspace, error) {
+	ws := &workspace{catalog: analyzer.NewCatalog()}
+	config, err := analyzer.LoadConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	ws.config = config
+	tailwind, err := analyzer.LoadTailwindConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	if tailwind != nil {
+		for _, warning := range tailwind.Warnings {
+			log.Printf("warning: %s\n", warning)
+		}
+	}
+	ws.tailwind = tailwind
+	ws.parser = tailwind.Parser()
+	tailwind.ExtendCatalog(ws.catalog)
+	return ws, nil
+}
+
+// validator checks classes against the catalog, the project's own CSS and
+// the allowlist
+func (ws *workspace) validator() *analyzer.Validator {
+	return &analyzer.Validator{
+		Parser:  ws.parser,
+		Catalog: ws.catalog,
+		Defined: ws.project.DefinedClasses(),
+		Config:  ws.config,
+	}
+}
+
+// rewriteFile applies edits to src, the contents of the file at path, and
+// writes the result back, the file keeps its mode
+func rewriteFile(path string, src []byte, edits []analyzer.Edit) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	rewritten, err := analyzer.ApplyEdits(src, edits)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, rewritten, info.Mode().Perm())
+}
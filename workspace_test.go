@@ -0,0 +1,38 @@
+package main
+
+import (
+	"css-class-analyzer/analyzer"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewriteFileKeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.cgi")
+	src := []byte(`<p class="a a">Hi</p>`)
+	if err := os.WriteFile(path, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the umask doesn't get a say in Chmod
+	if err := os.Chmod(path, 0775); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rewriteFile(path, src, []analyzer.Edit{{Start: 10, End: 12}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `<p class="a">Hi</p>` {
+		t.Errorf("Expected the edit to be made, got %s", got)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0775 {
+		t.Errorf("Expected the file to keep its mode 0775, got %o", info.Mode().Perm())
+	}
+}